@@ -1,40 +1,179 @@
 package main
 
 import (
+	"context"
 	"crypto-project-1/internal/app"
+	"crypto-project-1/internal/config"
 	"crypto-project-1/internal/domain"
-	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/health"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/metrics"
 	"crypto-project-1/internal/service"
-	logger "github.com/sirupsen/logrus"
+	"crypto-project-1/internal/tracing"
+	"crypto-project-1/internal/worker"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 )
 
+// exit codes of the process
 const (
-	port = "7777"
+	exitOK              = 0
+	exitBootFailure     = 1
+	exitServerFailure   = 2
+	exitUncleanShutdown = 3
 )
 
 func main() {
-	logger.Info("crypto api starting...")
+	os.Exit(run())
+}
+
+func run() int {
+	// the default settings are valid, the logger of the loaded config replaces this one
+	log, _ := logging.New(config.Default().Log, os.Stderr)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		return runMigrate(log, os.Args[2:], os.Stdout)
+	}
+	log.Info("crypto api starting...")
 
-	db, err := repository.NewDB()
+	cfg, err := config.Load(os.Args[1:])
 	if err != nil {
-		logger.Error(domain.CryptoAPIError, domain.BootError, "could not connect to db ", err)
-		return
+		log.WithField(logging.FieldErrorCode, domain.BootError).WithError(err).Error("could not load config")
+		return exitBootFailure
 	}
-	err = db.Ping()
+	configuredLog, err := logging.New(cfg.Log, os.Stderr)
 	if err != nil {
-		logger.Error(domain.CryptoAPIError, domain.BootError, "cannot ping db ", err)
+		log.WithField(logging.FieldErrorCode, domain.BootError).WithError(err).Error("could not create logger")
+		return exitBootFailure
+	}
+	log = configuredLog
+
+	tracerProvider, err := tracing.NewProvider(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.WithField(logging.FieldErrorCode, domain.BootError).WithError(err).Error("could not create tracer provider")
+		return exitBootFailure
 	}
 
 	// initialize dependencies
-	repo := repository.NewRepository(&repository.ChallengeDbRepository{})
-	microservice := app.NewCryptoMicroservice(service.NewChallengeService(repo, time.Now))
+	appMetrics := metrics.New()
+	store, err := openStorage(cfg, appMetrics, log)
+	if err != nil {
+		log.WithField(logging.FieldErrorCode, domain.BootError).WithError(err).Error("could not open storage")
+		return exitBootFailure
+	}
+	// the other backends expire the challenges themselves or keep them on purpose
+	var backgroundWorkers []worker.Worker
+	if len(store.sweepers) > 0 {
+		backgroundWorkers = append(backgroundWorkers, worker.NewExpirySweeper(cfg.Challenge.SweepInterval, cfg.Challenge.ExpiredRetention, time.Now, log, store.sweepers...))
+	}
+	workers := worker.NewGroup(log, backgroundWorkers...)
+	healthChecker := health.NewChecker(cfg.Health, time.Now, append(store.checks, health.WorkersCheck(workers))...)
+	challengeService := metrics.NewChallengeService(service.NewChallengeService(store.repo, time.Now, cfg.Challenge, cfg.Tenancy, service.NewNonceGenerators(), log), appMetrics)
+	apiKeyService := service.NewAPIKeyService(store.repo, time.Now, cfg.Auth, cfg.Tenancy, log)
+	adminService := service.NewAdminService(store.repo, time.Now, cfg.Tenancy, log)
+	microservice := app.NewCryptoMicroservice(challengeService, apiKeyService, adminService, healthChecker, log)
 
 	// create routes
-	httpServer := app.NewServer(microservice)
-	// start http server
-	if err := httpServer.Start(":" + port); err != nil {
-		logger.Error(domain.CryptoAPIError, domain.BootError, "cannot start http server ", err)
-		panic(err)
+	httpServer, err := app.NewServer(microservice, cfg.HTTP, cfg.Auth, appMetrics)
+	if err != nil {
+		log.WithField(logging.FieldErrorCode, domain.BootError).WithError(err).Error("cannot create http server")
+		_ = store.close()
+		return exitBootFailure
+	}
+	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.Port))
+	if err != nil {
+		log.WithField(logging.FieldErrorCode, domain.BootError).WithError(err).Error("cannot listen on grpc port")
+		_ = store.close()
+		return exitBootFailure
+	}
+	grpcServer := app.NewGRPCServer(microservice, cfg.GRPC, cfg.Auth)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	workers.Start(ctx)
+
+	// start http and grpc servers
+	serverErrors := make(chan error, 2)
+	go func() {
+		if err := httpServer.Start(fmt.Sprintf(":%d", cfg.HTTP.Port)); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErrors <- fmt.Errorf("http server: %w", err)
+		}
+	}()
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			serverErrors <- fmt.Errorf("grpc server: %w", err)
+		}
+	}()
+
+	exitCode := exitOK
+	select {
+	case <-ctx.Done():
+		log.Info("crypto api received shutdown signal")
+	case err := <-serverErrors:
+		log.WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error("server stopped unexpectedly")
+		exitCode = exitServerFailure
 	}
+	stop()
+
+	if !shutdown(log, cfg.ShutdownTimeout, httpServer, grpcServer, workers, store, tracerProvider) && exitCode == exitOK {
+		exitCode = exitUncleanShutdown
+	}
+	log.Info("crypto api stopped")
+
+	return exitCode
+}
+
+// shutdown stops accepting requests, waits for in-flight ones and for the
+// workers until the timeout, then closes the storage and flushes the pending
+// spans. It reports whether everything stopped in time.
+func shutdown(log *logrus.Entry, timeout time.Duration, httpServer *echo.Echo, grpcServer *grpc.Server, workers *worker.Group, store *storage, tracerProvider *sdktrace.TracerProvider) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	clean := true
+
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+
+	if err := httpServer.Shutdown(ctx); err != nil {
+		log.WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error("http server did not shut down cleanly")
+		clean = false
+	}
+
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		log.WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(ctx.Err()).Error("grpc server did not shut down in time")
+		grpcServer.Stop()
+		clean = false
+	}
+
+	if err := workers.Stop(ctx); err != nil {
+		log.WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error("workers did not stop in time")
+		clean = false
+	}
+
+	if err := store.close(); err != nil {
+		log.WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error("could not close storage")
+		clean = false
+	}
+
+	if err := tracerProvider.Shutdown(ctx); err != nil {
+		log.WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error("could not flush spans")
+		clean = false
+	}
+
+	return clean
 }
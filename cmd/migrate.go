@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/database"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/migrate"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+)
+
+const migrateUsage = "usage: crypto-api migrate up|down [steps]|status [flags]"
+
+// runMigrate runs the migrate subcommand: up applies the pending migrations,
+// down reverts the last steps ones (1 by default) and status lists them.
+func runMigrate(log *logrus.Entry, args []string, out io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return exitBootFailure
+	}
+	command, args := args[0], args[1:]
+
+	steps := 1
+	if command == "down" && len(args) > 0 {
+		if n, err := strconv.Atoi(args[0]); err == nil {
+			steps, args = n, args[1:]
+		}
+	}
+	if steps < 1 {
+		fmt.Fprintln(os.Stderr, "steps must be positive")
+		return exitBootFailure
+	}
+
+	cfg, err := config.Load(args)
+	if err != nil {
+		log.WithField(logging.FieldErrorCode, domain.BootError).WithError(err).Error("could not load config")
+		return exitBootFailure
+	}
+	if cfg.Storage.Backend != config.StorageDatabase {
+		fmt.Fprintln(os.Stderr, "migrations only apply to the database storage backend")
+		return exitBootFailure
+	}
+	db, err := database.Open(cfg.Database)
+	if err != nil {
+		log.WithField(logging.FieldErrorCode, domain.BootError).WithError(err).Error("could not connect to db")
+		return exitBootFailure
+	}
+	defer db.Close()
+
+	migrations, err := migrate.Load(db.Dialect)
+	if err != nil {
+		log.WithField(logging.FieldErrorCode, domain.BootError).WithError(err).Error("could not load migrations")
+		return exitBootFailure
+	}
+	migrator := migrate.New(db, migrations, log)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	switch command {
+	case "up":
+		_, err = migrator.Up(ctx)
+	case "down":
+		_, err = migrator.Down(ctx, steps)
+	case "status":
+		var statuses []migrate.MigrationStatus
+		statuses, err = migrator.Status(ctx)
+		if err == nil {
+			printStatus(out, statuses)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return exitBootFailure
+	}
+	if err != nil {
+		log.WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error("migrate ", command, " failed")
+		return exitBootFailure
+	}
+
+	return exitOK
+}
+
+func printStatus(out io.Writer, statuses []migrate.MigrationStatus) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+	_ = w.Flush()
+}
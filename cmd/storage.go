@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/database"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/health"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/metrics"
+	"crypto-project-1/internal/migrate"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/worker"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// storage is the backend the challenges are kept in, with the readiness
+// checks of its dependencies and the stores the expiry sweeper sweeps.
+type storage struct {
+	repo     *repository.Repository
+	checks   []health.Check
+	sweepers []worker.Sweeper
+	close    func() error
+}
+
+// openStorage opens the storage backend selected by the configuration.
+func openStorage(cfg *config.Config, appMetrics *metrics.Metrics, log *logrus.Entry) (*storage, error) {
+	store, err := openBackend(cfg, appMetrics, log)
+	if err != nil || cfg.Challenge.Mode != config.ChallengeModeSealed {
+		return store, err
+	}
+
+	return sealChallenges(store, cfg.Challenge, appMetrics, log)
+}
+
+func openBackend(cfg *config.Config, appMetrics *metrics.Metrics, log *logrus.Entry) (*storage, error) {
+	switch cfg.Storage.Backend {
+	case config.StorageMemory:
+		log.Warn("challenges, api keys and statistics are kept in memory, they are lost when the api stops")
+		challenges := repository.NewChallengeMemoryRepository()
+		multisig := repository.NewMultisigMemoryRepository()
+		return &storage{
+			repo: repository.NewRepository(repository.NoTx,
+				metrics.NewChallengeRepository(challenges, appMetrics),
+				metrics.NewAPIKeyRepository(repository.NewAPIKeyMemoryRepository(), appMetrics),
+				metrics.NewChallengeStatsRepository(repository.NewChallengeStatsMemoryRepository(), appMetrics),
+				metrics.NewMultisigRepository(multisig, appMetrics),
+				metrics.NewExtendedKeyRepository(repository.NewExtendedKeyMemoryRepository(), appMetrics)),
+			sweepers: []worker.Sweeper{challenges, multisig},
+			close:    func() error { return nil },
+		}, nil
+	case config.StorageRedis:
+		client := repository.NewRedisClient(cfg.Redis)
+		return &storage{
+			repo: repository.NewRepository(repository.NoTx,
+				metrics.NewChallengeRepository(repository.NewChallengeRedisRepository(client, cfg.Redis, log), appMetrics),
+				metrics.NewAPIKeyRepository(repository.NewAPIKeyRedisRepository(client, cfg.Redis, log), appMetrics),
+				metrics.NewChallengeStatsRepository(repository.NewChallengeStatsRedisRepository(client, cfg.Redis, log), appMetrics),
+				metrics.NewMultisigRepository(repository.NewMultisigRedisRepository(client, cfg.Redis, log), appMetrics),
+				metrics.NewExtendedKeyRepository(repository.NewExtendedKeyRedisRepository(client, cfg.Redis, log), appMetrics)),
+			checks: []health.Check{health.RedisCheck(client)},
+			close:  client.Close,
+		}, nil
+	default:
+		return openDatabase(cfg.Database, appMetrics, log)
+	}
+}
+
+// sealChallenges replaces the challenges of the backend by challenges sealed
+// into their nonce, so that neither creating nor verifying one reaches the
+// backend. Their statistics are kept in memory for the same reason.
+// Multi-signature challenges record their signers, they stay in the backend
+// with the enrolled extended keys.
+func sealChallenges(store *storage, cfg config.ChallengeConfig, appMetrics *metrics.Metrics, log *logrus.Entry) (*storage, error) {
+	sealed, err := repository.NewChallengeSealedRepository(cfg, time.Now)
+	if err != nil {
+		_ = store.close()
+		return nil, err
+	}
+	log.Info("challenges are sealed into their nonce, their statistics are kept in memory")
+	store.repo = repository.NewRepository(store.repo.UnitOfWork,
+		metrics.NewChallengeRepository(sealed, appMetrics),
+		store.repo.APIKeyRepo,
+		metrics.NewChallengeStatsRepository(repository.NewChallengeStatsMemoryRepository(), appMetrics),
+		store.repo.MultisigRepo,
+		store.repo.ExtendedKeyRepo)
+	sweepers := []worker.Sweeper{sealed}
+	for _, sweeper := range store.sweepers {
+		// the challenges of the memory backend, if any, are replaced
+		if _, replaced := sweeper.(*repository.ChallengeMemoryRepository); !replaced {
+			sweepers = append(sweepers, sweeper)
+		}
+	}
+	store.sweepers = sweepers
+
+	return store, nil
+}
+
+func openDatabase(cfg config.DatabaseConfig, appMetrics *metrics.Metrics, log *logrus.Entry) (*storage, error) {
+	db, err := database.Open(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to db: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		// keep booting, the readiness endpoint reports the database as unavailable until it can be reached
+		log.WithField(logging.FieldErrorCode, domain.BootError).WithError(err).Error("cannot ping db")
+	}
+
+	migrator, err := openMigrator(db, cfg, log)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	if err := appMetrics.RegisterDB(db.DB, databaseName(cfg, db.Dialect)); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("cannot register db metrics: %w", err)
+	}
+
+	return &storage{
+		repo: repository.NewRepository(repository.NewTransactor(db),
+			metrics.NewChallengeRepository(repository.NewChallengeDbRepository(db, cfg, log), appMetrics),
+			metrics.NewAPIKeyRepository(repository.NewAPIKeyDbRepository(db, cfg, log), appMetrics),
+			metrics.NewChallengeStatsRepository(repository.NewChallengeStatsDbRepository(db, cfg, log), appMetrics),
+			metrics.NewMultisigRepository(repository.NewMultisigDbRepository(db, cfg, log), appMetrics),
+			metrics.NewExtendedKeyRepository(repository.NewExtendedKeyDbRepository(db, cfg, log), appMetrics)),
+		checks: []health.Check{
+			health.DatabaseCheck(db.DB),
+			health.SchemaCheck(migrator),
+		},
+		close: db.Close,
+	}, nil
+}
+
+func openMigrator(db *database.DB, cfg config.DatabaseConfig, log *logrus.Entry) (*migrate.Migrator, error) {
+	migrations, err := migrate.Load(db.Dialect)
+	if err != nil {
+		return nil, fmt.Errorf("could not load migrations: %w", err)
+	}
+	migrator := migrate.New(db, migrations, log)
+	if cfg.MigrateOnStart {
+		// exit rather than serve with an outdated schema, the instance is restarted until the migrations pass
+		if _, err := migrator.Up(context.Background()); err != nil {
+			return nil, fmt.Errorf("could not migrate db: %w", err)
+		}
+	}
+
+	return migrator, nil
+}
+
+// databaseName labels the metrics of the connection pool.
+func databaseName(cfg config.DatabaseConfig, dialect database.Dialect) string {
+	if cfg.URL != "" {
+		return string(dialect)
+	}
+
+	return cfg.Name
+}
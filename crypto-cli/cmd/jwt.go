@@ -14,7 +14,10 @@ import (
 	"time"
 )
 
+var audience string
+
 func init() {
+	jwtCmd.Flags().StringVar(&audience, "audience", defaultAudience, "audience of the token, one the tenant of the challenge allows")
 	rootCmd.AddCommand(jwtCmd)
 }
 
@@ -22,7 +25,7 @@ const (
 	publicKeyFile    = "public_key.pem"
 	privateKeyFile   = "private_key.pem"
 	tokenTimeToLeave = time.Minute * 5
-	audience         = "wheltee"
+	defaultAudience  = "wheltee"
 )
 
 var jwtCmd = &cobra.Command{
@@ -0,0 +1,154 @@
+package app
+
+import (
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/public"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// registerAdminRoutes serves the admin API under /admin/v1. It always requires
+// an API key: the audit role reads, the admin role reads and manages.
+func (m *CryptoMicroservice) registerAdminRoutes(e *echo.Echo) {
+	admin := e.Group("/admin/v1")
+	canRead := m.requireScope(domain.ScopeAdmin, domain.ScopeAudit)
+	canManage := m.requireScope(domain.ScopeAdmin)
+
+	admin.GET("/challenges", m.SearchChallenges, canRead)
+	admin.POST("/challenges/:nonce/expire", m.ExpireChallenge, canManage)
+	admin.GET("/stats", m.GetChallengeStats, canRead)
+	admin.GET("/api-keys", m.ListAPIKeys, canRead)
+	admin.GET("/api-keys/:id", m.GetAPIKey, canRead)
+	admin.POST("/api-keys", m.CreateAPIKey, canManage)
+	admin.POST("/api-keys/:id/rotate", m.RotateAPIKey, canManage)
+	admin.DELETE("/api-keys/:id", m.RevokeAPIKey, canManage)
+}
+
+// adminStatus returns the status of an error of the admin service.
+func adminStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrChallengeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidFilter), isTenantError(err):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GET admin/v1/challenges
+func (m *CryptoMicroservice) SearchChallenges(ctx echo.Context) error {
+	filter := domain.ChallengeFilter{
+		KeyFingerprint: ctx.QueryParam("fingerprint"),
+		Nonce:          ctx.QueryParam("nonce"),
+		Status:         ctx.QueryParam("status"),
+	}
+	limit, err := int64Param(ctx, "limit")
+	filter.Limit = int(limit)
+	if err == nil {
+		filter.CreatedFrom, err = int64Param(ctx, "from")
+	}
+	if err == nil {
+		filter.CreatedTo, err = int64Param(ctx, "to")
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Code:    public.ChallengeSearchFailed,
+			Message: err.Error(),
+		})
+	}
+
+	page, err := m.adminService.SearchChallenges(ctx.Request().Context(), httpTenant(ctx), filter, ctx.QueryParam("cursor"))
+	if status := adminStatus(err); status != http.StatusInternalServerError {
+		return ctx.JSON(status, public.ApiResponse{
+			Code:    public.ChallengeSearchFailed,
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, public.ApiResponse{
+			Code:    public.ChallengeSearchFailed,
+			Message: "error while trying to search challenges",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, public.ApiResponse{
+		Result:  page,
+		Code:    public.ChallengeSearchSucceeded,
+		Message: "successfully searched challenges",
+	})
+}
+
+// POST admin/v1/challenges/:nonce/expire
+func (m *CryptoMicroservice) ExpireChallenge(ctx echo.Context) error {
+	err := m.adminService.ExpireChallenge(ctx.Request().Context(), httpTenant(ctx), ctx.Param("nonce"))
+	if status := adminStatus(err); status != http.StatusInternalServerError {
+		return ctx.JSON(status, public.ApiResponse{
+			Code:    public.ChallengeExpireFailed,
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, public.ApiResponse{
+			Code:    public.ChallengeExpireFailed,
+			Message: "error while trying to expire challenge",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, public.ApiResponse{
+		Code:    public.ChallengeExpireSucceeded,
+		Message: "successfully expired challenge",
+	})
+}
+
+// GET admin/v1/stats
+func (m *CryptoMicroservice) GetChallengeStats(ctx echo.Context) error {
+	from, err := int64Param(ctx, "from")
+	to := int64(0)
+	if err == nil {
+		to, err = int64Param(ctx, "to")
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Code:    public.ChallengeStatsFailed,
+			Message: err.Error(),
+		})
+	}
+
+	stats, err := m.adminService.GetStats(ctx.Request().Context(), httpTenant(ctx), from, to)
+	if status := adminStatus(err); status != http.StatusInternalServerError {
+		return ctx.JSON(status, public.ApiResponse{
+			Code:    public.ChallengeStatsFailed,
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, public.ApiResponse{
+			Code:    public.ChallengeStatsFailed,
+			Message: "error while trying to get challenge stats",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, public.ApiResponse{
+		Result:  stats,
+		Code:    public.ChallengeStatsSucceeded,
+		Message: "successfully got challenge stats",
+	})
+}
+
+// int64Param returns the integer of a query parameter, zero when it is absent.
+func int64Param(ctx echo.Context, name string) (int64, error) {
+	value := ctx.QueryParam(name)
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid request: " + name + " must be an integer")
+	}
+
+	return parsed, nil
+}
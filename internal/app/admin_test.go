@@ -0,0 +1,79 @@
+package app_test
+
+import (
+	"context"
+	"crypto-project-1/internal/app"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/metrics"
+	"crypto-project-1/public"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_AdminChallenges(t *testing.T) {
+	microservice, apiKeyService, cfg := newAuthMicroservice()
+	e, err := app.NewServer(microservice, cfg.HTTP, cfg.Auth, metrics.New())
+	require.NoError(t, err)
+	createKey, err := apiKeyService.CreateAPIKey(context.Background(), "shop", "create", []string{domain.ScopeCreate})
+	require.NoError(t, err)
+	auditKey, err := apiKeyService.CreateAPIKey(context.Background(), "shop", "audit", []string{domain.ScopeAudit})
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		status, _ := serve(t, e, http.MethodPost, "/v1/challenge", createKey.Key, "", public.CreateChallengeRequestBody{PubKey: testPublicKey})
+		require.Equal(t, http.StatusOK, status)
+	}
+
+	// the audit role pages through the challenges
+	status, response := serve(t, e, http.MethodGet, "/admin/v1/challenges?limit=1", auditKey.Key, "", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, public.ChallengeSearchSucceeded, response.Code)
+	page := response.Result.(map[string]interface{})
+	require.Len(t, page["challenges"], 1)
+	first := page["challenges"].([]interface{})[0].(map[string]interface{})
+	require.NotEmpty(t, page["nextCursor"])
+
+	status, response = serve(t, e, http.MethodGet, "/admin/v1/challenges?limit=1&cursor="+page["nextCursor"].(string), auditKey.Key, "", nil)
+	require.Equal(t, http.StatusOK, status)
+	page = response.Result.(map[string]interface{})
+	require.Len(t, page["challenges"], 1)
+	assert.NotContains(t, page, "nextCursor")
+	assert.NotEqual(t, first["nonce"], page["challenges"].([]interface{})[0].(map[string]interface{})["nonce"])
+
+	status, _ = serve(t, e, http.MethodGet, "/admin/v1/challenges?cursor=malformed", auditKey.Key, "", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// the audit role can not expire a challenge, the admin role can
+	nonce := first["nonce"].(string)
+	status, response = serve(t, e, http.MethodPost, "/admin/v1/challenges/"+nonce+"/expire", auditKey.Key, "", nil)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, public.PermissionDenied, response.Code)
+
+	status, response = serve(t, e, http.MethodPost, "/admin/v1/challenges/"+nonce+"/expire", testAdminKey, "shop", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, public.ChallengeExpireSucceeded, response.Code)
+
+	status, _ = serve(t, e, http.MethodPost, "/admin/v1/challenges/"+nonce+"/expire", testAdminKey, "bank", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, response = serve(t, e, http.MethodGet, "/admin/v1/challenges?status=pending", auditKey.Key, "", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, response.Result.(map[string]interface{})["challenges"], 1)
+
+	status, response = serve(t, e, http.MethodGet, "/admin/v1/stats", auditKey.Key, "", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, public.ChallengeStatsSucceeded, response.Code)
+	var created float64
+	for _, hourly := range response.Result.([]interface{}) {
+		created += hourly.(map[string]interface{})["created"].(float64)
+	}
+	assert.Equal(t, float64(2), created)
+
+	// the audit role reads the API keys but does not manage them
+	status, _ = serve(t, e, http.MethodGet, "/admin/v1/api-keys/"+createKey.ID, auditKey.Key, "", nil)
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = serve(t, e, http.MethodDelete, "/admin/v1/api-keys/"+createKey.ID, auditKey.Key, "", nil)
+	assert.Equal(t, http.StatusForbidden, status)
+}
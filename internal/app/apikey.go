@@ -0,0 +1,164 @@
+package app
+
+import (
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/public"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// apiKeyStatus returns the status of an error of the API key service.
+func apiKeyStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrAPIKeyNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidScope), isTenantError(err):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// POST admin/v1/api-keys
+func (m *CryptoMicroservice) CreateAPIKey(ctx echo.Context) error {
+	requestBody, err := ioutil.ReadAll(ctx.Request().Body)
+	if err != nil {
+		m.logError(ctx.Request().Context(), "could not read request", err)
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Code:    public.ApiKeyCreateFailed,
+			Message: "could not read request body",
+		})
+	}
+	defer func() {
+		if err := ctx.Request().Body.Close(); err != nil {
+			m.logError(ctx.Request().Context(), "could not close request body", err)
+			return
+		}
+	}()
+	request := &public.CreateApiKeyRequestBody{}
+	if err := json.Unmarshal(requestBody, request); err != nil {
+		m.logError(ctx.Request().Context(), "could not unmarshal request", err)
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Code:    public.ApiKeyCreateFailed,
+			Message: "invalid request body",
+		})
+	}
+
+	issued, err := m.apiKeyService.CreateAPIKey(ctx.Request().Context(), httpTenant(ctx), request.Name, request.Scopes)
+	if status := apiKeyStatus(err); status != http.StatusInternalServerError {
+		return ctx.JSON(status, public.ApiResponse{
+			Code:    public.ApiKeyCreateFailed,
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, public.ApiResponse{
+			Code:    public.ApiKeyCreateFailed,
+			Message: "error while trying to create api key",
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, public.ApiResponse{
+		Result:  issued,
+		Code:    public.ApiKeyCreateSucceeded,
+		Message: "successfully created api key",
+	})
+}
+
+// GET admin/v1/api-keys
+func (m *CryptoMicroservice) ListAPIKeys(ctx echo.Context) error {
+	keys, err := m.apiKeyService.ListAPIKeys(ctx.Request().Context(), httpTenant(ctx))
+	if isTenantError(err) {
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Code:    public.ApiKeyListFailed,
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, public.ApiResponse{
+			Code:    public.ApiKeyListFailed,
+			Message: "error while trying to list api keys",
+		})
+	}
+	if keys == nil {
+		keys = []*domain.APIKey{}
+	}
+
+	return ctx.JSON(http.StatusOK, public.ApiResponse{
+		Result:  keys,
+		Code:    public.ApiKeyListSucceeded,
+		Message: "successfully listed api keys",
+	})
+}
+
+// GET admin/v1/api-keys/:id
+func (m *CryptoMicroservice) GetAPIKey(ctx echo.Context) error {
+	key, err := m.apiKeyService.GetAPIKey(ctx.Request().Context(), httpTenant(ctx), ctx.Param("id"))
+	if status := apiKeyStatus(err); status != http.StatusInternalServerError {
+		return ctx.JSON(status, public.ApiResponse{
+			Code:    public.ApiKeyGetFailed,
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, public.ApiResponse{
+			Code:    public.ApiKeyGetFailed,
+			Message: "error while trying to get api key",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, public.ApiResponse{
+		Result:  key,
+		Code:    public.ApiKeyGetSucceeded,
+		Message: "successfully got api key",
+	})
+}
+
+// POST admin/v1/api-keys/:id/rotate
+func (m *CryptoMicroservice) RotateAPIKey(ctx echo.Context) error {
+	issued, err := m.apiKeyService.RotateAPIKey(ctx.Request().Context(), httpTenant(ctx), ctx.Param("id"))
+	if status := apiKeyStatus(err); status != http.StatusInternalServerError {
+		return ctx.JSON(status, public.ApiResponse{
+			Code:    public.ApiKeyRotateFailed,
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, public.ApiResponse{
+			Code:    public.ApiKeyRotateFailed,
+			Message: "error while trying to rotate api key",
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, public.ApiResponse{
+		Result:  issued,
+		Code:    public.ApiKeyRotateSucceeded,
+		Message: "successfully rotated api key",
+	})
+}
+
+// DELETE admin/v1/api-keys/:id
+func (m *CryptoMicroservice) RevokeAPIKey(ctx echo.Context) error {
+	err := m.apiKeyService.RevokeAPIKey(ctx.Request().Context(), httpTenant(ctx), ctx.Param("id"))
+	if status := apiKeyStatus(err); status != http.StatusInternalServerError {
+		return ctx.JSON(status, public.ApiResponse{
+			Code:    public.ApiKeyRevokeFailed,
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, public.ApiResponse{
+			Code:    public.ApiKeyRevokeFailed,
+			Message: "error while trying to revoke api key",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, public.ApiResponse{
+		Code:    public.ApiKeyRevokeSucceeded,
+		Message: "successfully revoked api key",
+	})
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/public"
+	challengev1 "crypto-project-1/public/proto/challenge/v1"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	bearerPrefix = "Bearer "
+	// authorizationMetadataKey carries the API key of a gRPC call, like the HTTP header
+	authorizationMetadataKey = "authorization"
+)
+
+// scopes of the gRPC methods that require an API key
+var grpcScopes = map[string]string{
+	challengev1.ChallengeService_CreateChallenge_FullMethodName:       domain.ScopeCreate,
+	challengev1.ChallengeService_VerifyChallenge_FullMethodName:       domain.ScopeVerify,
+	challengev1.ChallengeService_SignMultisigChallenge_FullMethodName: domain.ScopeVerify,
+	challengev1.ChallengeService_VerifyFrostSignature_FullMethodName:  domain.ScopeVerify,
+	challengev1.ChallengeService_EnrollExtendedKey_FullMethodName:     domain.ScopeCreate,
+	challengev1.ChallengeService_WatchChallenge_FullMethodName:        domain.ScopeCreate,
+}
+
+var (
+	errMissingAPIKey = errors.New("api key is required")
+	errScope         = errors.New("api key is not granted the scope")
+	errOtherTenant   = errors.New("api key belongs to another tenant")
+)
+
+type apiKeyContextKey struct{}
+
+// requireScope rejects the requests that do not carry an API key granted one
+// of scopes, in the Authorization header as a bearer token. The key of the
+// request is kept in its context.
+func (m *CryptoMicroservice) requireScope(scopes ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			request := ctx.Request()
+			key, err := m.authorize(request.Context(), request.Header.Get(echo.HeaderAuthorization), request.Header.Get(TenantHeader), scopes...)
+			switch {
+			case errors.Is(err, errMissingAPIKey), errors.Is(err, domain.ErrInvalidAPIKey):
+				logging.FromContext(request.Context(), m.log).WithError(err).Info("request not authenticated")
+				return ctx.JSON(http.StatusUnauthorized, public.ApiResponse{
+					Code:    public.AuthenticationFailed,
+					Message: err.Error(),
+				})
+			case errors.Is(err, errScope), errors.Is(err, errOtherTenant):
+				logging.FromContext(request.Context(), m.log).WithField(logging.FieldAPIKey, key.ID).WithError(err).Info("request not authorized")
+				return ctx.JSON(http.StatusForbidden, public.ApiResponse{
+					Code:    public.PermissionDenied,
+					Message: err.Error(),
+				})
+			case err != nil:
+				m.logError(request.Context(), "failed to authenticate request", err)
+				return ctx.JSON(http.StatusInternalServerError, public.ApiResponse{
+					Code:    public.AuthenticationFailed,
+					Message: "error while trying to authenticate request",
+				})
+			}
+
+			ctx.SetRequest(request.WithContext(context.WithValue(request.Context(), apiKeyContextKey{}, key)))
+			return next(ctx)
+		}
+	}
+}
+
+// authorize returns the API key of an Authorization value, provided it is
+// granted one of scopes and belongs to the requested tenant. The key is
+// returned with errScope and errOtherTenant.
+func (m *CryptoMicroservice) authorize(ctx context.Context, authorization, requestedTenant string, scopes ...string) (*domain.APIKey, error) {
+	if !strings.HasPrefix(authorization, bearerPrefix) {
+		return nil, errMissingAPIKey
+	}
+	key, err := m.apiKeyService.Authenticate(ctx, strings.TrimPrefix(authorization, bearerPrefix))
+	if err != nil {
+		return nil, err
+	}
+	if !key.HasScope(scopes...) {
+		return key, errScope
+	}
+	if key.TenantID != "" && requestedTenant != "" && requestedTenant != key.TenantID {
+		return key, errOtherTenant
+	}
+
+	return key, nil
+}
+
+// requestTenant returns the tenant a request is served for: the tenant of its
+// API key, or the tenant it names.
+func requestTenant(ctx context.Context, requested string) string {
+	if key, found := ctx.Value(apiKeyContextKey{}).(*domain.APIKey); found && key.TenantID != "" {
+		return key.TenantID
+	}
+
+	return requested
+}
+
+// UnaryAuthInterceptor authenticates the calls of the methods that require an
+// API key, passed in the authorization metadata as a bearer token.
+func (m *CryptoMicroservice) UnaryAuthInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := m.authorizeGRPC(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, request)
+	}
+}
+
+// StreamAuthInterceptor is the UnaryAuthInterceptor of streaming calls.
+func (m *CryptoMicroservice) StreamAuthInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := m.authorizeGRPC(stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func (m *CryptoMicroservice) authorizeGRPC(ctx context.Context, method string) (context.Context, error) {
+	scope, found := grpcScopes[method]
+	if !found {
+		return ctx, nil
+	}
+
+	var authorization string
+	if values := metadata.ValueFromIncomingContext(ctx, authorizationMetadataKey); len(values) > 0 {
+		authorization = values[0]
+	}
+	key, err := m.authorize(ctx, authorization, grpcTenant(ctx), scope)
+	switch {
+	case errors.Is(err, errMissingAPIKey), errors.Is(err, domain.ErrInvalidAPIKey):
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, errScope), errors.Is(err, errOtherTenant):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case err != nil:
+		m.logError(ctx, "failed to authenticate call", err)
+		return nil, grpcError(err, "error while trying to authenticate call")
+	}
+
+	return context.WithValue(ctx, apiKeyContextKey{}, key), nil
+}
+
+// authenticatedStream is a stream whose context carries the API key of its call.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
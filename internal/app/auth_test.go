@@ -0,0 +1,203 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"crypto-project-1/internal/app"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/metrics"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/service"
+	"crypto-project-1/public"
+	challengev1 "crypto-project-1/public/proto/challenge/v1"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testAdminKey = "0123456789abcdef0123456789abcdef"
+
+// newAuthMicroservice returns a microservice with auth enabled, its tenants are shop and bank.
+func newAuthMicroservice() (*app.CryptoMicroservice, service.APIKeyService, *config.Config) {
+	cfg := config.Default()
+	cfg.Auth.Enabled = true
+	cfg.Auth.AdminKey = testAdminKey
+	cfg.Tenancy = config.TenancyConfig{
+		DefaultTenant: "shop",
+		Tenants:       []config.TenantConfig{{ID: "shop"}, {ID: "bank"}},
+	}
+	repo := repository.NewRepository(repository.NoTx, repository.NewChallengeMemoryRepository(), repository.NewAPIKeyMemoryRepository(), repository.NewChallengeStatsMemoryRepository(), repository.NewMultisigMemoryRepository(), repository.NewExtendedKeyMemoryRepository())
+	log := logrus.NewEntry(logrus.New())
+	challengeService := service.NewChallengeService(repo, time.Now, cfg.Challenge, cfg.Tenancy, service.NewNonceGenerators(), log)
+	apiKeyService := service.NewAPIKeyService(repo, time.Now, cfg.Auth, cfg.Tenancy, log)
+	adminService := service.NewAdminService(repo, time.Now, cfg.Tenancy, log)
+
+	return app.NewCryptoMicroservice(challengeService, apiKeyService, adminService, nil, log), apiKeyService, cfg
+}
+
+func serve(t *testing.T, e *echo.Echo, method, path, apiKey, tenant string, body interface{}) (int, *public.ApiResponse) {
+	requestBody, err := json.Marshal(body)
+	require.NoError(t, err)
+	request := httptest.NewRequest(method, path, bytes.NewReader(requestBody))
+	request.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		request.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	if tenant != "" {
+		request.Header.Set(app.TenantHeader, tenant)
+	}
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	response := &public.ApiResponse{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), response))
+	return recorder.Code, response
+}
+
+func TestServer_Auth(t *testing.T) {
+	microservice, apiKeyService, cfg := newAuthMicroservice()
+	e, err := app.NewServer(microservice, cfg.HTTP, cfg.Auth, metrics.New())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	createKey, err := apiKeyService.CreateAPIKey(ctx, "shop", "create", []string{domain.ScopeCreate})
+	require.NoError(t, err)
+	verifyKey, err := apiKeyService.CreateAPIKey(ctx, "shop", "verify", []string{domain.ScopeVerify})
+	require.NoError(t, err)
+	body := public.CreateChallengeRequestBody{PubKey: testPublicKey}
+
+	tests := []struct {
+		name           string
+		apiKey         string
+		tenant         string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "a request without api key is not authenticated",
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   public.AuthenticationFailed,
+		},
+		{
+			name:           "a request with an unknown api key is not authenticated",
+			apiKey:         "ck_unknown.secret",
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   public.AuthenticationFailed,
+		},
+		{
+			name:           "a request with a key without the scope is denied",
+			apiKey:         verifyKey.Key,
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   public.PermissionDenied,
+		},
+		{
+			name:           "a request for another tenant than the one of the key is denied",
+			apiKey:         createKey.Key,
+			tenant:         "bank",
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   public.PermissionDenied,
+		},
+		{
+			name:           "a request with a key granted the scope succeeds",
+			apiKey:         createKey.Key,
+			expectedStatus: http.StatusOK,
+			expectedCode:   public.ChallengeCreateSucceed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, response := serve(t, e, http.MethodPost, "/v1/challenge", test.apiKey, test.tenant, body)
+			assert.Equal(t, test.expectedStatus, status)
+			assert.Equal(t, test.expectedCode, response.Code)
+		})
+	}
+}
+
+func TestServer_AdminAPIKeys(t *testing.T) {
+	microservice, apiKeyService, cfg := newAuthMicroservice()
+	e, err := app.NewServer(microservice, cfg.HTTP, cfg.Auth, metrics.New())
+	require.NoError(t, err)
+	createKey, err := apiKeyService.CreateAPIKey(context.Background(), "shop", "create", []string{domain.ScopeCreate})
+	require.NoError(t, err)
+	body := public.CreateApiKeyRequestBody{Name: "checkout", Scopes: []string{domain.ScopeCreate}}
+
+	status, response := serve(t, e, http.MethodPost, "/admin/v1/api-keys", createKey.Key, "", body)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, public.PermissionDenied, response.Code)
+
+	status, _ = serve(t, e, http.MethodPost, "/admin/v1/api-keys", testAdminKey, "bank", public.CreateApiKeyRequestBody{Name: "checkout", Scopes: []string{"delete"}})
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, response = serve(t, e, http.MethodPost, "/admin/v1/api-keys", testAdminKey, "bank", body)
+	require.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, public.ApiKeyCreateSucceeded, response.Code)
+	issued := response.Result.(map[string]interface{})
+	assert.Equal(t, "bank", issued["tenantId"])
+	assert.NotEmpty(t, issued["key"])
+	assert.NotContains(t, issued, "hash")
+
+	// the admin key manages the keys of the tenant of the request only
+	status, _ = serve(t, e, http.MethodDelete, "/admin/v1/api-keys/"+issued["id"].(string), testAdminKey, "shop", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, response = serve(t, e, http.MethodPost, "/admin/v1/api-keys/"+issued["id"].(string)+"/rotate", testAdminKey, "bank", nil)
+	require.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, public.ApiKeyRotateSucceeded, response.Code)
+
+	status, response = serve(t, e, http.MethodGet, "/admin/v1/api-keys", testAdminKey, "bank", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, response.Result, 2)
+
+	// the rotated key is revoked
+	status, _ = serve(t, e, http.MethodPost, "/v1/challenge", issued["key"].(string), "", public.CreateChallengeRequestBody{PubKey: testPublicKey})
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
+
+func TestGRPCServer_Auth(t *testing.T) {
+	microservice, apiKeyService, cfg := newAuthMicroservice()
+	listener := bufconn.Listen(1024 * 1024)
+	server := app.NewGRPCServer(microservice, cfg.GRPC, cfg.Auth)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	client := challengev1.NewChallengeServiceClient(conn)
+
+	createKey, err := apiKeyService.CreateAPIKey(context.Background(), "shop", "create", []string{domain.ScopeCreate})
+	require.NoError(t, err)
+	request := &challengev1.CreateChallengeRequest{PublicKey: testPublicKey}
+
+	_, err = client.CreateChallenge(context.Background(), request)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+createKey.Key)
+	_, err = client.CreateChallenge(ctx, request)
+	assert.NoError(t, err)
+
+	_, err = client.VerifyChallenge(ctx, &challengev1.VerifyChallengeRequest{Token: "token"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -4,8 +4,8 @@ import (
 	"crypto-project-1/internal/domain"
 	"crypto-project-1/public"
 	"encoding/json"
+	"errors"
 	"github.com/labstack/echo/v4"
-	logger "github.com/sirupsen/logrus"
 	"io/ioutil"
 	"net/http"
 )
@@ -14,7 +14,7 @@ import (
 func (m *CryptoMicroservice) CreateChallenge(ctx echo.Context) error {
 	requestBody, err := ioutil.ReadAll(ctx.Request().Body)
 	if err != nil {
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "could not read request ", err)
+		m.logError(ctx.Request().Context(), "could not read request", err)
 		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
 			Code:    public.ChallengeCreateFailed,
 			Message: "could not read request body",
@@ -22,23 +22,36 @@ func (m *CryptoMicroservice) CreateChallenge(ctx echo.Context) error {
 	}
 	defer func() {
 		if err := ctx.Request().Body.Close(); err != nil {
-			logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "could not close request body ", err)
+			m.logError(ctx.Request().Context(), "could not close request body", err)
 			return
 		}
 	}()
 	request := &public.CreateChallengeRequestBody{}
 	if err := json.Unmarshal(requestBody, request); err != nil {
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError,
-			"could not unmarshal request ", string(requestBody), err)
+		m.logError(ctx.Request().Context(), "could not unmarshal request", err)
 		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
 			Code:    public.ChallengeCreateFailed,
 			Message: "invalid request body",
 		})
 	}
 
-	challenge, err := m.challengeService.CreateChallenge(request.PubKey)
+	var challenge *domain.Challenge
+	switch {
+	case len(request.PubKeys) > 0:
+		challenge, err = m.challengeService.CreateMultisigChallenge(ctx.Request().Context(), httpTenant(ctx), request.PubKeys, request.Threshold)
+	case request.GroupKey != "":
+		challenge, err = m.challengeService.CreateFrostChallenge(ctx.Request().Context(), httpTenant(ctx), request.GroupKey)
+	default:
+		challenge, err = m.challengeService.CreateChallenge(ctx.Request().Context(), httpTenant(ctx), request.PubKey)
+	}
+	if isTenantError(err) || errors.Is(err, domain.ErrInvalidThreshold) {
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Code:    public.ChallengeCreateFailed,
+			Message: err.Error(),
+		})
+	}
 	if err != nil {
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "failed to create challenge ", err)
+		m.logError(ctx.Request().Context(), "failed to create challenge", err)
 		return ctx.JSON(http.StatusInternalServerError, public.ApiResponse{
 			Code:    public.ChallengeCreateFailed,
 			Message: "error while trying to create challenge",
@@ -56,7 +69,7 @@ func (m *CryptoMicroservice) CreateChallenge(ctx echo.Context) error {
 func (m *CryptoMicroservice) VerifyChallenge(ctx echo.Context) error {
 	requestBody, err := ioutil.ReadAll(ctx.Request().Body)
 	if err != nil {
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "could not read request ", err)
+		m.logError(ctx.Request().Context(), "could not read request", err)
 		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
 			Result: &domain.ChallengeValidationResult{
 				Valid: false,
@@ -67,14 +80,14 @@ func (m *CryptoMicroservice) VerifyChallenge(ctx echo.Context) error {
 	}
 	defer func() {
 		if err := ctx.Request().Body.Close(); err != nil {
-			logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "could not close request body ", err)
+			m.logError(ctx.Request().Context(), "could not close request body", err)
 			return
 		}
 	}()
 
 	request := &public.VerifyChallengeRequestBody{}
 	if err := json.Unmarshal(requestBody, request); err != nil {
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "could not unmarshal request ", err)
+		m.logError(ctx.Request().Context(), "could not unmarshal request", err)
 		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
 			Result: &domain.ChallengeValidationResult{
 				Valid: false,
@@ -84,9 +97,18 @@ func (m *CryptoMicroservice) VerifyChallenge(ctx echo.Context) error {
 		})
 	}
 
-	result, err := m.challengeService.VerifyChallenge(request.Token)
+	result, err := m.challengeService.VerifyChallenge(ctx.Request().Context(), httpTenant(ctx), request.Token)
+	if isTenantError(err) {
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Result: &domain.ChallengeValidationResult{
+				Valid: false,
+			},
+			Code:    public.ChallengeValidationFailed,
+			Message: err.Error(),
+		})
+	}
 	if err != nil {
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "error while challenge validation ", err)
+		m.logError(ctx.Request().Context(), "error while challenge validation", err)
 		return ctx.JSON(http.StatusInternalServerError, public.ApiResponse{
 			Result: &domain.ChallengeValidationResult{
 				Valid: false,
@@ -98,7 +120,6 @@ func (m *CryptoMicroservice) VerifyChallenge(ctx echo.Context) error {
 
 	if !result.Valid {
 		message := "challenge validation failed"
-		logger.Info(message)
 		return ctx.JSON(http.StatusOK, public.ApiResponse{
 			Result:  result,
 			Code:    public.ChallengeValidationFailed,
@@ -112,3 +133,215 @@ func (m *CryptoMicroservice) VerifyChallenge(ctx echo.Context) error {
 		Message: "challenge validation succeeded",
 	})
 }
+
+// POST v1/verify-frost-signature
+func (m *CryptoMicroservice) VerifyFrostSignature(ctx echo.Context) error {
+	requestBody, err := ioutil.ReadAll(ctx.Request().Body)
+	if err != nil {
+		m.logError(ctx.Request().Context(), "could not read request", err)
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Result: &domain.ChallengeValidationResult{
+				Valid: false,
+			},
+			Code:    public.ChallengeValidationFailed,
+			Message: "could not read request body",
+		})
+	}
+	defer func() {
+		if err := ctx.Request().Body.Close(); err != nil {
+			m.logError(ctx.Request().Context(), "could not close request body", err)
+			return
+		}
+	}()
+
+	request := &public.VerifyFrostRequestBody{}
+	if err := json.Unmarshal(requestBody, request); err != nil {
+		m.logError(ctx.Request().Context(), "could not unmarshal request", err)
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Result: &domain.ChallengeValidationResult{
+				Valid: false,
+			},
+			Code:    public.ChallengeValidationFailed,
+			Message: "invalid request body",
+		})
+	}
+
+	result, err := m.challengeService.VerifyFrostSignature(ctx.Request().Context(), httpTenant(ctx), request.GroupKey, request.Nonce, request.Signature)
+	if isTenantError(err) {
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Result: &domain.ChallengeValidationResult{
+				Valid: false,
+			},
+			Code:    public.ChallengeValidationFailed,
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		m.logError(ctx.Request().Context(), "error while threshold signature validation", err)
+		return ctx.JSON(http.StatusInternalServerError, public.ApiResponse{
+			Result: &domain.ChallengeValidationResult{
+				Valid: false,
+			},
+			Code:    public.ChallengeValidationFailed,
+			Message: "internal error while trying to validate threshold signature",
+		})
+	}
+
+	if !result.Valid {
+		return ctx.JSON(http.StatusOK, public.ApiResponse{
+			Result:  result,
+			Code:    public.ChallengeValidationFailed,
+			Message: "threshold signature validation failed",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, public.ApiResponse{
+		Result:  result,
+		Code:    public.ChallengeValidationSucceeded,
+		Message: "threshold signature validation succeeded",
+	})
+}
+
+// POST v1/multisig-signatures
+func (m *CryptoMicroservice) SignMultisigChallenge(ctx echo.Context) error {
+	requestBody, err := ioutil.ReadAll(ctx.Request().Body)
+	if err != nil {
+		m.logError(ctx.Request().Context(), "could not read request", err)
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Code:    public.ChallengeSignFailed,
+			Message: "could not read request body",
+		})
+	}
+	defer func() {
+		if err := ctx.Request().Body.Close(); err != nil {
+			m.logError(ctx.Request().Context(), "could not close request body", err)
+			return
+		}
+	}()
+
+	request := &public.SignMultisigRequestBody{}
+	if err := json.Unmarshal(requestBody, request); err != nil {
+		m.logError(ctx.Request().Context(), "could not unmarshal request", err)
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Code:    public.ChallengeSignFailed,
+			Message: "invalid request body",
+		})
+	}
+
+	result, err := m.challengeService.SignMultisigChallenge(ctx.Request().Context(), httpTenant(ctx), request.Nonce, request.Tokens)
+	if isTenantError(err) {
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Code:    public.ChallengeSignFailed,
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		m.logError(ctx.Request().Context(), "failed to sign multisig challenge", err)
+		return ctx.JSON(http.StatusInternalServerError, public.ApiResponse{
+			Code:    public.ChallengeSignFailed,
+			Message: "error while trying to sign multisig challenge",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, public.ApiResponse{
+		Result:  result,
+		Code:    public.ChallengeSignSucceeded,
+		Message: "signatures submitted",
+	})
+}
+
+// POST v1/challenge-status
+func (m *CryptoMicroservice) GetChallengeStatus(ctx echo.Context) error {
+	requestBody, err := ioutil.ReadAll(ctx.Request().Body)
+	if err != nil {
+		m.logError(ctx.Request().Context(), "could not read request", err)
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Code:    public.ChallengeStatusFailed,
+			Message: "could not read request body",
+		})
+	}
+	defer func() {
+		if err := ctx.Request().Body.Close(); err != nil {
+			m.logError(ctx.Request().Context(), "could not close request body", err)
+			return
+		}
+	}()
+
+	request := &public.ChallengeStatusRequestBody{}
+	if err := json.Unmarshal(requestBody, request); err != nil {
+		m.logError(ctx.Request().Context(), "could not unmarshal request", err)
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Code:    public.ChallengeStatusFailed,
+			Message: "invalid request body",
+		})
+	}
+
+	status, err := m.challengeService.GetChallengeStatus(ctx.Request().Context(), httpTenant(ctx), request.PubKey, request.Nonce)
+	if isTenantError(err) {
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Code:    public.ChallengeStatusFailed,
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		m.logError(ctx.Request().Context(), "failed to get challenge status", err)
+		return ctx.JSON(http.StatusInternalServerError, public.ApiResponse{
+			Code:    public.ChallengeStatusFailed,
+			Message: "error while trying to get challenge status",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, public.ApiResponse{
+		Result:  status,
+		Code:    public.ChallengeStatusSucceeded,
+		Message: "successfully retrieved challenge status",
+	})
+}
+
+// POST v1/extended-keys
+func (m *CryptoMicroservice) EnrollExtendedKey(ctx echo.Context) error {
+	requestBody, err := ioutil.ReadAll(ctx.Request().Body)
+	if err != nil {
+		m.logError(ctx.Request().Context(), "could not read request", err)
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Code:    public.ExtendedKeyEnrollFailed,
+			Message: "could not read request body",
+		})
+	}
+	defer func() {
+		if err := ctx.Request().Body.Close(); err != nil {
+			m.logError(ctx.Request().Context(), "could not close request body", err)
+			return
+		}
+	}()
+
+	request := &public.EnrollExtendedKeyRequestBody{}
+	if err := json.Unmarshal(requestBody, request); err != nil {
+		m.logError(ctx.Request().Context(), "could not unmarshal request", err)
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Code:    public.ExtendedKeyEnrollFailed,
+			Message: "invalid request body",
+		})
+	}
+
+	key, err := m.challengeService.EnrollExtendedKey(ctx.Request().Context(), httpTenant(ctx), request.Xpub, request.PathPattern, request.MaxIndex)
+	if isTenantError(err) || errors.Is(err, domain.ErrInvalidPublicKey) || errors.Is(err, domain.ErrInvalidDerivationPath) {
+		return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+			Code:    public.ExtendedKeyEnrollFailed,
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		m.logError(ctx.Request().Context(), "failed to enroll extended key", err)
+		return ctx.JSON(http.StatusInternalServerError, public.ApiResponse{
+			Code:    public.ExtendedKeyEnrollFailed,
+			Message: "error while trying to enroll extended key",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, public.ApiResponse{
+		Result:  key,
+		Code:    public.ExtendedKeyEnrollSucceeded,
+		Message: "successfully enrolled extended key",
+	})
+}
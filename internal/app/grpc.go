@@ -0,0 +1,283 @@
+package app
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/tracing"
+	challengev1 "crypto-project-1/public/proto/challenge/v1"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var challengeStates = map[string]challengev1.ChallengeState{
+	domain.ChallengeStatusPending:  challengev1.ChallengeState_CHALLENGE_STATE_PENDING,
+	domain.ChallengeStatusExpired:  challengev1.ChallengeState_CHALLENGE_STATE_EXPIRED,
+	domain.ChallengeStatusNotFound: challengev1.ChallengeState_CHALLENGE_STATE_NOT_FOUND,
+	domain.ChallengeStatusVerified: challengev1.ChallengeState_CHALLENGE_STATE_VERIFIED,
+}
+
+// NewGRPCServer creates the gRPC server exposing the challenge service, the
+// standard health checking service and server reflection. The challenge
+// service requires an API key when auth is enabled.
+func NewGRPCServer(microService *CryptoMicroservice, cfg config.GRPCConfig, auth config.AuthConfig) *grpc.Server {
+	unary := []grpc.UnaryServerInterceptor{tracing.UnaryServerInterceptor(), logging.UnaryServerInterceptor(microService.log), microService.UnaryRecoveryInterceptor()}
+	stream := []grpc.StreamServerInterceptor{tracing.StreamServerInterceptor(), logging.StreamServerInterceptor(microService.log), microService.StreamRecoveryInterceptor()}
+	if auth.Enabled {
+		unary = append(unary, microService.UnaryAuthInterceptor())
+		stream = append(stream, microService.StreamAuthInterceptor())
+	}
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+	challengev1.RegisterChallengeServiceServer(server, &challengeGRPCServer{
+		microService:  microService,
+		watchInterval: cfg.WatchInterval,
+	})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(challengev1.ChallengeService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	return server
+}
+
+// UnaryRecoveryInterceptor turns a panic of a call into an Internal error, as
+// the Recover middleware of the HTTP server does, so that it does not crash
+// the process.
+func (m *CryptoMicroservice) UnaryRecoveryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (_ interface{}, err error) {
+		defer m.recoverGRPC(ctx, info.FullMethod, &err)
+
+		return handler(ctx, request)
+	}
+}
+
+// StreamRecoveryInterceptor is the UnaryRecoveryInterceptor of streaming calls.
+func (m *CryptoMicroservice) StreamRecoveryInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer m.recoverGRPC(stream.Context(), info.FullMethod, &err)
+
+		return handler(srv, stream)
+	}
+}
+
+func (m *CryptoMicroservice) recoverGRPC(ctx context.Context, method string, err *error) {
+	if recovered := recover(); recovered != nil {
+		m.logError(ctx, "recovered from panic in "+method, fmt.Errorf("%v\n%s", recovered, debug.Stack()))
+		*err = status.Error(codes.Internal, "internal error")
+	}
+}
+
+type challengeGRPCServer struct {
+	challengev1.UnimplementedChallengeServiceServer
+	microService  *CryptoMicroservice
+	watchInterval time.Duration
+}
+
+func (s *challengeGRPCServer) CreateChallenge(ctx context.Context, request *challengev1.CreateChallengeRequest) (*challengev1.CreateChallengeResponse, error) {
+	var challenge *domain.Challenge
+	var err error
+	switch {
+	case len(request.GetPublicKeys()) > 0:
+		challenge, err = s.microService.challengeService.CreateMultisigChallenge(ctx, grpcTenant(ctx), request.GetPublicKeys(), int(request.GetThreshold()))
+	case request.GetGroupKey() != "":
+		challenge, err = s.microService.challengeService.CreateFrostChallenge(ctx, grpcTenant(ctx), request.GetGroupKey())
+	case request.GetPublicKey() != "":
+		challenge, err = s.microService.challengeService.CreateChallenge(ctx, grpcTenant(ctx), request.GetPublicKey())
+	default:
+		return nil, status.Error(codes.InvalidArgument, "public key or group key is required")
+	}
+	if err != nil {
+		s.microService.logError(ctx, "failed to create challenge", err)
+		return nil, grpcError(err, "error while trying to create challenge")
+	}
+
+	return &challengev1.CreateChallengeResponse{
+		Challenge: &challengev1.Challenge{
+			PublicKey:   challenge.PublicKey,
+			Nonce:       challenge.Nonce,
+			ExpiresAt:   challenge.ExpiresAt,
+			Message:     challenge.Message,
+			PublicKeys:  challenge.PublicKeys,
+			Threshold:   int32(challenge.Threshold),
+			Ciphersuite: challenge.Ciphersuite,
+		},
+	}, nil
+}
+
+func (s *challengeGRPCServer) VerifyChallenge(ctx context.Context, request *challengev1.VerifyChallengeRequest) (*challengev1.VerifyChallengeResponse, error) {
+	if request.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	result, err := s.microService.challengeService.VerifyChallenge(ctx, grpcTenant(ctx), request.GetToken())
+	if err != nil {
+		s.microService.logError(ctx, "error while challenge validation", err)
+		return nil, grpcError(err, "internal error while trying to validate challenge")
+	}
+
+	response, err := verifyResponse(result)
+	if err != nil {
+		s.microService.logError(ctx, "error while encoding token claims", err)
+		return nil, grpcError(err, "internal error while trying to validate challenge")
+	}
+
+	return response, nil
+}
+
+func (s *challengeGRPCServer) VerifyFrostSignature(ctx context.Context, request *challengev1.VerifyFrostSignatureRequest) (*challengev1.VerifyChallengeResponse, error) {
+	if request.GetGroupKey() == "" || request.GetNonce() == "" || request.GetSignature() == "" {
+		return nil, status.Error(codes.InvalidArgument, "group key, nonce and signature are required")
+	}
+
+	result, err := s.microService.challengeService.VerifyFrostSignature(ctx, grpcTenant(ctx), request.GetGroupKey(), request.GetNonce(), request.GetSignature())
+	if err != nil {
+		s.microService.logError(ctx, "error while threshold signature validation", err)
+		return nil, grpcError(err, "internal error while trying to validate threshold signature")
+	}
+
+	return verifyResponse(result)
+}
+
+func (s *challengeGRPCServer) EnrollExtendedKey(ctx context.Context, request *challengev1.EnrollExtendedKeyRequest) (*challengev1.EnrollExtendedKeyResponse, error) {
+	if request.GetXpub() == "" || request.GetPathPattern() == "" {
+		return nil, status.Error(codes.InvalidArgument, "xpub and path pattern are required")
+	}
+
+	key, err := s.microService.challengeService.EnrollExtendedKey(ctx, grpcTenant(ctx), request.GetXpub(), request.GetPathPattern(), request.GetMaxIndex())
+	if err != nil {
+		s.microService.logError(ctx, "failed to enroll extended key", err)
+		return nil, grpcError(err, "error while trying to enroll extended key")
+	}
+
+	return &challengev1.EnrollExtendedKeyResponse{
+		Xpub:        key.Xpub,
+		PathPattern: key.PathPattern,
+		MaxIndex:    key.MaxIndex,
+		CreatedAt:   key.CreatedAt,
+	}, nil
+}
+
+func (s *challengeGRPCServer) SignMultisigChallenge(ctx context.Context, request *challengev1.SignMultisigChallengeRequest) (*challengev1.SignMultisigChallengeResponse, error) {
+	if request.GetNonce() == "" || len(request.GetTokens()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "nonce and tokens are required")
+	}
+
+	result, err := s.microService.challengeService.SignMultisigChallenge(ctx, grpcTenant(ctx), request.GetNonce(), request.GetTokens())
+	if err != nil {
+		s.microService.logError(ctx, "failed to sign multisig challenge", err)
+		return nil, grpcError(err, "error while trying to sign multisig challenge")
+	}
+
+	response := &challengev1.SignMultisigChallengeResponse{
+		Verified:  result.Verified,
+		Threshold: int32(result.Threshold),
+		Signers:   result.Signers,
+		ExpiresAt: result.ExpiresAt,
+	}
+	for _, signature := range result.Signatures {
+		signatureResponse, err := verifyResponse(signature)
+		if err != nil {
+			s.microService.logError(ctx, "error while encoding token claims", err)
+			return nil, grpcError(err, "error while trying to sign multisig challenge")
+		}
+		response.Signatures = append(response.Signatures, signatureResponse)
+	}
+
+	return response, nil
+}
+
+// verifyResponse encodes the result of a token.
+func verifyResponse(result *domain.ChallengeValidationResult) (*challengev1.VerifyChallengeResponse, error) {
+	response := &challengev1.VerifyChallengeResponse{
+		Valid:           result.Valid,
+		ValidationError: result.ValidationError,
+		KeyFingerprint:  result.KeyFingerprint,
+		KeyType:         result.KeyType,
+		Algorithm:       result.Algorithm,
+		CreatedAt:       result.CreatedAt,
+		ExpiresAt:       result.ExpiresAt,
+		VerifiedAt:      result.VerifiedAt,
+		DerivationPath:  result.DerivationPath,
+	}
+	if len(result.Claims) > 0 {
+		var err error
+		if response.Claims, err = structpb.NewStruct(result.Claims); err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+func (s *challengeGRPCServer) WatchChallenge(request *challengev1.WatchChallengeRequest, stream challengev1.ChallengeService_WatchChallengeServer) error {
+	if request.GetPublicKey() == "" || request.GetNonce() == "" {
+		return status.Error(codes.InvalidArgument, "public key and nonce are required")
+	}
+
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+
+	lastState := challengev1.ChallengeState_CHALLENGE_STATE_UNSPECIFIED
+	lastSigners := 0
+	for {
+		challengeStatus, err := s.microService.challengeService.GetChallengeStatus(stream.Context(), grpcTenant(stream.Context()), request.GetPublicKey(), request.GetNonce())
+		if err != nil {
+			s.microService.logError(stream.Context(), "failed to get challenge status", err)
+			return grpcError(err, "error while trying to get challenge status")
+		}
+
+		state := challengeStates[challengeStatus.Status]
+		if state != lastState || len(challengeStatus.Signers) != lastSigners {
+			lastState = state
+			lastSigners = len(challengeStatus.Signers)
+			err := stream.Send(&challengev1.WatchChallengeResponse{
+				State:     state,
+				ExpiresAt: challengeStatus.ExpiresAt,
+				Threshold: int32(challengeStatus.Threshold),
+				Signers:   challengeStatus.Signers,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if state != challengev1.ChallengeState_CHALLENGE_STATE_PENDING {
+			return nil
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// grpcError maps errors returned by the challenge service to gRPC status errors.
+func grpcError(err error, message string) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidPublicKey), errors.Is(err, domain.ErrInvalidThreshold), errors.Is(err, domain.ErrInvalidDerivationPath), isTenantError(err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, message)
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, message)
+	default:
+		return status.Error(codes.Internal, message)
+	}
+}
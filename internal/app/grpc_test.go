@@ -0,0 +1,299 @@
+package app_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto-project-1/internal/app"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/frost"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/repository/mock_repository"
+	"crypto-project-1/internal/service"
+	challengev1 "crypto-project-1/public/proto/challenge/v1"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newGRPCTestConn(t *testing.T, mockRepo *mock_repository.MockChallengeRepository, now func() time.Time) *grpc.ClientConn {
+	listener := bufconn.Listen(1024 * 1024)
+	challengeService := service.NewChallengeService(repository.NewRepository(repository.NoTx, mockRepo, nil, repository.NewChallengeStatsMemoryRepository(), repository.NewMultisigMemoryRepository(), repository.NewExtendedKeyMemoryRepository()), now, config.Default().Challenge, config.Default().Tenancy, service.NewNonceGenerators(), logrus.NewEntry(logrus.New()))
+	server := app.NewGRPCServer(app.NewCryptoMicroservice(challengeService, nil, nil, nil, logrus.NewEntry(logrus.New())), config.Default().GRPC, config.Default().Auth)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestGRPCServer_CreateChallenge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_repository.NewMockChallengeRepository(ctrl)
+	timeNow := time.Now()
+	mockRepo.EXPECT().CreateChallenge(gomock.Any(), "default", testPublicKey, gomock.Any(), gomock.Any(), gomock.Any(), timeNow.Add(time.Minute*5).Unix()).
+		DoAndReturn(func(_ context.Context, tenantID, pubKey, nonce, _ string, createdAt, expiresAt int64) (*domain.Challenge, error) {
+			return &domain.Challenge{TenantID: tenantID, PublicKey: pubKey, Nonce: nonce, ExpiresAt: expiresAt}, nil
+		})
+
+	client := challengev1.NewChallengeServiceClient(newGRPCTestConn(t, mockRepo, func() time.Time { return timeNow }))
+
+	response, err := client.CreateChallenge(context.Background(), &challengev1.CreateChallengeRequest{PublicKey: testPublicKey})
+	require.NoError(t, err)
+	assert.Equal(t, testPublicKey, response.GetChallenge().GetPublicKey())
+	assert.NotEmpty(t, response.GetChallenge().GetNonce())
+
+	_, err = client.CreateChallenge(context.Background(), &challengev1.CreateChallengeRequest{PublicKey: "invalid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.VerifyChallenge(context.Background(), &challengev1.VerifyChallengeRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCServer_VerifyChallenge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_repository.NewMockChallengeRepository(ctrl)
+	timeNow := time.Now()
+	nonce := "4b8b3887-e113-4e27-adb4-06f9aa66c395"
+	mockRepo.EXPECT().GetChallenges(gomock.Any(), "default", testPublicKey, nonce).
+		Return([]*domain.Challenge{{TenantID: "default", PublicKey: testPublicKey, Nonce: nonce, CreatedAt: timeNow.Unix(), ExpiresAt: timeNow.Add(time.Minute).Unix()}}, nil)
+
+	client := challengev1.NewChallengeServiceClient(newGRPCTestConn(t, mockRepo, func() time.Time { return timeNow }))
+
+	response, err := client.VerifyChallenge(context.Background(), &challengev1.VerifyChallengeRequest{Token: testToken})
+	require.NoError(t, err)
+	assert.True(t, response.GetValid())
+	assert.Equal(t, "EC P-256", response.GetKeyType())
+	assert.Equal(t, "ES256", response.GetAlgorithm())
+	assert.NotEmpty(t, response.GetKeyFingerprint())
+	assert.Equal(t, timeNow.Unix(), response.GetVerifiedAt())
+	assert.Equal(t, timeNow.Add(time.Minute).Unix(), response.GetExpiresAt())
+	assert.Equal(t, nonce, response.GetClaims().AsMap()["jti"])
+	assert.Equal(t, float64(1893492000), response.GetClaims().AsMap()["exp"])
+}
+
+func TestGRPCServer_VerifyChallengeNotPEMKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := challengev1.NewChallengeServiceClient(newGRPCTestConn(t, mock_repository.NewMockChallengeRepository(ctrl), time.Now))
+
+	// the kid decompresses to hex that is not a PEM block
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err := writer.Write([]byte("abcd"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	header, err := json.Marshal(map[string]string{"alg": "ES256", "typ": "JWT", "kid": base64.StdEncoding.EncodeToString(compressed.Bytes())})
+	require.NoError(t, err)
+	token := base64.RawURLEncoding.EncodeToString(header) + ".e30." + base64.RawURLEncoding.EncodeToString([]byte("signature"))
+
+	response, err := client.VerifyChallenge(context.Background(), &challengev1.VerifyChallengeRequest{Token: token})
+	require.NoError(t, err)
+	assert.False(t, response.GetValid())
+	assert.Contains(t, response.GetValidationError(), "not PEM encoded")
+
+	// the server is still serving
+	_, err = client.VerifyChallenge(context.Background(), &challengev1.VerifyChallengeRequest{Token: token})
+	require.NoError(t, err)
+}
+
+func TestGRPCServer_Recovery(t *testing.T) {
+	microService := app.NewCryptoMicroservice(nil, nil, nil, nil, logrus.NewEntry(logrus.New()))
+	info := &grpc.UnaryServerInfo{FullMethod: challengev1.ChallengeService_VerifyChallenge_FullMethodName}
+
+	_, err := microService.UnaryRecoveryInterceptor()(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		panic("nil pointer dereference")
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestGRPCServer_Multisig(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := challengev1.NewChallengeServiceClient(newGRPCTestConn(t, mock_repository.NewMockChallengeRepository(ctrl), time.Now))
+	ctx := context.Background()
+
+	response, err := client.CreateChallenge(ctx, &challengev1.CreateChallengeRequest{PublicKeys: []string{testPublicKey}, Threshold: 1})
+	require.NoError(t, err)
+	assert.Empty(t, response.GetChallenge().GetPublicKey())
+	assert.Equal(t, []string{testPublicKey}, response.GetChallenge().GetPublicKeys())
+	assert.Equal(t, int32(1), response.GetChallenge().GetThreshold())
+
+	_, err = client.CreateChallenge(ctx, &challengev1.CreateChallengeRequest{PublicKeys: []string{testPublicKey}, Threshold: 2})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// the token signs another nonce
+	signed, err := client.SignMultisigChallenge(ctx, &challengev1.SignMultisigChallengeRequest{Nonce: response.GetChallenge().GetNonce(), Tokens: []string{testToken}})
+	require.NoError(t, err)
+	assert.False(t, signed.GetVerified())
+	assert.Equal(t, int32(1), signed.GetThreshold())
+	require.Len(t, signed.GetSignatures(), 1)
+	assert.Equal(t, service.ValidationErrorInvalidNonce, signed.GetSignatures()[0].GetValidationError())
+
+	_, err = client.SignMultisigChallenge(ctx, &challengev1.SignMultisigChallengeRequest{Nonce: response.GetChallenge().GetNonce()})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCServer_Frost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_repository.NewMockChallengeRepository(ctrl)
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	groupKey := hex.EncodeToString(pub)
+	stored := &domain.Challenge{PublicKey: groupKey, Nonce: "4b8b3887-e113-4e27-adb4-06f9aa66c395", ExpiresAt: time.Now().Add(time.Minute).Unix()}
+	mockRepo.EXPECT().CreateChallenge(gomock.Any(), "default", groupKey, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(stored, nil)
+	mockRepo.EXPECT().GetChallenges(gomock.Any(), "default", groupKey, stored.Nonce).Return([]*domain.Challenge{stored}, nil)
+	client := challengev1.NewChallengeServiceClient(newGRPCTestConn(t, mockRepo, time.Now))
+	ctx := context.Background()
+
+	response, err := client.CreateChallenge(ctx, &challengev1.CreateChallengeRequest{GroupKey: groupKey})
+	require.NoError(t, err)
+	assert.Equal(t, groupKey, response.GetChallenge().GetPublicKey())
+	assert.Equal(t, frost.Ed25519, response.GetChallenge().GetCiphersuite())
+
+	_, err = client.CreateChallenge(ctx, &challengev1.CreateChallengeRequest{GroupKey: "invalid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// without a message the group signs the nonce
+	verified, err := client.VerifyFrostSignature(ctx, &challengev1.VerifyFrostSignatureRequest{
+		GroupKey:  groupKey,
+		Nonce:     stored.Nonce,
+		Signature: hex.EncodeToString(ed25519.Sign(priv, []byte(stored.Nonce))),
+	})
+	require.NoError(t, err)
+	assert.True(t, verified.GetValid())
+	assert.Equal(t, frost.Ed25519, verified.GetAlgorithm())
+
+	_, err = client.VerifyFrostSignature(ctx, &challengev1.VerifyFrostSignatureRequest{GroupKey: groupKey, Nonce: stored.Nonce})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCServer_Deadline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_repository.NewMockChallengeRepository(ctrl)
+	released := make(chan struct{})
+	mockRepo.EXPECT().CreateChallenge(gomock.Any(), "default", testPublicKey, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _, _, _, _ string, _, _ int64) (*domain.Challenge, error) {
+			<-ctx.Done()
+			close(released)
+			return nil, ctx.Err()
+		})
+
+	client := challengev1.NewChallengeServiceClient(newGRPCTestConn(t, mockRepo, time.Now))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	_, err := client.CreateChallenge(ctx, &challengev1.CreateChallengeRequest{PublicKey: testPublicKey})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		t.Fatal("the deadline of the call did not reach the repository")
+	}
+}
+
+func TestGRPCServer_Tenant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_repository.NewMockChallengeRepository(ctrl)
+	mockRepo.EXPECT().CreateChallenge(gomock.Any(), "default", testPublicKey, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, tenantID, pubKey, nonce, _ string, createdAt, expiresAt int64) (*domain.Challenge, error) {
+			return &domain.Challenge{TenantID: tenantID, PublicKey: pubKey, Nonce: nonce, ExpiresAt: expiresAt}, nil
+		})
+
+	client := challengev1.NewChallengeServiceClient(newGRPCTestConn(t, mockRepo, time.Now))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant-id", "default")
+	_, err := client.CreateChallenge(ctx, &challengev1.CreateChallengeRequest{PublicKey: testPublicKey})
+	assert.NoError(t, err)
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "x-tenant-id", "unknown")
+	_, err = client.CreateChallenge(ctx, &challengev1.CreateChallengeRequest{PublicKey: testPublicKey})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCServer_WatchChallenge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_repository.NewMockChallengeRepository(ctrl)
+	timeNow := time.Now()
+	expiresAt := timeNow.Add(time.Millisecond * 500)
+	mockRepo.EXPECT().GetChallenges(gomock.Any(), "default", testPublicKey, "nonce").
+		Return([]*domain.Challenge{{PublicKey: testPublicKey, Nonce: "nonce", ExpiresAt: expiresAt.Unix()}}, nil).
+		AnyTimes()
+
+	// the challenge expires between the first and the second poll
+	calls := 0
+	now := func() time.Time {
+		calls++
+		if calls == 1 {
+			return timeNow
+		}
+		return expiresAt.Add(time.Second)
+	}
+
+	client := challengev1.NewChallengeServiceClient(newGRPCTestConn(t, mockRepo, now))
+	stream, err := client.WatchChallenge(context.Background(), &challengev1.WatchChallengeRequest{PublicKey: testPublicKey, Nonce: "nonce"})
+	require.NoError(t, err)
+
+	var states []challengev1.ChallengeState
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		states = append(states, response.GetState())
+	}
+
+	assert.Equal(t, []challengev1.ChallengeState{
+		challengev1.ChallengeState_CHALLENGE_STATE_PENDING,
+		challengev1.ChallengeState_CHALLENGE_STATE_EXPIRED,
+	}, states)
+}
+
+func TestGRPCServer_Health(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	conn := newGRPCTestConn(t, mock_repository.NewMockChallengeRepository(ctrl), time.Now)
+	response, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: challengev1.ChallengeService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.GetStatus())
+}
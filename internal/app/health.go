@@ -0,0 +1,26 @@
+package app
+
+import (
+	"crypto-project-1/internal/health"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// GET healthz
+// Liveness: the process is up and serving requests.
+func (m *CryptoMicroservice) Liveness(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, map[string]string{
+		"status": health.StatusOK,
+	})
+}
+
+// GET readyz
+// Readiness: the dependencies of the API are available.
+func (m *CryptoMicroservice) Readiness(ctx echo.Context) error {
+	report := m.healthChecker.Check(ctx.Request().Context())
+	if report.Status != health.StatusOK {
+		return ctx.JSON(http.StatusServiceUnavailable, report)
+	}
+
+	return ctx.JSON(http.StatusOK, report)
+}
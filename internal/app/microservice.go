@@ -1,13 +1,34 @@
 package app
 
-import "crypto-project-1/internal/service"
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/health"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/service"
+
+	"github.com/sirupsen/logrus"
+)
 
 type CryptoMicroservice struct {
 	challengeService service.ChallengeService
+	apiKeyService    service.APIKeyService
+	adminService     service.AdminService
+	healthChecker    *health.Checker
+	log              *logrus.Entry
 }
 
-func NewCryptoMicroservice(challengeService service.ChallengeService) *CryptoMicroservice {
+func NewCryptoMicroservice(challengeService service.ChallengeService, apiKeyService service.APIKeyService, adminService service.AdminService, healthChecker *health.Checker, log *logrus.Entry) *CryptoMicroservice {
 	return &CryptoMicroservice{
 		challengeService: challengeService,
+		apiKeyService:    apiKeyService,
+		adminService:     adminService,
+		healthChecker:    healthChecker,
+		log:              log,
 	}
 }
+
+// logError logs err with the logger of the request of ctx.
+func (m *CryptoMicroservice) logError(ctx context.Context, message string, err error) {
+	logging.FromContext(ctx, m.log).WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error(message)
+}
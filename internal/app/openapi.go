@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/public"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// LoadOpenAPISpec parses and validates the OpenAPI document embedded in the public package.
+func LoadOpenAPISpec() (*openapi3.T, error) {
+	spec, err := openapi3.NewLoader().LoadFromData(public.OpenAPISpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load openapi spec: %w", err)
+	}
+	if err := spec.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %w", err)
+	}
+
+	return spec, nil
+}
+
+// GET openapi.json
+func getOpenAPISpec(ctx echo.Context) error {
+	return ctx.Blob(http.StatusOK, echo.MIMEApplicationJSON, public.OpenAPISpec)
+}
+
+// openAPIValidator rejects requests that do not match the OpenAPI spec. Requests
+// for routes that are not described by the spec are left to the router.
+func openAPIValidator(spec *openapi3.T, log *logrus.Entry) (echo.MiddlewareFunc, error) {
+	router, err := gorillamux.NewRouter(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create openapi router: %w", err)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			request := ctx.Request()
+			route, pathParams, err := router.FindRoute(request)
+			if err != nil {
+				if errors.Is(err, routers.ErrPathNotFound) || errors.Is(err, routers.ErrMethodNotAllowed) {
+					return next(ctx)
+				}
+				return err
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    request,
+				PathParams: pathParams,
+				Route:      route,
+				// API keys are checked by the routes that require them
+				Options: &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc},
+			}
+			if err := openapi3filter.ValidateRequest(request.Context(), input); err != nil {
+				logging.FromContext(request.Context(), log).WithError(err).Info("request does not match openapi spec")
+				return ctx.JSON(http.StatusBadRequest, public.ApiResponse{
+					Code:    public.RequestValidationFailed,
+					Message: validationMessage(err),
+				})
+			}
+
+			return next(ctx)
+		}
+	}, nil
+}
+
+func validationMessage(err error) string {
+	var requestErr *openapi3filter.RequestError
+	if errors.As(err, &requestErr) {
+		var schemaErr *openapi3.SchemaError
+		if errors.As(requestErr.Err, &schemaErr) {
+			return fmt.Sprintf("invalid request: %s", schemaErr.Reason)
+		}
+		return fmt.Sprintf("invalid request: %s", requestErr.Error())
+	}
+
+	return "invalid request"
+}
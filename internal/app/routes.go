@@ -1,17 +1,57 @@
 package app
 
 import (
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/metrics"
+	"crypto-project-1/internal/tracing"
+
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
 
-func NewServer(microService *CryptoMicroservice) *echo.Echo {
+// NewServer creates the HTTP server. The challenge endpoints require an API
+// key when auth is enabled, the admin endpoints always require one.
+func NewServer(microService *CryptoMicroservice, cfg config.HTTPConfig, auth config.AuthConfig, m *metrics.Metrics) (*echo.Echo, error) {
+	spec, err := LoadOpenAPISpec()
+	if err != nil {
+		return nil, err
+	}
+	validator, err := openAPIValidator(spec, microService.log)
+	if err != nil {
+		return nil, err
+	}
+
 	e := echo.New()
-	e.Use(middleware.Logger())
+	e.Server.ReadTimeout = cfg.ReadTimeout
+	e.Server.WriteTimeout = cfg.WriteTimeout
+	e.Use(tracing.Middleware())
+	e.Use(logging.Middleware(microService.log))
 	e.Use(middleware.Recover())
+	e.Use(m.Middleware())
+	e.Use(validator)
+	e.GET("/openapi.json", getOpenAPISpec)
+	e.GET("/metrics", echo.WrapHandler(m.Handler()))
+	e.GET("/healthz", microService.Liveness)
+	e.GET("/readyz", microService.Readiness)
 	v1 := e.Group("/v1")
-	v1.POST("/challenge", microService.CreateChallenge)
-	v1.POST("/verify-challenge", microService.VerifyChallenge)
+	v1.POST("/challenge", microService.CreateChallenge, microService.scope(auth, domain.ScopeCreate)...)
+	v1.POST("/verify-challenge", microService.VerifyChallenge, microService.scope(auth, domain.ScopeVerify)...)
+	v1.POST("/verify-frost-signature", microService.VerifyFrostSignature, microService.scope(auth, domain.ScopeVerify)...)
+	v1.POST("/multisig-signatures", microService.SignMultisigChallenge, microService.scope(auth, domain.ScopeVerify)...)
+	v1.POST("/challenge-status", microService.GetChallengeStatus, microService.scope(auth, domain.ScopeCreate)...)
+	v1.POST("/extended-keys", microService.EnrollExtendedKey, microService.scope(auth, domain.ScopeCreate)...)
+	microService.registerAdminRoutes(e)
+
+	return e, nil
+}
+
+// scope returns the middleware requiring an API key granted scope, none when auth is disabled.
+func (m *CryptoMicroservice) scope(auth config.AuthConfig, scope string) []echo.MiddlewareFunc {
+	if !auth.Enabled {
+		return nil
+	}
 
-	return e
+	return []echo.MiddlewareFunc{m.requireScope(scope)}
 }
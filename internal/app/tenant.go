@@ -0,0 +1,37 @@
+package app
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"errors"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// TenantHeader names the tenant of an HTTP request, the default tenant when absent.
+	TenantHeader = "X-Tenant-ID"
+	// tenantMetadataKey names the tenant of a gRPC call.
+	tenantMetadataKey = "x-tenant-id"
+)
+
+// isTenantError reports whether err is caused by the tenant a request names.
+func isTenantError(err error) bool {
+	return errors.Is(err, domain.ErrUnknownTenant) || errors.Is(err, domain.ErrTenantRequired)
+}
+
+// httpTenant returns the tenant of an HTTP request.
+func httpTenant(ctx echo.Context) string {
+	return requestTenant(ctx.Request().Context(), ctx.Request().Header.Get(TenantHeader))
+}
+
+// grpcTenant returns the tenant of a gRPC call.
+func grpcTenant(ctx context.Context) string {
+	var requested string
+	if values := metadata.ValueFromIncomingContext(ctx, tenantMetadataKey); len(values) > 0 {
+		requested = values[0]
+	}
+
+	return requestTenant(ctx, requested)
+}
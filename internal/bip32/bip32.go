@@ -0,0 +1,171 @@
+// Package bip32 derives the public keys of the non-hardened children of a
+// BIP-32 extended public key, an xpub.
+package bip32
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"gitlab.com/yawning/secp256k1-voi"
+	"gitlab.com/yawning/secp256k1-voi/secec"
+)
+
+const (
+	// HardenedOffset is the index of the first hardened child, which only the
+	// private key derives.
+	HardenedOffset = 1 << 31
+
+	// an extended key is 78 bytes and a checksum of 4, 111 base58 characters
+	serializedSize = 78
+	checksumSize   = 4
+	maxEncodedSize = 112
+	chainCodeSize  = 32
+)
+
+var (
+	// ErrInvalidExtendedKey is returned when an extended public key does not decode.
+	ErrInvalidExtendedKey = errors.New("invalid extended public key")
+	// ErrInvalidChild is returned when a child key cannot be derived, its
+	// index is hardened or, rarely, out of the group.
+	ErrInvalidChild = errors.New("invalid child key")
+)
+
+// versions of the extended public keys of the main and test networks, xpub
+// and tpub, and of their private keys, which are rejected
+var (
+	versionMainnetPublic  = []byte{0x04, 0x88, 0xb2, 0x1e}
+	versionTestnetPublic  = []byte{0x04, 0x35, 0x87, 0xcf}
+	versionMainnetPrivate = []byte{0x04, 0x88, 0xad, 0xe4}
+	versionTestnetPrivate = []byte{0x04, 0x35, 0x83, 0x94}
+)
+
+// IsExtendedKey reports whether the encoded key looks like an extended public
+// key, xpub or tpub, rather than a key of another format.
+func IsExtendedKey(encoded string) bool {
+	return strings.HasPrefix(encoded, "xpub") || strings.HasPrefix(encoded, "tpub")
+}
+
+// ExtendedKey is a public key with the chain code that derives its children.
+type ExtendedKey struct {
+	Depth       uint8
+	ChildNumber uint32
+	ChainCode   [chainCodeSize]byte
+	PublicKey   *secec.PublicKey
+}
+
+// ParseExtendedKey decodes the base58check serialization of an extended
+// public key of the main or the test network.
+func ParseExtendedKey(encoded string) (*ExtendedKey, error) {
+	if len(encoded) > maxEncodedSize {
+		return nil, fmt.Errorf("%w: more than %d characters", ErrInvalidExtendedKey, maxEncodedSize)
+	}
+	decoded, err := decodeBase58(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidExtendedKey, err)
+	}
+	if len(decoded) != serializedSize+checksumSize {
+		return nil, fmt.Errorf("%w: not %d bytes", ErrInvalidExtendedKey, serializedSize)
+	}
+	payload, checksum := decoded[:serializedSize], decoded[serializedSize:]
+	if !bytes.Equal(doubleSHA256(payload)[:checksumSize], checksum) {
+		return nil, fmt.Errorf("%w: checksum does not match", ErrInvalidExtendedKey)
+	}
+
+	version := payload[:4]
+	switch {
+	case bytes.Equal(version, versionMainnetPrivate), bytes.Equal(version, versionTestnetPrivate):
+		return nil, fmt.Errorf("%w: it is a private key", ErrInvalidExtendedKey)
+	case !bytes.Equal(version, versionMainnetPublic) && !bytes.Equal(version, versionTestnetPublic):
+		return nil, fmt.Errorf("%w: unknown version %x", ErrInvalidExtendedKey, version)
+	}
+
+	key := &ExtendedKey{
+		Depth:       payload[4],
+		ChildNumber: binary.BigEndian.Uint32(payload[9:13]),
+	}
+	// a master key has no parent
+	if key.Depth == 0 && (binary.BigEndian.Uint32(payload[5:9]) != 0 || key.ChildNumber != 0) {
+		return nil, fmt.Errorf("%w: master key with a parent", ErrInvalidExtendedKey)
+	}
+	copy(key.ChainCode[:], payload[13:45])
+	if payload[45] != 0x02 && payload[45] != 0x03 {
+		return nil, fmt.Errorf("%w: public key is not compressed", ErrInvalidExtendedKey)
+	}
+	if key.PublicKey, err = secec.NewPublicKey(payload[45:]); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidExtendedKey, err)
+	}
+
+	return key, nil
+}
+
+// Child derives the public key of the non-hardened child of the index, as
+// CKDpub of BIP-32 does.
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	if index >= HardenedOffset {
+		return nil, fmt.Errorf("%w: index %d is hardened", ErrInvalidChild, index)
+	}
+	if k.Depth == 255 {
+		return nil, fmt.Errorf("%w: key is at the maximum depth", ErrInvalidChild)
+	}
+
+	mac := hmac.New(sha512.New, k.ChainCode[:])
+	mac.Write(k.PublicKey.CompressedBytes())
+	_ = binary.Write(mac, binary.BigEndian, index)
+	sum := mac.Sum(nil)
+
+	// a tweak out of the group, or a child at infinity, has no key: the
+	// caller moves on to the next index
+	tweak, err := secp256k1.NewScalarFromCanonicalBytes((*[secp256k1.ScalarSize]byte)(sum[:secp256k1.ScalarSize]))
+	if err != nil {
+		return nil, fmt.Errorf("%w: index %d: %s", ErrInvalidChild, index, err)
+	}
+	point := secp256k1.NewIdentityPoint().ScalarBaseMult(tweak)
+	point.Add(point, k.PublicKey.Point())
+	pubKey, err := secec.NewPublicKeyFromPoint(point)
+	if err != nil {
+		return nil, fmt.Errorf("%w: index %d: %s", ErrInvalidChild, index, err)
+	}
+
+	child := &ExtendedKey{
+		Depth:       k.Depth + 1,
+		ChildNumber: index,
+		PublicKey:   pubKey,
+	}
+	copy(child.ChainCode[:], sum[secp256k1.ScalarSize:])
+
+	return child, nil
+}
+
+func doubleSHA256(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+
+	return second[:]
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// decodeBase58 decodes the bitcoin alphabet, whose leading 1s are zero bytes.
+func decodeBase58(encoded string) ([]byte, error) {
+	value, radix := new(big.Int), big.NewInt(int64(len(base58Alphabet)))
+	zeros := 0
+	for i := 0; i < len(encoded); i++ {
+		digit := strings.IndexByte(base58Alphabet, encoded[i])
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", encoded[i])
+		}
+		if digit == 0 && zeros == i {
+			zeros++
+		}
+		value.Mul(value, radix).Add(value, big.NewInt(int64(digit)))
+	}
+
+	return append(make([]byte, zeros), value.Bytes()...), nil
+}
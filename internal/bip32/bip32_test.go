@@ -0,0 +1,96 @@
+package bip32
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/yawning/secp256k1-voi/secec"
+)
+
+// keys of test vector 1 of BIP-32, each the non-hardened child of the previous one
+const (
+	vectorParent     = "xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5"
+	vectorChild      = "xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV"
+	vectorGrandchild = "xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy"
+)
+
+func TestExtendedKey_Child(t *testing.T) {
+	parent, err := ParseExtendedKey(vectorParent)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(3), parent.Depth)
+	assert.Equal(t, uint32(HardenedOffset+2), parent.ChildNumber)
+
+	child, err := parent.Child(2)
+	require.NoError(t, err)
+	assertKey(t, vectorChild, child)
+
+	grandchild, err := child.Child(1000000000)
+	require.NoError(t, err)
+	assertKey(t, vectorGrandchild, grandchild)
+
+	_, err = child.Child(HardenedOffset)
+	assert.ErrorIs(t, err, ErrInvalidChild)
+}
+
+func TestPathPattern_Derive(t *testing.T) {
+	parent, err := ParseExtendedKey(vectorParent)
+	require.NoError(t, err)
+
+	pattern, err := ParsePathPattern("2/*")
+	require.NoError(t, err)
+	assert.Equal(t, "2/1000000000", pattern.Path(1000000000))
+	grandchild, err := pattern.Derive(parent, 1000000000)
+	require.NoError(t, err)
+	assertKey(t, vectorGrandchild, grandchild)
+
+	pattern, err = ParsePathPattern("*")
+	require.NoError(t, err)
+	assert.Equal(t, "2", pattern.Path(2))
+	child, err := pattern.Derive(parent, 2)
+	require.NoError(t, err)
+	assertKey(t, vectorChild, child)
+
+	// the child key verifies the signatures of its private key only
+	digest := sha256.Sum256([]byte("nonce"))
+	privateKey, err := secec.GenerateKey()
+	require.NoError(t, err)
+	signature, err := privateKey.Sign(nil, digest[:], nil)
+	require.NoError(t, err)
+	assert.False(t, child.PublicKey.Verify(digest[:], signature, nil))
+}
+
+func TestParsePathPattern(t *testing.T) {
+	for _, pattern := range []string{"", "0", "*/0", "0/*/*", "0'/*", "0h/*", "2147483648/*", "-1/*", "01/*", "0//*", "0/1/2/3/4/5/6/7/*"} {
+		_, err := ParsePathPattern(pattern)
+		assert.ErrorIs(t, err, ErrInvalidPath, pattern)
+	}
+}
+
+func TestParseExtendedKey(t *testing.T) {
+	tests := map[string]string{
+		"not base58":  "xpub0OIl",
+		"checksum":    vectorParent[:len(vectorParent)-1] + "6",
+		"too short":   "xpub661MyMwAqRbcF",
+		"private key": "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+		// invalid keys of test vector 5 of BIP-32
+		"pubkey version / prvkey mismatch": "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6LBpB85b3D2yc8sfvZU521AAwdZafEz7mnzBBsz4wKY5fTtTQBm",
+		"zero depth with non-zero parent":  "xpub661no6RGEX3uJkY4bNnPcw4URcQTrSibUZ4NqJEw5eBkv7ovTwgiT91XX27VbEXGENhYRCf7hyEbWrR3FewATdCEebj6znwMfQkhRYHRLpJ",
+		"zero depth with non-zero index":   "xpub661MyMwAuDcm6CRQ5N4qiHKrJ39Xe1R1NyfouMKTTWcguwVcfrZJaNvhpebzGerh7gucBvzEQWRugZDuDXjNDRmXzSZe4c7mnTK97pTvGS8",
+	}
+	for name, encoded := range tests {
+		_, err := ParseExtendedKey(encoded)
+		assert.ErrorIs(t, err, ErrInvalidExtendedKey, name)
+	}
+}
+
+func assertKey(t *testing.T, expected string, key *ExtendedKey) {
+	t.Helper()
+	expectedKey, err := ParseExtendedKey(expected)
+	require.NoError(t, err)
+	assert.Equal(t, expectedKey.Depth, key.Depth)
+	assert.Equal(t, expectedKey.ChildNumber, key.ChildNumber)
+	assert.Equal(t, expectedKey.ChainCode, key.ChainCode)
+	assert.True(t, expectedKey.PublicKey.Equal(key.PublicKey))
+}
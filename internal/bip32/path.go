@@ -0,0 +1,74 @@
+package bip32
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Wildcard is the component of a path pattern that the derivation index replaces.
+	Wildcard = "*"
+	// MaxPathLength is the number of components a path pattern has at most.
+	MaxPathLength = 8
+)
+
+// ErrInvalidPath is returned when a path pattern does not parse.
+var ErrInvalidPath = errors.New("invalid derivation path")
+
+// PathPattern is a derivation path relative to an extended key, such as 0/*,
+// whose last component is the wildcard. Its components are non-hardened.
+type PathPattern struct {
+	prefix []uint32
+}
+
+// ParsePathPattern parses a pattern of non-hardened indexes separated by
+// slashes that ends with the wildcard.
+func ParsePathPattern(pattern string) (*PathPattern, error) {
+	components := strings.Split(pattern, "/")
+	if len(components) > MaxPathLength {
+		return nil, fmt.Errorf("%w: more than %d components", ErrInvalidPath, MaxPathLength)
+	}
+	if components[len(components)-1] != Wildcard {
+		return nil, fmt.Errorf("%w: %q does not end with %s", ErrInvalidPath, pattern, Wildcard)
+	}
+
+	path := &PathPattern{}
+	for _, component := range components[:len(components)-1] {
+		if strings.HasSuffix(component, "'") || strings.HasSuffix(component, "h") || strings.HasSuffix(component, "H") {
+			return nil, fmt.Errorf("%w: %s is hardened, an extended public key cannot derive it", ErrInvalidPath, component)
+		}
+		index, err := strconv.ParseUint(component, 10, 32)
+		if err != nil || index >= HardenedOffset || component != strconv.FormatUint(index, 10) {
+			return nil, fmt.Errorf("%w: %q is not an index below %d", ErrInvalidPath, component, uint32(HardenedOffset))
+		}
+		path.prefix = append(path.prefix, uint32(index))
+	}
+
+	return path, nil
+}
+
+// Path is the path of the child of the index, relative to the extended key.
+func (p *PathPattern) Path(index uint32) string {
+	var path strings.Builder
+	for _, component := range p.prefix {
+		path.WriteString(strconv.FormatUint(uint64(component), 10))
+		path.WriteString("/")
+	}
+	path.WriteString(strconv.FormatUint(uint64(index), 10))
+
+	return path.String()
+}
+
+// Derive derives the child of the key at the path of the index.
+func (p *PathPattern) Derive(key *ExtendedKey, index uint32) (*ExtendedKey, error) {
+	var err error
+	for _, component := range append(append([]uint32(nil), p.prefix...), index) {
+		if key, err = key.Child(component); err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
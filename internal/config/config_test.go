@@ -0,0 +1,253 @@
+package config_test
+
+import (
+	"bytes"
+	"crypto-project-1/internal/config"
+	"encoding/base64"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+
+	return path
+}
+
+func setDatabaseEnv(t *testing.T) {
+	t.Setenv("PGHOST", "db")
+	t.Setenv("PGDATABASE", "postgres")
+	t.Setenv("PGUSER", "postgres")
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	configFile := writeFile(t, "config.yaml", `
+http:
+  port: 8000
+grpc:
+  port: 8001
+database:
+  host: file-host
+  port: 6543
+challenge:
+  nonceTimeToLive: 1m
+`)
+	setDatabaseEnv(t)
+	t.Setenv("CRYPTO_CONFIG_FILE", configFile)
+	t.Setenv("CRYPTO_GRPC_PORT", "9001")
+	t.Setenv("CRYPTO_NONCE_TTL", "2m")
+
+	cfg, err := config.Load([]string{"-nonce-ttl", "3m"})
+	require.NoError(t, err)
+
+	// file overrides defaults
+	assert.Equal(t, 8000, cfg.HTTP.Port)
+	assert.Equal(t, 6543, cfg.Database.Port)
+	assert.Equal(t, time.Second*10, cfg.HTTP.ReadTimeout)
+	// env overrides file
+	assert.Equal(t, 9001, cfg.GRPC.Port)
+	assert.Equal(t, "db", cfg.Database.Host)
+	// flags override env
+	assert.Equal(t, time.Minute*3, cfg.Challenge.NonceTimeToLive)
+}
+
+func TestLoad_SecretFromFile(t *testing.T) {
+	setDatabaseEnv(t)
+	t.Setenv("PGPASSWORD", "from-env")
+	t.Setenv("PGPASSWORD_FILE", writeFile(t, "password", "from-file\n"))
+
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", cfg.Database.Password)
+}
+
+func TestLoad_MemoryStorage(t *testing.T) {
+	t.Setenv("PGHOST", "")
+
+	cfg, err := config.Load([]string{"-storage-backend", "memory"})
+	require.NoError(t, err)
+	assert.Equal(t, config.StorageMemory, cfg.Storage.Backend)
+}
+
+func TestLoad_DatabaseURL(t *testing.T) {
+	t.Setenv("PGHOST", "")
+	t.Setenv("DATABASE_URL", "sqlite:/var/lib/crypto/challenges.db")
+
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, config.StorageDatabase, cfg.Storage.Backend)
+	assert.Equal(t, "sqlite:/var/lib/crypto/challenges.db", cfg.Database.URL)
+}
+
+func TestLoad_Tenants(t *testing.T) {
+	setDatabaseEnv(t)
+	t.Setenv("CRYPTO_CONFIG_FILE", writeFile(t, "config.yaml", `
+tenancy:
+  defaultTenant: shop
+  tenants:
+    - id: shop
+      audiences: [shop-web, shop-app]
+      nonceTimeToLive: 2m
+    - id: bank
+      algorithms: [ES512]
+`))
+
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "shop", cfg.Tenancy.DefaultTenant)
+	assert.Equal(t, []config.TenantConfig{
+		{ID: "shop", Audiences: []string{"shop-web", "shop-app"}, NonceTimeToLive: time.Minute * 2},
+		{ID: "bank", Algorithms: []string{"ES512"}},
+	}, cfg.Tenancy.Tenants)
+}
+
+func TestLoad_SealKeys(t *testing.T) {
+	setDatabaseEnv(t)
+	newKey := base64.StdEncoding.EncodeToString(make([]byte, 32))
+	oldKey := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	t.Setenv("CRYPTO_CHALLENGE_MODE", "sealed")
+	t.Setenv("CRYPTO_CHALLENGE_SINGLE_USE", "true")
+	t.Setenv("CRYPTO_CHALLENGE_SEAL_KEYS", newKey+", "+oldKey)
+
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{newKey, oldKey}, cfg.Challenge.SealKeys)
+}
+
+func TestLoad_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		file string
+	}{
+		{
+			name: "database host is required",
+			env:  map[string]string{"PGHOST": ""},
+		},
+		{
+			name: "ports must differ",
+			args: []string{"-http-port", "7000", "-grpc-port", "7000"},
+		},
+		{
+			name: "sslmode must be known",
+			env:  map[string]string{"PGSSLMODE": "sometimes"},
+		},
+		{
+			name: "nonce ttl must be positive",
+			args: []string{"-nonce-ttl", "0s"},
+		},
+		{
+			name: "sample ratio must be a share",
+			env:  map[string]string{"CRYPTO_TRACING_SAMPLE_RATIO": "1.5"},
+		},
+		{
+			name: "log format must be known",
+			args: []string{"-log-format", "xml"},
+		},
+		{
+			name: "query timeout must be positive",
+			env:  map[string]string{"CRYPTO_DB_QUERY_TIMEOUT": "0s"},
+		},
+		{
+			name: "database url scheme must be known",
+			env:  map[string]string{"DATABASE_URL": "mysql://db/crypto"},
+		},
+		{
+			name: "storage backend must be known",
+			env:  map[string]string{"CRYPTO_STORAGE_BACKEND": "files"},
+		},
+		{
+			name: "idle connections must not exceed open connections",
+			args: []string{"-db-max-open-conns", "2", "-db-max-idle-conns", "3"},
+		},
+		{
+			name: "default tenant must be one of the tenants",
+			env:  map[string]string{"CRYPTO_DEFAULT_TENANT": "bank"},
+			file: "tenancy:\n  tenants:\n    - id: shop\n",
+		},
+		{
+			name: "tenant ids must be unique",
+			env:  map[string]string{"CRYPTO_DEFAULT_TENANT": "shop"},
+			file: "tenancy:\n  tenants:\n    - id: shop\n    - id: shop\n",
+		},
+		{
+			name: "tenant algorithms must be known",
+			env:  map[string]string{"CRYPTO_DEFAULT_TENANT": "shop"},
+			file: "tenancy:\n  tenants:\n    - id: shop\n      algorithms: [HS256]\n",
+		},
+		{
+			name: "tenant id must be lower case",
+			env:  map[string]string{"CRYPTO_DEFAULT_TENANT": "Shop"},
+		},
+		{
+			name: "admin key must be long enough",
+			env:  map[string]string{"CRYPTO_AUTH_ADMIN_KEY": "short"},
+		},
+		{
+			name: "challenge mode must be known",
+			args: []string{"-challenge-mode", "cached"},
+		},
+		{
+			name: "seal keys are required in sealed mode",
+			args: []string{"-challenge-mode", "sealed"},
+		},
+		{
+			name: "seal keys must be AES-256 keys",
+			env:  map[string]string{"CRYPTO_CHALLENGE_SEAL_KEYS": "c2hvcnQ="},
+			args: []string{"-challenge-mode", "sealed"},
+		},
+		{
+			name: "single use is required in sealed mode",
+			env:  map[string]string{"CRYPTO_CHALLENGE_SEAL_KEYS": base64.StdEncoding.EncodeToString(make([]byte, 32))},
+			args: []string{"-challenge-mode", "sealed"},
+		},
+		{
+			name: "nonce format must be known",
+			args: []string{"-nonce-format", "hex"},
+		},
+		{
+			name: "tenant nonce format must be known",
+			env:  map[string]string{"CRYPTO_DEFAULT_TENANT": "shop"},
+			file: "tenancy:\n  tenants:\n    - id: shop\n      nonceFormat: hex\n",
+		},
+		{
+			name: "message statement must be a single line",
+			env:  map[string]string{"CRYPTO_CHALLENGE_STATEMENT": "Sign in.\nURI: https://evil.example"},
+		},
+		{
+			name: "message template must render the nonce",
+			env:  map[string]string{"CRYPTO_CHALLENGE_MESSAGE_TEMPLATE": "Sign in to {{.Domain}}"},
+		},
+		{
+			name: "tenant message template must parse",
+			env:  map[string]string{"CRYPTO_DEFAULT_TENANT": "shop"},
+			file: "tenancy:\n  tenants:\n    - id: shop\n      domain: shop.example\n      messageTemplate: \"{{.Nonce\"\n",
+		},
+		{
+			name: "port must be a number",
+			env:  map[string]string{"PGPORT": "postgres"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setDatabaseEnv(t)
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+			if test.file != "" {
+				t.Setenv("CRYPTO_CONFIG_FILE", writeFile(t, "config.yaml", test.file))
+			}
+
+			_, err := config.Load(test.args)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const fileVarSuffix = "_FILE"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setting is a configurable field of Config.
+type setting struct {
+	path   string
+	env    string
+	flag   string
+	usage  string
+	secret bool
+	value  reflect.Value
+}
+
+// Load resolves the configuration from defaults, the config file, environment
+// variables and the command line arguments, and validates it.
+func Load(args []string) (*Config, error) {
+	return load(args, os.LookupEnv)
+}
+
+func load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+	settings := collectSettings(reflect.ValueOf(cfg).Elem(), "")
+
+	flagSet := flag.NewFlagSet("crypto-api", flag.ContinueOnError)
+	configFile := flagSet.String(configFileFlag, "", "path of the YAML config file")
+	flagValues := map[string]*string{}
+	for _, s := range settings {
+		if s.flag != "" {
+			flagValues[s.flag] = flagSet.String(s.flag, "", s.usage)
+		}
+	}
+	if err := flagSet.Parse(args); err != nil {
+		return nil, err
+	}
+
+	// config file
+	path := *configFile
+	if path == "" {
+		path, _ = lookupEnv(configFileVar)
+	}
+	if path != "" {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(content, cfg); err != nil {
+			return nil, fmt.Errorf("could not parse config file %s: %w", path, err)
+		}
+	}
+
+	// environment variables
+	for _, s := range settings {
+		if s.env == "" {
+			continue
+		}
+		if s.secret {
+			if file, found := lookupEnv(s.env + fileVarSuffix); found {
+				content, err := ioutil.ReadFile(file)
+				if err != nil {
+					return nil, fmt.Errorf("could not read %s: %w", s.env+fileVarSuffix, err)
+				}
+				if err := s.set(strings.TrimSpace(string(content))); err != nil {
+					return nil, fmt.Errorf("invalid %s: %w", s.env+fileVarSuffix, err)
+				}
+				continue
+			}
+		}
+		if value, found := lookupEnv(s.env); found {
+			if err := s.set(value); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", s.env, err)
+			}
+		}
+	}
+
+	// command line flags
+	var flagErr error
+	flagSet.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.flag == f.Name && flagErr == nil {
+				if err := s.set(*flagValues[s.flag]); err != nil {
+					flagErr = fmt.Errorf("invalid -%s: %w", s.flag, err)
+				}
+			}
+		}
+	})
+	if flagErr != nil {
+		return nil, flagErr
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func collectSettings(v reflect.Value, prefix string) []*setting {
+	var settings []*setting
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		path := prefix + field.Tag.Get("yaml")
+		if field.Type.Kind() == reflect.Struct {
+			settings = append(settings, collectSettings(v.Field(i), path+".")...)
+			continue
+		}
+
+		settings = append(settings, &setting{
+			path:   path,
+			env:    field.Tag.Get("env"),
+			flag:   field.Tag.Get("flag"),
+			usage:  field.Tag.Get("usage"),
+			secret: field.Tag.Get("secret") == "true",
+			value:  v.Field(i),
+		})
+	}
+
+	return settings
+}
+
+func (s *setting) set(raw string) error {
+	if s.value.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		s.value.SetInt(int64(d))
+		return nil
+	}
+
+	switch s.value.Kind() {
+	case reflect.String:
+		s.value.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		s.value.SetInt(i)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		s.value.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		s.value.SetBool(b)
+	case reflect.Slice:
+		if s.value.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s of setting %s", s.value.Type(), s.path)
+		}
+		// lists are comma separated
+		var values []string
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		s.value.Set(reflect.ValueOf(values))
+	default:
+		return fmt.Errorf("unsupported type %s of setting %s", s.value.Type(), s.path)
+	}
+
+	return nil
+}
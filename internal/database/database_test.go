@@ -0,0 +1,85 @@
+package database_test
+
+import (
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/database"
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		name            string
+		url             string
+		dialect         database.Dialect
+		dsn             string
+		errorIsReturned bool
+	}{
+		{
+			name:    "empty url is postgres",
+			dialect: database.Postgres,
+		},
+		{
+			name:    "postgres url is passed to the driver",
+			url:     "postgres://user:password@db:5432/crypto?sslmode=disable",
+			dialect: database.Postgres,
+			dsn:     "postgres://user:password@db:5432/crypto?sslmode=disable",
+		},
+		{
+			name:    "sqlite path gets the pragmas",
+			url:     "sqlite:/var/lib/crypto/challenges.db",
+			dialect: database.SQLite,
+			dsn:     "/var/lib/crypto/challenges.db?_pragma=busy_timeout%285000%29&_pragma=journal_mode%28WAL%29&_pragma=foreign_keys%281%29",
+		},
+		{
+			name:    "sqlite url keeps its parameters",
+			url:     "sqlite://challenges.db?mode=ro",
+			dialect: database.SQLite,
+			dsn:     "challenges.db?mode=ro&_pragma=busy_timeout%285000%29&_pragma=journal_mode%28WAL%29&_pragma=foreign_keys%281%29",
+		},
+		{
+			name:            "scheme must be known",
+			url:             "mysql://db/crypto",
+			errorIsReturned: true,
+		},
+		{
+			name:            "scheme is required",
+			url:             "challenges.db",
+			errorIsReturned: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dialect, dsn, err := database.ParseURL(test.url)
+			if test.errorIsReturned {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.dialect, dialect)
+			assert.Equal(t, test.dsn, dsn)
+		})
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	db, err := database.Open(config.DatabaseConfig{URL: "sqlite::memory:"})
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("create table item (name text unique)")
+	require.NoError(t, err)
+	_, err = db.Exec("insert into item (name) values ('a')")
+	require.NoError(t, err)
+
+	_, err = db.Exec("insert into item (name) values ('a')")
+	assert.True(t, database.IsUniqueViolation(err))
+	assert.True(t, database.IsUniqueViolation(&pq.Error{Code: "23505"}))
+	assert.False(t, database.IsUniqueViolation(&pq.Error{Code: "23503"}))
+	assert.False(t, database.IsUniqueViolation(errors.New("unique")))
+}
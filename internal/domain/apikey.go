@@ -0,0 +1,45 @@
+package domain
+
+// scopes of an API key
+const (
+	ScopeCreate = "create"
+	ScopeVerify = "verify"
+	ScopeAdmin  = "admin"
+	// ScopeAudit grants the read-only calls of the admin API
+	ScopeAudit = "audit"
+)
+
+// Scopes lists the scopes an API key can be granted.
+var Scopes = []string{ScopeCreate, ScopeVerify, ScopeAdmin, ScopeAudit}
+
+// APIKey authenticates a server-to-server caller of a tenant. Only the hash of
+// its secret is kept, the secret is shown once when the key is issued.
+type APIKey struct {
+	ID         string   `json:"id"`
+	TenantID   string   `json:"tenantId"`
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	Hash       []byte   `json:"-"`
+	CreatedAt  int64    `json:"createdAt"`
+	LastUsedAt int64    `json:"lastUsedAt,omitempty"`
+	RevokedAt  int64    `json:"revokedAt,omitempty"`
+}
+
+// HasScope reports whether the key is granted one of scopes.
+func (k *APIKey) HasScope(scopes ...string) bool {
+	for _, granted := range k.Scopes {
+		for _, scope := range scopes {
+			if granted == scope {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IssuedAPIKey is a new API key with the key its caller authenticates with.
+type IssuedAPIKey struct {
+	*APIKey
+	Key string `json:"key"`
+}
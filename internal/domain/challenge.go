@@ -1,12 +1,128 @@
 package domain
 
+const (
+	ChallengeStatusPending  = "pending"
+	ChallengeStatusExpired  = "expired"
+	ChallengeStatusNotFound = "notFound"
+	// ChallengeStatusVerified is the final status of a multi-signature
+	// challenge once its threshold of signers signed it
+	ChallengeStatusVerified = "verified"
+)
+
 type Challenge struct {
+	TenantID  string `json:"tenantId"`
 	PublicKey string `json:"publicKey"`
 	Nonce     string `json:"nonce"`
-	ExpiresAt int64  `json:"expiresAt"`
+	// CreatedAt is zero for the challenges created before it was recorded
+	CreatedAt int64 `json:"createdAt,omitempty"`
+	ExpiresAt int64 `json:"expiresAt"`
+	// RequestID is the ID of the request that created the challenge
+	RequestID string `json:"requestId,omitempty"`
+	// Message is the text the challenge asks to sign when its tenant has a
+	// domain, it is rendered again rather than stored. A token carries it in
+	// its msg claim, a detached signature signs its UTF-8 bytes, or those of
+	// the nonce when it has none.
+	Message string `json:"message,omitempty"`
+	// Ciphersuite is the FROST ciphersuite of the group key of a threshold
+	// challenge, its PublicKey
+	Ciphersuite string `json:"ciphersuite,omitempty"`
+	// PublicKeys are the keys of a multi-signature challenge, whose PublicKey
+	// is empty: Threshold of them must sign it. Signers are the keys that
+	// signed it, in the order they did.
+	PublicKeys []string `json:"publicKeys,omitempty"`
+	Threshold  int      `json:"threshold,omitempty"`
+	Signers    []string `json:"signers,omitempty"`
+}
+
+// Verified reports whether a multi-signature challenge has its threshold of signers.
+func (c *Challenge) Verified() bool {
+	return c.Threshold > 0 && len(c.Signers) >= c.Threshold
+}
+
+// SignedMessage is what a detached signature of the challenge signs.
+func (c *Challenge) SignedMessage() []byte {
+	if c.Message == "" {
+		return []byte(c.Nonce)
+	}
+
+	return []byte(c.Message)
 }
 
+// ChallengeValidationResult is the outcome of a verification. A valid result
+// also tells what the token proved, the other fields are empty otherwise.
 type ChallengeValidationResult struct {
 	Valid           bool   `json:"valid"`
 	ValidationError string `json:"validationError"`
+	// KeyFingerprint identifies the public key that signed the token, as in
+	// the logs and the admin API
+	KeyFingerprint string `json:"keyFingerprint,omitempty"`
+	// KeyType is the type of the public key, e.g. EC P-256, and Algorithm the
+	// algorithm of the signature
+	KeyType   string `json:"keyType,omitempty"`
+	Algorithm string `json:"algorithm,omitempty"`
+	// DerivationPath is the path of the child key that signed the token
+	// relative to its extended public key, e.g. 0/5
+	DerivationPath string `json:"derivationPath,omitempty"`
+	// Claims are the claims of the token, registered and custom ones
+	Claims map[string]interface{} `json:"claims,omitempty"`
+	// CreatedAt and ExpiresAt are the times of the challenge, VerifiedAt the
+	// time of the verification, in Unix seconds
+	CreatedAt  int64 `json:"createdAt,omitempty"`
+	ExpiresAt  int64 `json:"expiresAt,omitempty"`
+	VerifiedAt int64 `json:"verifiedAt,omitempty"`
+}
+
+type ChallengeStatus struct {
+	PublicKey string `json:"publicKey"`
+	Nonce     string `json:"nonce"`
+	Status    string `json:"status"`
+	ExpiresAt int64  `json:"expiresAt,omitempty"`
+	// Threshold and Signers are the progress of a multi-signature challenge
+	Threshold int      `json:"threshold,omitempty"`
+	Signers   []string `json:"signers,omitempty"`
+}
+
+// MultisigResult is the progress of a multi-signature challenge after
+// signatures were submitted, with the result of each of them in order.
+type MultisigResult struct {
+	Nonce     string   `json:"nonce"`
+	Verified  bool     `json:"verified"`
+	Threshold int      `json:"threshold"`
+	Signers   []string `json:"signers"`
+	ExpiresAt int64    `json:"expiresAt,omitempty"`
+	// Signatures are the results of the tokens, a valid one added its signer
+	Signatures []*ChallengeValidationResult `json:"signatures"`
+}
+
+// ChallengeFilter selects the challenges of a search. Empty fields match every
+// challenge; the challenges are ordered from the most recently created one.
+type ChallengeFilter struct {
+	KeyFingerprint string
+	Nonce          string
+	// Status is ChallengeStatusPending or ChallengeStatusExpired at Now
+	Status string
+	Now    int64
+	// CreatedFrom and CreatedTo bound the creation time, inclusive
+	CreatedFrom int64
+	CreatedTo   int64
+	// After is the last challenge of the previous page
+	After *ChallengeCursor
+	Limit int
+}
+
+// ChallengeCursor is the position of a challenge in the order of a search.
+type ChallengeCursor struct {
+	CreatedAt int64
+	Nonce     string
+}
+
+// Before reports whether the challenge comes after the cursor in the order of a search.
+func (c *ChallengeCursor) Before(challenge *Challenge) bool {
+	return challenge.CreatedAt < c.CreatedAt || (challenge.CreatedAt == c.CreatedAt && challenge.Nonce < c.Nonce)
+}
+
+// ChallengePage is a page of the challenges of a search, NextCursor is empty on the last page.
+type ChallengePage struct {
+	Challenges []*Challenge `json:"challenges"`
+	NextCursor string       `json:"nextCursor,omitempty"`
 }
@@ -1,7 +1,41 @@
 package domain
 
+import "errors"
+
+// values of the error_code log field
 const (
-	CryptoAPIError  = "CryptoAPIError "
-	BootError       = "BootError "
-	UnexpectedError = "UnexpectedError "
+	BootError       = "BootError"
+	UnexpectedError = "UnexpectedError"
 )
+
+// ErrInvalidPublicKey is returned when a public key cannot be decoded.
+var ErrInvalidPublicKey = errors.New("invalid public key")
+
+// ErrUnknownTenant is returned when a request names a tenant that is not configured.
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+// ErrTenantRequired is returned when a request names no tenant and there is no default one.
+var ErrTenantRequired = errors.New("tenant is required")
+
+// ErrInvalidAPIKey is returned when an API key is malformed, unknown or revoked.
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// ErrAPIKeyNotFound is returned when a tenant has no API key of an ID.
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// ErrInvalidScope is returned when an API key is requested with an unknown scope.
+var ErrInvalidScope = errors.New("invalid scope")
+
+// ErrChallengeNotFound is returned when a tenant has no challenge of a nonce.
+var ErrChallengeNotFound = errors.New("challenge not found")
+
+// ErrInvalidFilter is returned when a search or statistics request is malformed.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// ErrInvalidThreshold is returned when a multi-signature challenge asks for
+// fewer than one signer or more signers than it has public keys.
+var ErrInvalidThreshold = errors.New("invalid threshold")
+
+// ErrInvalidDerivationPath is returned when an extended public key is enrolled
+// with a path pattern or a maximum index it cannot derive.
+var ErrInvalidDerivationPath = errors.New("invalid derivation path")
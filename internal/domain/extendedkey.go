@@ -0,0 +1,12 @@
+package domain
+
+// ExtendedKey is a BIP-32 extended public key enrolled by a tenant. Its
+// challenges are signed by its non-hardened children at the paths of
+// PathPattern, such as 0/*, whose wildcard is an index up to MaxIndex.
+type ExtendedKey struct {
+	TenantID    string `json:"tenantId"`
+	Xpub        string `json:"xpub"`
+	PathPattern string `json:"pathPattern"`
+	MaxIndex    uint32 `json:"maxIndex"`
+	CreatedAt   int64  `json:"createdAt"`
+}
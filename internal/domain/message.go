@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultMessageTemplate renders a message in the spirit of EIP-4361: the
+// domain asking for the signature first, then the statement and the fields
+// binding the message to its challenge.
+const DefaultMessageTemplate = `{{.Domain}} asks you to sign in with your key.
+{{with .Statement}}
+{{.}}
+{{end}}
+{{with .URI}}URI: {{.}}
+{{end}}Nonce: {{.Nonce}}
+Issued At: {{.IssuedAt}}
+Expiration Time: {{.ExpirationTime}}{{with .RequestID}}
+Request ID: {{.}}{{end}}`
+
+// MessageFields are the values a message template renders, the times are
+// RFC 3339 in UTC.
+type MessageFields struct {
+	Domain         string
+	URI            string
+	Statement      string
+	Nonce          string
+	IssuedAt       string
+	ExpirationTime string
+	RequestID      string
+}
+
+// messageNonceCheck is the nonce a template is rendered with to check that it
+// renders the nonce.
+const messageNonceCheck = "message-nonce-check"
+
+// ParseMessageTemplate parses a text/template message. The template must
+// render the nonce, else a signed message would not prove its challenge.
+func ParseMessageTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("message").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, MessageFields{Nonce: messageNonceCheck}); err != nil {
+		return nil, err
+	}
+	if !strings.Contains(rendered.String(), messageNonceCheck) {
+		return nil, errors.New("the message template does not render the nonce")
+	}
+
+	return tmpl, nil
+}
+
+// RenderMessage renders the message a challenge of the tenant asks to sign,
+// empty when the tenant has no message template.
+func RenderMessage(tenant Tenant, challenge *Challenge) (string, error) {
+	if tenant.MessageTemplate == nil {
+		return "", nil
+	}
+
+	var message strings.Builder
+	err := tenant.MessageTemplate.Execute(&message, MessageFields{
+		Domain:         tenant.Domain,
+		URI:            tenant.URI,
+		Statement:      tenant.Statement,
+		Nonce:          challenge.Nonce,
+		IssuedAt:       messageTime(challenge.CreatedAt),
+		ExpirationTime: messageTime(challenge.ExpiresAt),
+		RequestID:      challenge.RequestID,
+	})
+
+	return message.String(), err
+}
+
+func messageTime(unix int64) string {
+	return time.Unix(unix, 0).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,29 @@
+package domain
+
+// events counted by the challenge statistics
+const (
+	StatCreated  = "created"
+	StatVerified = "verified"
+	StatFailed   = "failed"
+)
+
+// HourlyStats counts the challenge events of a tenant within an hour.
+type HourlyStats struct {
+	// Hour is the unix time in seconds the hour starts at
+	Hour     int64 `json:"hour"`
+	Created  int64 `json:"created"`
+	Verified int64 `json:"verified"`
+	Failed   int64 `json:"failed"`
+}
+
+// Add counts an event.
+func (s *HourlyStats) Add(event string, count int64) {
+	switch event {
+	case StatCreated:
+		s.Created += count
+	case StatVerified:
+		s.Verified += count
+	case StatFailed:
+		s.Failed += count
+	}
+}
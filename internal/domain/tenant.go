@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"text/template"
+	"time"
+)
+
+// signature algorithms a tenant can allow
+const (
+	AlgorithmES256 = "ES256"
+	AlgorithmES384 = "ES384"
+	AlgorithmES512 = "ES512"
+	// AlgorithmES256K is ECDSA on secp256k1 with SHA-256, the algorithm of
+	// the keys derived from extended public keys.
+	AlgorithmES256K = "ES256K"
+	// RSA PKCS #1 v1.5 and RSA-PSS, which tokens signed with RSA keys use
+	AlgorithmRS256 = "RS256"
+	AlgorithmRS384 = "RS384"
+	AlgorithmRS512 = "RS512"
+	AlgorithmPS256 = "PS256"
+	AlgorithmPS384 = "PS384"
+	AlgorithmPS512 = "PS512"
+)
+
+// key formats a tenant can allow
+const (
+	// KeyFormatCompressedPEM is a base64 encoded gzip of the hex encoded PEM public key.
+	KeyFormatCompressedPEM = "compressed-pem"
+	// KeyFormatFrost is the hex encoded group key of a FROST threshold group,
+	// which signs the challenges detached.
+	KeyFormatFrost = "frost"
+	// KeyFormatBIP32 is an enrolled BIP-32 extended public key, whose derived
+	// children sign the challenges.
+	KeyFormatBIP32 = "bip32-xpub"
+)
+
+// nonce formats a tenant can choose
+const (
+	// NonceFormatUUIDv4 is a random UUID, the format of the first nonces
+	NonceFormatUUIDv4 = "uuidv4"
+	// NonceFormatUUIDv7 is a UUID that starts with its creation time, so that
+	// the nonces of an index are close to one another
+	NonceFormatUUIDv7       = "uuidv7"
+	NonceFormatBase64URL128 = "base64url-128"
+	NonceFormatBase64URL256 = "base64url-256"
+	// NonceFormatAlphanumeric is made of letters and digits only, as EIP-4361 requires
+	NonceFormatAlphanumeric = "alphanumeric"
+)
+
+// Algorithms and KeyFormats list what the API supports, the policy of a tenant
+// allows a subset of them. A tenant chooses one of the NonceFormats.
+var (
+	Algorithms = []string{
+		AlgorithmES256, AlgorithmES384, AlgorithmES512, AlgorithmES256K,
+		AlgorithmRS256, AlgorithmRS384, AlgorithmRS512, AlgorithmPS256, AlgorithmPS384, AlgorithmPS512,
+	}
+	KeyFormats   = []string{KeyFormatCompressedPEM, KeyFormatFrost, KeyFormatBIP32}
+	NonceFormats = []string{NonceFormatUUIDv4, NonceFormatUUIDv7, NonceFormatBase64URL128, NonceFormatBase64URL256, NonceFormatAlphanumeric}
+)
+
+// Tenant is an application using the API. Its challenges are isolated from
+// the ones of the other tenants and verified against its policy.
+type Tenant struct {
+	ID string
+	// Audiences the aud claim of a token must contain one of, any audience when empty.
+	Audiences       []string
+	NonceTimeToLive time.Duration
+	Algorithms      []string
+	KeyFormats      []string
+	NonceFormat     string
+	// Domain, URI and Statement are rendered by MessageTemplate into the
+	// message of the challenges, which carry none when the template is nil.
+	Domain          string
+	URI             string
+	Statement       string
+	MessageTemplate *template.Template
+}
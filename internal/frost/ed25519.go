@@ -0,0 +1,87 @@
+package frost
+
+import (
+	"crypto/sha512"
+	"errors"
+
+	"github.com/oasisprotocol/curve25519-voi/curve"
+	"github.com/oasisprotocol/curve25519-voi/curve/scalar"
+)
+
+const (
+	ed25519ElementSize = 32
+	ed25519ScalarSize  = 32
+)
+
+// ed25519Suite is FROST(Ed25519, SHA-512), whose signatures verify as the
+// ones of RFC 8032 do.
+type ed25519Suite struct{}
+
+type ed25519Key struct {
+	point   *curve.EdwardsPoint
+	encoded []byte
+}
+
+func (ed25519Suite) decodeKey(key []byte) (groupKey, error) {
+	point, err := decodeEd25519Element(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ed25519Key{point: point, encoded: key}, nil
+}
+
+// decodeEd25519Element decodes a point as RFC 8032 does, and rejects the
+// identity and the points out of the prime-order subgroup.
+func decodeEd25519Element(encoded []byte) (*curve.EdwardsPoint, error) {
+	compressed, err := curve.NewCompressedEdwardsY().SetBytes(encoded)
+	if err != nil {
+		return nil, err
+	}
+	point, err := curve.NewEdwardsPoint().SetCompressedY(compressed)
+	if err != nil {
+		return nil, err
+	}
+	// the decoding accepts a y coordinate that is not reduced
+	if curve.NewCompressedEdwardsY().SetEdwardsPoint(point).Equal(compressed) != 1 {
+		return nil, errors.New("element is not canonical")
+	}
+	if point.IsIdentity() || !point.IsTorsionFree() {
+		return nil, errors.New("element is not of prime order")
+	}
+
+	return point, nil
+}
+
+func (k *ed25519Key) verify(message, signature []byte) error {
+	if len(signature) != ed25519ElementSize+ed25519ScalarSize {
+		return errors.New("signature is not 64 bytes")
+	}
+	r, err := decodeEd25519Element(signature[:ed25519ElementSize])
+	if err != nil {
+		return err
+	}
+	z, err := scalar.NewFromCanonicalBytes(signature[ed25519ElementSize:])
+	if err != nil {
+		return err
+	}
+
+	// the challenge hash of the suite has no context string, as in RFC 8032
+	h := sha512.New()
+	h.Write(signature[:ed25519ElementSize])
+	h.Write(k.encoded)
+	h.Write(message)
+	c, err := scalar.NewFromBytesModOrderWide(h.Sum(nil))
+	if err != nil {
+		return err
+	}
+
+	// [z]B - [c]PK - R, multiplied by the cofactor, is the identity
+	check := curve.NewEdwardsPoint().DoubleScalarMulBasepointVartime(scalar.New().Neg(c), k.point, z)
+	check.Sub(check, r)
+	if !check.MulByCofactor(check).IsIdentity() {
+		return errors.New("signature does not match")
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+// Package frost verifies the signatures of FROST threshold groups (RFC 9591).
+// A group signs with the shares of its members, the signature it aggregates
+// is a single Schnorr signature under the public key of the group.
+package frost
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// the ciphersuites a group key may belong to, named by their context string
+const (
+	Ed25519   = "FROST-ED25519-SHA512-v1"
+	Secp256k1 = "FROST-secp256k1-SHA256-v1"
+)
+
+var (
+	ErrInvalidGroupKey  = errors.New("invalid group key")
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// suite is the group and the challenge hash of a ciphersuite.
+type suite interface {
+	// decodeKey checks that the serialized element is a valid group key.
+	decodeKey(key []byte) (groupKey, error)
+}
+
+type groupKey interface {
+	// verify checks the serialized signature of the message, R then z.
+	verify(message, signature []byte) error
+}
+
+// suites are told apart by the size of their serialized elements, a key of
+// 32 bytes is a point of Ed25519, one of 33 bytes a compressed point of
+// secp256k1.
+var suites = map[int]struct {
+	name string
+	suite
+}{
+	ed25519ElementSize:   {Ed25519, ed25519Suite{}},
+	secp256k1ElementSize: {Secp256k1, secp256k1Suite{}},
+}
+
+// GroupKey is the verification key of a FROST group.
+type GroupKey struct {
+	Ciphersuite string
+	key         groupKey
+}
+
+// ParseGroupKey decodes the hex of the serialized element of a group key, its
+// size tells its ciphersuite.
+func ParseGroupKey(encoded string) (*GroupKey, error) {
+	raw, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidGroupKey, err)
+	}
+	s, found := suites[len(raw)]
+	if !found {
+		return nil, fmt.Errorf("%w: a group key has %d or %d bytes", ErrInvalidGroupKey, ed25519ElementSize, secp256k1ElementSize)
+	}
+	key, err := s.decodeKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidGroupKey, err)
+	}
+
+	return &GroupKey{Ciphersuite: s.name, key: key}, nil
+}
+
+// Verify checks the hex of an aggregated signature of the message.
+func (k *GroupKey) Verify(message []byte, signature string) error {
+	raw, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+	if err := k.key.verify(message, raw); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package frost
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/yawning/secp256k1-voi"
+)
+
+func TestExpandMessageXMD(t *testing.T) {
+	// vectors of RFC 9380, appendix K.1
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	tests := []struct {
+		message string
+		size    int
+		uniform string
+	}{
+		{"", 0x20, "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235"},
+		{"abc", 0x20, "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615"},
+		{"abcdef0123456789", 0x20, "eff31487c770a893cfb36f912fbfcbff40d5661771ca4b2cb4eafe524333f5c1"},
+		{"abc", 0x80, "abba86a6129e366fc877aab32fc4ffc70120d8996c88aee2fe4b32d6c7b6437a647e6c3163d40b76a73cf6a5674ef1d890f95b664ee0afa5359a5c4e07985635bbecbac65d747d3d2da7ec2b8221b17b0ca9dc8a1ac1c07ea6a1e60583e2cb00058e77b7b72a298425cd1b941ad4ec65e8afc50303a22c0f99b0509b4c895f40"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.uniform, hex.EncodeToString(expandMessageXMD(dst, []byte(test.message), test.size)), test.message)
+	}
+}
+
+func TestGroupKey_Vectors(t *testing.T) {
+	// aggregated signatures of participants 1 and 3 of the 2-of-3 groups of
+	// RFC 9591, appendix E
+	tests := []struct {
+		ciphersuite string
+		groupKey    string
+		signature   string
+	}{
+		{
+			ciphersuite: Ed25519,
+			groupKey:    "15d21ccd7ee42959562fc8aa63224c8851fb3ec85a3faf66040d380fb9738673",
+			signature:   "36282629c383bb820a88b71cae937d41f2f2adfcc3d02e55507e2fb9e2dd3cbebd9d2b0844e49ae0f3fa935161e1419aab7b47d21a37ebeae1f17d4987b3160b",
+		},
+		{
+			ciphersuite: Secp256k1,
+			groupKey:    "02f37c34b66ced1fb51c34a90bdae006901f10625cc06c4f64663b0eae87d87b4f",
+			signature:   "0205b6d04d3774c8929413e3c76024d54149c372d57aae62574ed74319b5ea14d0c65dde8492a7471437e6c2fe3da49b90d23f642b5c6dbe7e36089f096dd97324",
+		},
+	}
+	message, err := hex.DecodeString("74657374")
+	require.NoError(t, err)
+	for _, test := range tests {
+		t.Run(test.ciphersuite, func(t *testing.T) {
+			key, err := ParseGroupKey(test.groupKey)
+			require.NoError(t, err)
+			assert.Equal(t, test.ciphersuite, key.Ciphersuite)
+			assert.NoError(t, key.Verify(message, test.signature))
+			assert.ErrorIs(t, key.Verify([]byte("tests"), test.signature), ErrInvalidSignature)
+
+			// z is the last byte, R the first ones
+			tampered, err := hex.DecodeString(test.signature)
+			require.NoError(t, err)
+			tampered[len(tampered)-2] ^= 1
+			assert.ErrorIs(t, key.Verify(message, hex.EncodeToString(tampered)), ErrInvalidSignature)
+		})
+	}
+}
+
+func TestGroupKey_Ed25519(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	message := []byte("example.com asks you to sign in with your key.")
+
+	key, err := ParseGroupKey(hex.EncodeToString(pub))
+	require.NoError(t, err)
+	assert.Equal(t, Ed25519, key.Ciphersuite)
+
+	// an aggregated signature verifies as one of RFC 8032
+	signature := hex.EncodeToString(ed25519.Sign(priv, message))
+	assert.NoError(t, key.Verify(message, signature))
+	assert.ErrorIs(t, key.Verify([]byte("other message"), signature), ErrInvalidSignature)
+	assert.ErrorIs(t, key.Verify(message, signature[:64]), ErrInvalidSignature)
+
+	_, otherPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	assert.ErrorIs(t, key.Verify(message, hex.EncodeToString(ed25519.Sign(otherPriv, message))), ErrInvalidSignature)
+}
+
+func TestGroupKey_Secp256k1(t *testing.T) {
+	message := []byte("example.com asks you to sign in with your key.")
+	// a 2-of-3 group of a trusted dealer, f(x) = secret + a*x
+	secret, a := randomScalar(t), randomScalar(t)
+	share := func(i uint64) *secp256k1.Scalar {
+		x := secp256k1.NewScalarFromUint64(i)
+		return secp256k1.NewScalar().Add(secret, secp256k1.NewScalar().Multiply(a, x))
+	}
+	pub := secp256k1.NewIdentityPoint().ScalarBaseMult(secret)
+
+	key, err := ParseGroupKey(hex.EncodeToString(pub.CompressedBytes()))
+	require.NoError(t, err)
+	assert.Equal(t, Secp256k1, key.Ciphersuite)
+
+	// members 1 and 3 sign, weighing their shares with their Lagrange coefficients
+	sign := func(message []byte) string {
+		signers := []uint64{1, 3}
+		nonces := []*secp256k1.Scalar{randomScalar(t), randomScalar(t)}
+		r := secp256k1.NewIdentityPoint()
+		for _, k := range nonces {
+			r.Add(r, secp256k1.NewIdentityPoint().ScalarBaseMult(k))
+		}
+		input := append(append(r.CompressedBytes(), pub.CompressedBytes()...), message...)
+		c, err := secp256k1Challenge(input)
+		require.NoError(t, err)
+
+		z := secp256k1.NewScalar()
+		for i, member := range signers {
+			lambda := secp256k1.NewScalarFromUint64(1)
+			for _, other := range signers {
+				if other == member {
+					continue
+				}
+				// other / (other - member)
+				x := secp256k1.NewScalarFromUint64(other)
+				denominator := secp256k1.NewScalar().Subtract(x, secp256k1.NewScalarFromUint64(member))
+				lambda.Multiply(lambda, secp256k1.NewScalar().Multiply(x, secp256k1.NewScalar().Invert(denominator)))
+			}
+			zi := secp256k1.NewScalar().Multiply(secp256k1.NewScalar().Multiply(lambda, share(member)), c)
+			z.Add(z, zi.Add(zi, nonces[i]))
+		}
+
+		return hex.EncodeToString(append(r.CompressedBytes(), z.Bytes()...))
+	}
+
+	signature := sign(message)
+	assert.NoError(t, key.Verify(message, signature))
+	assert.ErrorIs(t, key.Verify([]byte("other message"), signature), ErrInvalidSignature)
+	assert.ErrorIs(t, key.Verify(message, sign([]byte("other message"))), ErrInvalidSignature)
+	assert.ErrorIs(t, key.Verify(message, "not hex"), ErrInvalidSignature)
+}
+
+func TestParseGroupKey(t *testing.T) {
+	tests := map[string]string{
+		"not hex":             "not hex",
+		"unknown size":        strings.Repeat("00", 48),
+		"ed25519 identity":    "01" + strings.Repeat("00", 31),
+		"ed25519 small order": "26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc05",
+		"ed25519 not reduced": "edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		"secp256k1 off curve": "02" + strings.Repeat("00", 32),
+		"secp256k1 prefix":    "05" + strings.Repeat("11", 32),
+	}
+	for name, encoded := range tests {
+		_, err := ParseGroupKey(encoded)
+		assert.ErrorIs(t, err, ErrInvalidGroupKey, name)
+	}
+}
+
+func randomScalar(t *testing.T) *secp256k1.Scalar {
+	var b [secp256k1.ScalarSize]byte
+	_, err := rand.Read(b[:])
+	require.NoError(t, err)
+	s, _ := secp256k1.NewScalarFromBytes(&b)
+
+	return s
+}
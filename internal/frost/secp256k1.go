@@ -0,0 +1,106 @@
+package frost
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	"gitlab.com/yawning/secp256k1-voi"
+)
+
+const (
+	secp256k1ElementSize = secp256k1.CompressedPointSize
+	secp256k1ScalarSize  = secp256k1.ScalarSize
+	// secp256k1HashSize is the L of hash_to_field, 48 bytes for a 128-bit security level
+	secp256k1HashSize = 48
+)
+
+// secp256k1Order is the order n of the group.
+var secp256k1Order, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+
+// secp256k1Suite is FROST(secp256k1, SHA-256).
+type secp256k1Suite struct{}
+
+type secp256k1Key struct {
+	point   *secp256k1.Point
+	encoded []byte
+}
+
+func (secp256k1Suite) decodeKey(key []byte) (groupKey, error) {
+	point, err := secp256k1.NewIdentityPoint().SetCompressedBytes(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &secp256k1Key{point: point, encoded: key}, nil
+}
+
+func (k *secp256k1Key) verify(message, signature []byte) error {
+	if len(signature) != secp256k1ElementSize+secp256k1ScalarSize {
+		return errors.New("signature is not 65 bytes")
+	}
+	// a compressed point is never the identity
+	r, err := secp256k1.NewIdentityPoint().SetCompressedBytes(signature[:secp256k1ElementSize])
+	if err != nil {
+		return err
+	}
+	z, err := secp256k1.NewScalarFromCanonicalBytes((*[secp256k1ScalarSize]byte)(signature[secp256k1ElementSize:]))
+	if err != nil {
+		return err
+	}
+
+	input := make([]byte, 0, len(signature)+len(k.encoded)+len(message))
+	input = append(append(append(input, signature[:secp256k1ElementSize]...), k.encoded...), message...)
+	c, err := secp256k1Challenge(input)
+	if err != nil {
+		return err
+	}
+
+	// [z]G - [c]PK is R
+	check := secp256k1.NewIdentityPoint().DoubleScalarMultBasepointVartime(z, secp256k1.NewScalar().Negate(c), k.point)
+	if check.Equal(r) != 1 {
+		return errors.New("signature does not match")
+	}
+
+	return nil
+}
+
+// secp256k1Challenge is H2 of the suite: hash_to_field of RFC 9380 with
+// expand_message_xmd, which reduces 48 bytes modulo the order of the group.
+func secp256k1Challenge(input []byte) (*secp256k1.Scalar, error) {
+	uniform := expandMessageXMD([]byte(Secp256k1+"chal"), input, secp256k1HashSize)
+	var reduced [secp256k1ScalarSize]byte
+	new(big.Int).Mod(new(big.Int).SetBytes(uniform), secp256k1Order).FillBytes(reduced[:])
+
+	return secp256k1.NewScalarFromCanonicalBytes(&reduced)
+}
+
+// expandMessageXMD is expand_message_xmd of RFC 9380 with SHA-256, for
+// outputs of up to 255 blocks and tags of up to 255 bytes.
+func expandMessageXMD(dst, message []byte, size int) []byte {
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+	blocks := (size + sha256.Size - 1) / sha256.Size
+
+	h := sha256.New()
+	h.Write(make([]byte, h.BlockSize()))
+	h.Write(message)
+	h.Write([]byte{byte(size >> 8), byte(size), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	uniform := make([]byte, 0, blocks*sha256.Size)
+	b := make([]byte, sha256.Size)
+	for i := 1; i <= blocks; i++ {
+		h.Reset()
+		for j := range b {
+			b[j] ^= b0[j]
+		}
+		h.Write(b)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		b = h.Sum(nil)
+		uniform = append(uniform, b...)
+	}
+
+	return uniform[:size]
+}
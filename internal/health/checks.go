@@ -0,0 +1,89 @@
+package health
+
+import (
+	"context"
+	"crypto-project-1/internal/worker"
+	"database/sql"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SchemaVersion reads the version of the database schema, see migrate.Migrator.
+type SchemaVersion interface {
+	Version(ctx context.Context) (int, error)
+	Latest() int
+}
+
+// DatabaseCheck pings the database.
+func DatabaseCheck(db *sql.DB) Check {
+	return Check{
+		Name: "database",
+		Run: func(ctx context.Context) (interface{}, error) {
+			if err := db.PingContext(ctx); err != nil {
+				return nil, err
+			}
+			stats := db.Stats()
+
+			return map[string]int{
+				"openConnections": stats.OpenConnections,
+				"inUse":           stats.InUse,
+				"idle":            stats.Idle,
+			}, nil
+		},
+	}
+}
+
+// RedisCheck pings redis.
+func RedisCheck(client *redis.Client) Check {
+	return Check{
+		Name: "redis",
+		Run: func(ctx context.Context) (interface{}, error) {
+			if err := client.Ping(ctx).Err(); err != nil {
+				return nil, err
+			}
+			stats := client.PoolStats()
+
+			return map[string]uint32{
+				"totalConnections": stats.TotalConns,
+				"idle":             stats.IdleConns,
+			}, nil
+		},
+	}
+}
+
+// SchemaCheck verifies that every migration known to the API is applied.
+func SchemaCheck(schema SchemaVersion) Check {
+	return Check{
+		Name: "schema",
+		Run: func(ctx context.Context) (interface{}, error) {
+			version, err := schema.Version(ctx)
+			if err != nil {
+				return nil, err
+			}
+			detail := map[string]int{"version": version, "expected": schema.Latest()}
+			if version != schema.Latest() {
+				return detail, fmt.Errorf("schema version is %d, expected %d", version, schema.Latest())
+			}
+
+			return detail, nil
+		},
+	}
+}
+
+// WorkersCheck reports the state of the background workers and fails when one of them failed.
+func WorkersCheck(workers *worker.Group) Check {
+	return Check{
+		Name: "workers",
+		Run: func(ctx context.Context) (interface{}, error) {
+			states := workers.States()
+			for name, state := range states {
+				if state == worker.StateFailed {
+					return states, fmt.Errorf("worker %s failed", name)
+				}
+			}
+
+			return states, nil
+		},
+	}
+}
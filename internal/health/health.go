@@ -0,0 +1,113 @@
+// Package health runs the readiness checks of the API's dependencies.
+package health
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Check verifies one dependency. The returned detail is reported as is.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) (detail interface{}, err error)
+}
+
+type CheckResult struct {
+	Status     string      `json:"status"`
+	Detail     interface{} `json:"detail,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	DurationMs int64       `json:"durationMs"`
+}
+
+type Report struct {
+	Status    string                 `json:"status"`
+	Checks    map[string]CheckResult `json:"checks"`
+	CheckedAt time.Time              `json:"checkedAt"`
+}
+
+// Checker runs all checks concurrently, each one bounded by the configured
+// timeout, and caches the report for a short interval so that frequent probes
+// do not hit the dependencies every time.
+type Checker struct {
+	checks   []Check
+	timeout  time.Duration
+	cacheTTL time.Duration
+	now      func() time.Time
+
+	mu     sync.Mutex
+	cached *Report
+}
+
+func NewChecker(cfg config.HealthConfig, now func() time.Time, checks ...Check) *Checker {
+	return &Checker{
+		checks:   checks,
+		timeout:  cfg.CheckTimeout,
+		cacheTTL: cfg.CacheTTL,
+		now:      now,
+	}
+}
+
+// Check returns the cached report if it is recent enough, or runs the checks.
+// They run detached from the cancellation of ctx, bounded by their timeout
+// only: the report of a probe that gave up is cached for the others.
+func (c *Checker) Check(ctx context.Context) *Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cached != nil && c.now().Sub(c.cached.CheckedAt) < c.cacheTTL {
+		return c.cached
+	}
+
+	report := &Report{
+		Status:    StatusOK,
+		Checks:    make(map[string]CheckResult, len(c.checks)),
+		CheckedAt: c.now(),
+	}
+	ctx = context.WithoutCancel(ctx)
+	results := make([]CheckResult, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = c.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for i, check := range c.checks {
+		report.Checks[check.Name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusUnavailable
+		}
+	}
+	c.cached = report
+
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := check.Run(ctx)
+	result := CheckResult{
+		Status:     StatusOK,
+		Detail:     detail,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = StatusUnavailable
+		result.Error = err.Error()
+	}
+
+	return result
+}
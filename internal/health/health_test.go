@@ -0,0 +1,104 @@
+package health_test
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/health"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker_Check(t *testing.T) {
+	timeNow := time.Now()
+	now := func() time.Time { return timeNow }
+	calls := 0
+	failing := false
+	check := health.Check{
+		Name: "dependency",
+		Run: func(ctx context.Context) (interface{}, error) {
+			calls++
+			if failing {
+				return nil, errors.New("unreachable")
+			}
+			return "reachable", nil
+		},
+	}
+	slow := health.Check{
+		Name: "slow",
+		Run: func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	checker := health.NewChecker(config.HealthConfig{CheckTimeout: time.Millisecond * 10, CacheTTL: time.Second}, now, check)
+	report := checker.Check(context.Background())
+	assert.Equal(t, health.StatusOK, report.Status)
+	assert.Equal(t, "reachable", report.Checks["dependency"].Detail)
+
+	// the report is cached until the ttl passes
+	failing = true
+	assert.Equal(t, health.StatusOK, checker.Check(context.Background()).Status)
+	assert.Equal(t, 1, calls)
+
+	timeNow = timeNow.Add(time.Second)
+	report = checker.Check(context.Background())
+	assert.Equal(t, health.StatusUnavailable, report.Status)
+	assert.Equal(t, "unreachable", report.Checks["dependency"].Error)
+
+	// checks are bounded by the timeout
+	checker = health.NewChecker(config.HealthConfig{CheckTimeout: time.Millisecond * 10}, now, slow)
+	report = checker.Check(context.Background())
+	assert.Equal(t, health.StatusUnavailable, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+
+	// a probe that gave up does not cache its cancellation
+	contextual := health.Check{
+		Name: "contextual",
+		Run: func(ctx context.Context) (interface{}, error) {
+			return nil, ctx.Err()
+		},
+	}
+	checker = health.NewChecker(config.HealthConfig{CheckTimeout: time.Second, CacheTTL: time.Second}, now, contextual)
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	report = checker.Check(cancelled)
+	assert.Equal(t, health.StatusOK, report.Status)
+	assert.Equal(t, health.StatusOK, checker.Check(context.Background()).Status)
+}
+
+type schemaVersion struct {
+	version int
+	latest  int
+}
+
+func (s schemaVersion) Version(context.Context) (int, error) { return s.version, nil }
+
+func (s schemaVersion) Latest() int { return s.latest }
+
+func TestSchemaCheck(t *testing.T) {
+	result, err := health.SchemaCheck(schemaVersion{version: 2, latest: 2}).Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"version": 2, "expected": 2}, result)
+
+	_, err = health.SchemaCheck(schemaVersion{version: 1, latest: 2}).Run(context.Background())
+	assert.EqualError(t, err, "schema version is 1, expected 2")
+}
+
+func TestRedisCheck(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	_, err := health.RedisCheck(client).Run(context.Background())
+	assert.NoError(t, err)
+
+	server.Close()
+	_, err = health.RedisCheck(client).Run(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+// Package logging builds the structured logger of the API and binds it to the
+// requests.
+//
+// Every layer receives a *logrus.Entry and logs through FromContext, so that
+// the entries of a request carry its request ID. Tokens and public keys are
+// redacted from the entries before they are written, see Redact.
+package logging
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+
+	"github.com/sirupsen/logrus"
+)
+
+// field names shared by the entries of every layer
+const (
+	FieldRequestID      = "request_id"
+	FieldTenant         = "tenant"
+	FieldAPIKey         = "api_key_id"
+	FieldTraceID        = "trace_id"
+	FieldKeyFingerprint = "key_fp"
+	FieldNonce          = "nonce"
+	FieldOutcome        = "outcome"
+	FieldErrorCode      = "error_code"
+	FieldWorker         = "worker"
+)
+
+type contextKey struct{}
+
+// New creates the logger writing to out with the level and format of cfg.
+func New(cfg config.LogConfig, out io.Writer) (*logrus.Entry, error) {
+	level, err := logrus.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var formatter logrus.Formatter = &logrus.JSONFormatter{}
+	if cfg.Format == "text" {
+		formatter = &logrus.TextFormatter{DisableColors: true, FullTimestamp: true}
+	}
+
+	log := logrus.New()
+	log.SetOutput(out)
+	log.SetLevel(level)
+	log.SetFormatter(&redactingFormatter{next: formatter})
+
+	return logrus.NewEntry(log), nil
+}
+
+// WithLogger returns a copy of ctx carrying log.
+func WithLogger(ctx context.Context, log *logrus.Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, log)
+}
+
+// FromContext returns the logger bound to ctx by the request middleware, or
+// fallback when ctx carries none.
+func FromContext(ctx context.Context, fallback *logrus.Entry) *logrus.Entry {
+	if log, ok := ctx.Value(contextKey{}).(*logrus.Entry); ok {
+		return log
+	}
+
+	return fallback
+}
+
+// RequestID returns the request ID of the logger bound to ctx by the request
+// middleware, empty when ctx carries none.
+func RequestID(ctx context.Context) string {
+	log, ok := ctx.Value(contextKey{}).(*logrus.Entry)
+	if !ok {
+		return ""
+	}
+	id, _ := log.Data[FieldRequestID].(string)
+
+	return id
+}
+
+// KeyFingerprint identifies a public key in the logs without writing the key.
+func KeyFingerprint(pubKey string) string {
+	sum := sha256.Sum256([]byte(pubKey))
+
+	return hex.EncodeToString(sum[:8])
+}
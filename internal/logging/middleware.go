@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDHeader carries the request ID, it is propagated from the caller
+// when valid and returned in the response.
+const RequestIDHeader = "X-Request-ID"
+
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// requestID returns the request ID sent by the caller, or a new one when it
+// sent none or one that could be used to forge log lines.
+func requestID(sent string) string {
+	if validRequestID.MatchString(sent) {
+		return sent
+	}
+
+	return uuid.NewString()
+}
+
+func requestLogger(ctx context.Context, log *logrus.Entry, id string) *logrus.Entry {
+	log = log.WithField(FieldRequestID, id)
+	if span := trace.SpanContextFromContext(ctx); span.HasTraceID() {
+		log = log.WithField(FieldTraceID, span.TraceID().String())
+	}
+
+	return log
+}
+
+// Middleware binds a logger carrying the request ID to every HTTP request and
+// logs the request once it is served.
+func Middleware(log *logrus.Entry) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			start := time.Now()
+			request := ctx.Request()
+			id := requestID(request.Header.Get(RequestIDHeader))
+			ctx.Response().Header().Set(RequestIDHeader, id)
+			requestLog := requestLogger(request.Context(), log, id)
+			ctx.SetRequest(request.WithContext(WithLogger(request.Context(), requestLog)))
+
+			err := next(ctx)
+			if err != nil {
+				// let echo write the error response so that its status is recorded
+				ctx.Error(err)
+			}
+
+			requestLog.WithFields(logrus.Fields{
+				"method":     request.Method,
+				"route":      ctx.Path(),
+				"status":     ctx.Response().Status,
+				"latency_ms": time.Since(start).Milliseconds(),
+			}).Info("request served")
+
+			return nil
+		}
+	}
+}
+
+// UnaryServerInterceptor binds a logger carrying the request ID of the
+// x-request-id metadata to every unary call and logs the call once served.
+func UnaryServerInterceptor(log *logrus.Entry) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx, requestLog := bindRPCLogger(ctx, log)
+		response, err := handler(ctx, request)
+		logRPC(requestLog, info.FullMethod, start, err)
+
+		return response, err
+	}
+}
+
+// StreamServerInterceptor binds a logger carrying the request ID of the
+// x-request-id metadata to every streaming call and logs the call once served.
+func StreamServerInterceptor(log *logrus.Entry) grpc.StreamServerInterceptor {
+	return func(server interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, requestLog := bindRPCLogger(stream.Context(), log)
+		err := handler(server, &loggedStream{ServerStream: stream, ctx: ctx})
+		logRPC(requestLog, info.FullMethod, start, err)
+
+		return err
+	}
+}
+
+type loggedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *loggedStream) Context() context.Context {
+	return s.ctx
+}
+
+func bindRPCLogger(ctx context.Context, log *logrus.Entry) (context.Context, *logrus.Entry) {
+	var sent string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDHeader); len(values) > 0 {
+			sent = values[0]
+		}
+	}
+	id := requestID(sent)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id))
+	requestLog := requestLogger(ctx, log, id)
+
+	return WithLogger(ctx, requestLog), requestLog
+}
+
+func logRPC(log *logrus.Entry, method string, start time.Time, err error) {
+	log.WithFields(logrus.Fields{
+		"method":     method,
+		"code":       status.Code(err).String(),
+		"latency_ms": time.Since(start).Milliseconds(),
+	}).Info("call served")
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/sirupsen/logrus"
+)
+
+const redacted = "[REDACTED]"
+
+// fields whose value is never written
+var secretFields = map[string]bool{
+	"token":         true,
+	"signed_token":  true,
+	"pub_key":       true,
+	"public_key":    true,
+	"password":      true,
+	"authorization": true,
+	"body":          true,
+}
+
+var secretPatterns = []*regexp.Regexp{
+	// JWT
+	regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*`),
+	// base64 gzip compressed public key
+	regexp.MustCompile(`H4sI[A-Za-z0-9+/]{16,}={0,2}`),
+	// PEM block and its hex encoding
+	regexp.MustCompile(`-----BEGIN [A-Z ]+-----[\s\S]*?-----END [A-Z ]+-----`),
+	regexp.MustCompile(`(?i)2d2d2d2d2d424547494e[0-9a-f]+`),
+}
+
+// Redact replaces the tokens and keys found in s.
+func Redact(s string) string {
+	for _, pattern := range secretPatterns {
+		s = pattern.ReplaceAllString(s, redacted)
+	}
+
+	return s
+}
+
+// redactingFormatter redacts the message and fields of the entries before
+// handing them to the next formatter.
+type redactingFormatter struct {
+	next logrus.Formatter
+}
+
+func (f *redactingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data := make(logrus.Fields, len(entry.Data))
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			data[key] = Redact(v)
+		case error:
+			data[key] = Redact(v.Error())
+		case fmt.Stringer:
+			data[key] = Redact(v.String())
+		default:
+			data[key] = value
+		}
+		if secretFields[key] {
+			data[key] = redacted
+		}
+	}
+
+	// the entry is shared with the hooks, format a copy
+	clean := *entry
+	clean.Data = data
+	clean.Message = Redact(entry.Message)
+
+	return f.next.Format(&clean)
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/repository"
+	"time"
+)
+
+// apiKeyRepository times the queries of the wrapped repository.
+type apiKeyRepository struct {
+	next    repository.APIKeyRepository
+	metrics *Metrics
+}
+
+func NewAPIKeyRepository(next repository.APIKeyRepository, metrics *Metrics) repository.APIKeyRepository {
+	return &apiKeyRepository{
+		next:    next,
+		metrics: metrics,
+	}
+}
+
+func (r *apiKeyRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) error {
+	start := time.Now()
+	err := r.next.CreateAPIKey(ctx, key)
+	r.metrics.observeQuery("CreateAPIKey", start, err)
+
+	return err
+}
+
+func (r *apiKeyRepository) GetAPIKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	start := time.Now()
+	key, err := r.next.GetAPIKey(ctx, id)
+	r.metrics.observeQuery("GetAPIKey", start, err)
+
+	return key, err
+}
+
+func (r *apiKeyRepository) ListAPIKeys(ctx context.Context, tenantID string) ([]*domain.APIKey, error) {
+	start := time.Now()
+	keys, err := r.next.ListAPIKeys(ctx, tenantID)
+	r.metrics.observeQuery("ListAPIKeys", start, err)
+
+	return keys, err
+}
+
+func (r *apiKeyRepository) RevokeAPIKey(ctx context.Context, tenantID, id string, revokedAt int64) (bool, error) {
+	start := time.Now()
+	revoked, err := r.next.RevokeAPIKey(ctx, tenantID, id, revokedAt)
+	r.metrics.observeQuery("RevokeAPIKey", start, err)
+
+	return revoked, err
+}
+
+func (r *apiKeyRepository) TouchAPIKey(ctx context.Context, id string, usedAt int64) error {
+	start := time.Now()
+	err := r.next.TouchAPIKey(ctx, id, usedAt)
+	r.metrics.observeQuery("TouchAPIKey", start, err)
+
+	return err
+}
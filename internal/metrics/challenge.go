@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/service"
+	"time"
+)
+
+// challengeRepository times the queries of the wrapped repository.
+type challengeRepository struct {
+	next    repository.ChallengeRepository
+	metrics *Metrics
+}
+
+func NewChallengeRepository(next repository.ChallengeRepository, metrics *Metrics) repository.ChallengeRepository {
+	return &challengeRepository{
+		next:    next,
+		metrics: metrics,
+	}
+}
+
+func (r *challengeRepository) GetChallenges(ctx context.Context, tenantID, pubKey, nonce string) ([]*domain.Challenge, error) {
+	start := time.Now()
+	challenges, err := r.next.GetChallenges(ctx, tenantID, pubKey, nonce)
+	r.metrics.observeQuery("GetChallenges", start, err)
+
+	return challenges, err
+}
+
+func (r *challengeRepository) CreateChallenge(ctx context.Context, tenantID, pubKey, nonce, requestID string, createdAt, expiresAt int64) (*domain.Challenge, error) {
+	start := time.Now()
+	challenge, err := r.next.CreateChallenge(ctx, tenantID, pubKey, nonce, requestID, createdAt, expiresAt)
+	r.metrics.observeQuery("CreateChallenge", start, err)
+
+	return challenge, err
+}
+
+func (r *challengeRepository) ConsumeChallenge(ctx context.Context, tenantID, pubKey, nonce string) (*domain.Challenge, error) {
+	start := time.Now()
+	challenge, err := r.next.ConsumeChallenge(ctx, tenantID, pubKey, nonce)
+	r.metrics.observeQuery("ConsumeChallenge", start, err)
+
+	return challenge, err
+}
+
+func (r *challengeRepository) SearchChallenges(ctx context.Context, tenantID string, filter domain.ChallengeFilter) ([]*domain.Challenge, error) {
+	start := time.Now()
+	challenges, err := r.next.SearchChallenges(ctx, tenantID, filter)
+	r.metrics.observeQuery("SearchChallenges", start, err)
+
+	return challenges, err
+}
+
+func (r *challengeRepository) ExpireChallenge(ctx context.Context, tenantID, nonce string, expiresAt int64) (bool, error) {
+	start := time.Now()
+	expired, err := r.next.ExpireChallenge(ctx, tenantID, nonce, expiresAt)
+	r.metrics.observeQuery("ExpireChallenge", start, err)
+
+	return expired, err
+}
+
+// challengeService counts created challenges and verification outcomes of the wrapped service.
+type challengeService struct {
+	next    service.ChallengeService
+	metrics *Metrics
+}
+
+func NewChallengeService(next service.ChallengeService, metrics *Metrics) service.ChallengeService {
+	return &challengeService{
+		next:    next,
+		metrics: metrics,
+	}
+}
+
+func (s *challengeService) CreateChallenge(ctx context.Context, tenantID, pubKey string) (*domain.Challenge, error) {
+	challenge, err := s.next.CreateChallenge(ctx, tenantID, pubKey)
+	if err == nil {
+		s.metrics.challengesCreated.Inc()
+	}
+
+	return challenge, err
+}
+
+func (s *challengeService) VerifyChallenge(ctx context.Context, tenantID, signedToken string) (*domain.ChallengeValidationResult, error) {
+	result, err := s.next.VerifyChallenge(ctx, tenantID, signedToken)
+	outcome, reason := verificationOutcome(result, err)
+	s.metrics.verifications.WithLabelValues(outcome, reason).Inc()
+
+	return result, err
+}
+
+func (s *challengeService) GetChallengeStatus(ctx context.Context, tenantID, pubKey, nonce string) (*domain.ChallengeStatus, error) {
+	return s.next.GetChallengeStatus(ctx, tenantID, pubKey, nonce)
+}
+
+func (s *challengeService) CreateMultisigChallenge(ctx context.Context, tenantID string, pubKeys []string, threshold int) (*domain.Challenge, error) {
+	challenge, err := s.next.CreateMultisigChallenge(ctx, tenantID, pubKeys, threshold)
+	if err == nil {
+		s.metrics.challengesCreated.Inc()
+	}
+
+	return challenge, err
+}
+
+// SignMultisigChallenge counts a verification per token, or an error.
+func (s *challengeService) SignMultisigChallenge(ctx context.Context, tenantID, nonce string, tokens []string) (*domain.MultisigResult, error) {
+	result, err := s.next.SignMultisigChallenge(ctx, tenantID, nonce, tokens)
+	if err != nil {
+		outcome, reason := verificationOutcome(nil, err)
+		s.metrics.verifications.WithLabelValues(outcome, reason).Inc()
+		return result, err
+	}
+	for _, signature := range result.Signatures {
+		outcome, reason := verificationOutcome(signature, nil)
+		s.metrics.verifications.WithLabelValues(outcome, reason).Inc()
+	}
+
+	return result, nil
+}
+
+func (s *challengeService) CreateFrostChallenge(ctx context.Context, tenantID, groupKey string) (*domain.Challenge, error) {
+	challenge, err := s.next.CreateFrostChallenge(ctx, tenantID, groupKey)
+	if err == nil {
+		s.metrics.challengesCreated.Inc()
+	}
+
+	return challenge, err
+}
+
+func (s *challengeService) VerifyFrostSignature(ctx context.Context, tenantID, groupKey, nonce, signature string) (*domain.ChallengeValidationResult, error) {
+	result, err := s.next.VerifyFrostSignature(ctx, tenantID, groupKey, nonce, signature)
+	outcome, reason := verificationOutcome(result, err)
+	s.metrics.verifications.WithLabelValues(outcome, reason).Inc()
+
+	return result, err
+}
+
+func (s *challengeService) EnrollExtendedKey(ctx context.Context, tenantID, xpub, pathPattern string, maxIndex uint32) (*domain.ExtendedKey, error) {
+	return s.next.EnrollExtendedKey(ctx, tenantID, xpub, pathPattern, maxIndex)
+}
+
+func verificationOutcome(result *domain.ChallengeValidationResult, err error) (string, string) {
+	switch {
+	case err != nil:
+		return OutcomeError, ReasonInternal
+	case result.Valid:
+		return OutcomeValid, ReasonNone
+	case result.ValidationError == service.ValidationErrorInvalidNonce:
+		return OutcomeInvalid, ReasonInvalidNonce
+	case result.ValidationError == service.ValidationErrorExpiredNonce:
+		return OutcomeInvalid, ReasonExpiredNonce
+	case result.ValidationError == service.ValidationErrorInvalidMessage:
+		return OutcomeInvalid, ReasonInvalidMessage
+	case result.ValidationError == service.ValidationErrorUnknownSigner:
+		return OutcomeInvalid, ReasonUnknownSigner
+	case result.ValidationError == service.ValidationErrorDuplicateSigner:
+		return OutcomeInvalid, ReasonDuplicateSigner
+	default:
+		return OutcomeInvalid, ReasonInvalidToken
+	}
+}
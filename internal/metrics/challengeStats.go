@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/repository"
+	"time"
+)
+
+// challengeStatsRepository times the queries of the wrapped repository.
+type challengeStatsRepository struct {
+	next    repository.ChallengeStatsRepository
+	metrics *Metrics
+}
+
+func NewChallengeStatsRepository(next repository.ChallengeStatsRepository, metrics *Metrics) repository.ChallengeStatsRepository {
+	return &challengeStatsRepository{
+		next:    next,
+		metrics: metrics,
+	}
+}
+
+func (r *challengeStatsRepository) AddStats(ctx context.Context, tenantID string, hour int64, event string) error {
+	start := time.Now()
+	err := r.next.AddStats(ctx, tenantID, hour, event)
+	r.metrics.observeQuery("AddStats", start, err)
+
+	return err
+}
+
+func (r *challengeStatsRepository) GetStats(ctx context.Context, tenantID string, from, to int64) ([]*domain.HourlyStats, error) {
+	start := time.Now()
+	stats, err := r.next.GetStats(ctx, tenantID, from, to)
+	r.metrics.observeQuery("GetStats", start, err)
+
+	return stats, err
+}
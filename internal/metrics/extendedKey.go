@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/repository"
+	"time"
+)
+
+// extendedKeyRepository times the queries of the wrapped repository.
+type extendedKeyRepository struct {
+	next    repository.ExtendedKeyRepository
+	metrics *Metrics
+}
+
+func NewExtendedKeyRepository(next repository.ExtendedKeyRepository, metrics *Metrics) repository.ExtendedKeyRepository {
+	return &extendedKeyRepository{
+		next:    next,
+		metrics: metrics,
+	}
+}
+
+func (r *extendedKeyRepository) EnrollExtendedKey(ctx context.Context, key *domain.ExtendedKey) error {
+	start := time.Now()
+	err := r.next.EnrollExtendedKey(ctx, key)
+	r.metrics.observeQuery("EnrollExtendedKey", start, err)
+
+	return err
+}
+
+func (r *extendedKeyRepository) GetExtendedKey(ctx context.Context, tenantID, xpub string) (*domain.ExtendedKey, error) {
+	start := time.Now()
+	key, err := r.next.GetExtendedKey(ctx, tenantID, xpub)
+	r.metrics.observeQuery("GetExtendedKey", start, err)
+
+	return key, err
+}
@@ -0,0 +1,128 @@
+// Package metrics exposes the Prometheus metrics of the API.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// verification outcomes and failure reasons
+const (
+	OutcomeValid   = "valid"
+	OutcomeInvalid = "invalid"
+	OutcomeError   = "error"
+
+	ReasonNone            = "none"
+	ReasonInvalidToken    = "invalid_token"
+	ReasonInvalidNonce    = "invalid_nonce"
+	ReasonExpiredNonce    = "expired_nonce"
+	ReasonInvalidMessage  = "invalid_message"
+	ReasonUnknownSigner   = "unknown_signer"
+	ReasonDuplicateSigner = "duplicate_signer"
+	ReasonInternal        = "internal"
+)
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests        *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+	challengesCreated   prometheus.Counter
+	verifications       *prometheus.CounterVec
+	repositoryDuration  *prometheus.HistogramVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Number of HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "code"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Latency of HTTP requests by method and route.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		challengesCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "challenges_created_total",
+			Help: "Number of challenges created.",
+		}),
+		verifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "verifications_total",
+			Help: "Number of challenge verifications by outcome and failure reason.",
+		}, []string{"outcome", "reason"}),
+		repositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "repository_query_duration_seconds",
+			Help:    "Latency of repository queries by operation and outcome.",
+			Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "outcome"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpRequestDuration,
+		m.challengesCreated,
+		m.verifications,
+		m.repositoryDuration,
+	)
+
+	return m
+}
+
+// RegisterDB exposes the connection pool statistics of db.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// Registry is the registry the metrics are registered with.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Middleware counts and times the HTTP requests by route.
+func (m *Metrics) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			start := time.Now()
+			err := next(ctx)
+			if err != nil {
+				// let echo write the error response so that its status is recorded
+				ctx.Error(err)
+			}
+
+			// unmatched requests keep their raw path, which must not become a label value
+			route := ctx.Path()
+			if route == "" || err == echo.ErrNotFound {
+				route = "unmatched"
+			}
+			method := ctx.Request().Method
+			m.httpRequests.WithLabelValues(method, route, strconv.Itoa(ctx.Response().Status)).Inc()
+			m.httpRequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+
+			return nil
+		}
+	}
+}
+
+func (m *Metrics) observeQuery(operation string, start time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	m.repositoryDuration.WithLabelValues(operation, outcome).Observe(time.Since(start).Seconds())
+}
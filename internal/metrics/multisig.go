@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/repository"
+	"time"
+)
+
+// multisigRepository times the queries of the wrapped repository.
+type multisigRepository struct {
+	next    repository.MultisigRepository
+	metrics *Metrics
+}
+
+func NewMultisigRepository(next repository.MultisigRepository, metrics *Metrics) repository.MultisigRepository {
+	return &multisigRepository{
+		next:    next,
+		metrics: metrics,
+	}
+}
+
+func (r *multisigRepository) CreateMultisigChallenge(ctx context.Context, challenge *domain.Challenge) error {
+	start := time.Now()
+	err := r.next.CreateMultisigChallenge(ctx, challenge)
+	r.metrics.observeQuery("CreateMultisigChallenge", start, err)
+
+	return err
+}
+
+func (r *multisigRepository) GetMultisigChallenge(ctx context.Context, tenantID, nonce string) (*domain.Challenge, error) {
+	start := time.Now()
+	challenge, err := r.next.GetMultisigChallenge(ctx, tenantID, nonce)
+	r.metrics.observeQuery("GetMultisigChallenge", start, err)
+
+	return challenge, err
+}
+
+func (r *multisigRepository) AddSigner(ctx context.Context, tenantID, nonce, pubKey string, signedAt int64) (int, error) {
+	start := time.Now()
+	position, err := r.next.AddSigner(ctx, tenantID, nonce, pubKey, signedAt)
+	r.metrics.observeQuery("AddSigner", start, err)
+
+	return position, err
+}
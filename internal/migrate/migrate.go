@@ -0,0 +1,291 @@
+// Package migrate versions the database schema of the API.
+//
+// Migrations are the SQL files of the migrations directory of each dialect,
+// embedded in the binary and named NNNN_description.up.sql and
+// NNNN_description.down.sql; every dialect has the same versions. Applied
+// versions are recorded in the schema_migrations table. On Postgres every run
+// holds an advisory lock, so that instances starting together apply each
+// migration once; SQLite serves a single node.
+package migrate
+
+import (
+	"context"
+	"crypto-project-1/internal/database"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// lockKey identifies the advisory lock of the migrations.
+const lockKey = 72616937
+
+var createMigrationsTable = map[database.Dialect]string{
+	database.Postgres: `create table if not exists schema_migrations
+(
+    version    bigint primary key,
+    name       varchar     not null,
+    applied_at timestamptz not null default now()
+)`,
+	database.SQLite: `create table if not exists schema_migrations
+(
+    version    integer primary key,
+    name       text      not null,
+    applied_at timestamp not null default current_timestamp
+)`,
+}
+
+//go:embed migrations/*/*.sql
+var embedded embed.FS
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+}
+
+// Load returns the embedded migrations of the dialect ordered by version.
+func Load(dialect database.Dialect) ([]Migration, error) {
+	migrations, err := fs.Sub(embedded, "migrations/"+string(dialect))
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(migrations)
+}
+
+// Parse reads the migrations of fsys ordered by version. Every migration must
+// have an up and a down file.
+func Parse(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, file := range files {
+		match := fileName.FindStringSubmatch(file.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s is not named NNNN_description.up.sql or NNNN_description.down.sql", file.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		content, err := fs.ReadFile(fsys, file.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, found := byVersion[version]
+		if !found {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d is named both %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d needs both an up and a down file", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Migrator applies and reverts the migrations on a database.
+type Migrator struct {
+	db         *database.DB
+	migrations []Migration
+	log        *logrus.Entry
+}
+
+func New(db *database.DB, migrations []Migration, log *logrus.Entry) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		log:        log,
+	}
+}
+
+// Latest is the version of the last known migration, 0 when there is none.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version is the version of the last applied migration, 0 when none was.
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	var version int
+	err := m.db.QueryRowContext(ctx, "select coalesce(max(version), 0) from schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("could not read schema version: %w", err)
+	}
+
+	return version, nil
+}
+
+// Up applies the pending migrations in order and returns their versions.
+func (m *Migrator) Up(ctx context.Context) ([]int, error) {
+	var applied []int
+	err := m.locked(ctx, func(conn *sql.Conn, done map[int]time.Time) error {
+		for _, migration := range m.migrations {
+			if _, found := done[migration.Version]; found {
+				continue
+			}
+			err := inTx(ctx, conn, migration.Up,
+				"insert into schema_migrations (version, name) values ($1, $2)", migration.Version, migration.Name)
+			if err != nil {
+				return fmt.Errorf("migration %d %s failed: %w", migration.Version, migration.Name, err)
+			}
+			m.log.WithField("version", migration.Version).Info("applied migration ", migration.Name)
+			applied = append(applied, migration.Version)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts the last steps applied migrations and returns their versions.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]int, error) {
+	var reverted []int
+	err := m.locked(ctx, func(conn *sql.Conn, done map[int]time.Time) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, found := done[migration.Version]; !found {
+				continue
+			}
+			err := inTx(ctx, conn, migration.Down,
+				"delete from schema_migrations where version = $1", migration.Version)
+			if err != nil {
+				return fmt.Errorf("reverting migration %d %s failed: %w", migration.Version, migration.Name, err)
+			}
+			m.log.WithField("version", migration.Version).Info("reverted migration ", migration.Name)
+			reverted = append(reverted, migration.Version)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status lists the known migrations with the time they were applied, if they were.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.locked(ctx, func(_ *sql.Conn, done map[int]time.Time) error {
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+			if appliedAt, found := done[migration.Version]; found {
+				status.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+
+		return nil
+	})
+
+	return statuses, err
+}
+
+// locked runs fn on a connection, holding the advisory lock on Postgres, with
+// the applied versions and the time they were applied.
+func (m *Migrator) locked(ctx context.Context, fn func(*sql.Conn, map[int]time.Time) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if m.db.Dialect == database.Postgres {
+		if _, err := conn.ExecContext(ctx, "select pg_advisory_lock($1)", lockKey); err != nil {
+			return fmt.Errorf("could not acquire migration lock: %w", err)
+		}
+		defer func() {
+			// the lock is released with the session should the unlock fail
+			_, _ = conn.ExecContext(context.Background(), "select pg_advisory_unlock($1)", lockKey)
+		}()
+	}
+
+	if _, err := conn.ExecContext(ctx, createMigrationsTable[m.db.Dialect]); err != nil {
+		return fmt.Errorf("could not create schema_migrations: %w", err)
+	}
+	done, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	return fn(conn, done)
+}
+
+// statement writes the parameters of a statement in the dialect of the database.
+func (m *Migrator) statement(query string) string {
+	query, _ = m.db.Dialect.Placeholder().ReplacePlaceholders(query)
+	return query
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, "select version, applied_at from schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("could not read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	done := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		done[version] = appliedAt
+	}
+
+	return done, rows.Err()
+}
+
+// inTx runs the statements of a migration and records it in one transaction.
+func inTx(ctx context.Context, conn *sql.Conn, statements, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, statements); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,130 @@
+package migrate_test
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/database"
+	"crypto-project-1/internal/migrate"
+	"testing"
+	"testing/fstest"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	migrations, err := migrate.Load(database.Postgres)
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	assert.Equal(t, 1, migrations[0].Version)
+	assert.Equal(t, "create_challenge", migrations[0].Name)
+	assert.Contains(t, migrations[0].Up, "create table if not exists challenge")
+	assert.Contains(t, migrations[0].Down, "drop table if exists challenge")
+	for i := 1; i < len(migrations); i++ {
+		assert.Greater(t, migrations[i].Version, migrations[i-1].Version)
+	}
+
+	// every dialect has the same migrations
+	sqliteMigrations, err := migrate.Load(database.SQLite)
+	require.NoError(t, err)
+	require.Len(t, sqliteMigrations, len(migrations))
+	for i := range migrations {
+		assert.Equal(t, migrations[i].Version, sqliteMigrations[i].Version)
+		assert.Equal(t, migrations[i].Name, sqliteMigrations[i].Name)
+	}
+}
+
+func TestMigrator_SQLite(t *testing.T) {
+	ctx := context.Background()
+	db, err := database.Open(config.DatabaseConfig{URL: "sqlite::memory:"})
+	require.NoError(t, err)
+	defer db.Close()
+	migrations, err := migrate.Load(database.SQLite)
+	require.NoError(t, err)
+	migrator := migrate.New(db, migrations, logrus.NewEntry(logrus.New()))
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(migrations))
+	version, err := migrator.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, migrator.Latest(), version)
+	_, err = db.ExecContext(ctx, "insert into challenge (public_key, nonce, expires_at) values ('key', 'nonce', 1)")
+	assert.NoError(t, err)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, len(migrations))
+	assert.NotNil(t, statuses[0].AppliedAt)
+
+	reverted, err := migrator.Down(ctx, len(migrations))
+	require.NoError(t, err)
+	assert.Len(t, reverted, len(migrations))
+	version, err = migrator.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, version)
+}
+
+func TestParse(t *testing.T) {
+	file := func(content string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(content)} }
+
+	tests := []struct {
+		name            string
+		files           fstest.MapFS
+		expected        []int
+		errorIsReturned bool
+	}{
+		{
+			name: "migrations are ordered by version",
+			files: fstest.MapFS{
+				"0010_add_index.up.sql":   file("create index"),
+				"0010_add_index.down.sql": file("drop index"),
+				"0002_add_table.up.sql":   file("create table"),
+				"0002_add_table.down.sql": file("drop table"),
+			},
+			expected: []int{2, 10},
+		},
+		{
+			name: "down file is required",
+			files: fstest.MapFS{
+				"0001_add_table.up.sql": file("create table"),
+			},
+			errorIsReturned: true,
+		},
+		{
+			name: "up and down files must share the name",
+			files: fstest.MapFS{
+				"0001_add_table.up.sql":      file("create table"),
+				"0001_remove_table.down.sql": file("drop table"),
+			},
+			errorIsReturned: true,
+		},
+		{
+			name: "file names must carry the version and direction",
+			files: fstest.MapFS{
+				"add_table.sql": file("create table"),
+			},
+			errorIsReturned: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			migrations, err := migrate.Parse(test.files)
+			if test.errorIsReturned {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			var versions []int
+			for _, migration := range migrations {
+				versions = append(versions, migration.Version)
+			}
+			assert.Equal(t, test.expected, versions)
+			assert.Equal(t, 10, migrate.New(nil, migrations, nil).Latest())
+		})
+	}
+}
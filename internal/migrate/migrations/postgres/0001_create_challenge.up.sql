@@ -1,7 +1,8 @@
+-- the table may predate the migrations, when it was created by init.sql
 create table if not exists challenge
 (
     id         serial primary key,
     public_key varchar        not null,
     nonce      varchar unique not null,
     expires_at bigint         not null
-);
\ No newline at end of file
+);
@@ -0,0 +1,10 @@
+-- nonces may repeat across tenants, only one challenge of a nonce is kept
+delete from challenge c
+    using challenge other
+where c.nonce = other.nonce
+  and c.id > other.id;
+drop index if exists challenge_tenant_nonce_key;
+alter table challenge
+    add constraint challenge_nonce_key unique (nonce);
+alter table challenge
+    drop column tenant_id;
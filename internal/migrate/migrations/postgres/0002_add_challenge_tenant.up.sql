@@ -0,0 +1,6 @@
+-- the challenges created before the tenants belong to the default tenant
+alter table challenge
+    add column tenant_id varchar not null default 'default';
+alter table challenge
+    drop constraint if exists challenge_nonce_key;
+create unique index challenge_tenant_nonce_key on challenge (tenant_id, nonce);
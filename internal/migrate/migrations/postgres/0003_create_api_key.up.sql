@@ -0,0 +1,12 @@
+create table if not exists api_key
+(
+    id           varchar primary key,
+    tenant_id    varchar not null,
+    name         varchar not null,
+    scopes       varchar not null,
+    hash         bytea   not null,
+    created_at   bigint  not null,
+    last_used_at bigint  not null default 0,
+    revoked_at   bigint  not null default 0
+);
+create index if not exists api_key_tenant_id on api_key (tenant_id);
@@ -0,0 +1,7 @@
+drop table if exists challenge_stat;
+drop index if exists challenge_tenant_key_fingerprint;
+drop index if exists challenge_tenant_created_at;
+alter table challenge
+    drop column key_fingerprint;
+alter table challenge
+    drop column created_at;
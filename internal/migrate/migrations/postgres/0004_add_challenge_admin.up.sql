@@ -0,0 +1,16 @@
+-- the challenges created before these columns have no creation time nor key fingerprint
+alter table challenge
+    add column created_at bigint not null default 0;
+alter table challenge
+    add column key_fingerprint varchar not null default '';
+create index if not exists challenge_tenant_created_at on challenge (tenant_id, created_at);
+create index if not exists challenge_tenant_key_fingerprint on challenge (tenant_id, key_fingerprint);
+create table if not exists challenge_stat
+(
+    tenant_id varchar not null,
+    hour      bigint  not null,
+    created   bigint  not null default 0,
+    verified  bigint  not null default 0,
+    failed    bigint  not null default 0,
+    primary key (tenant_id, hour)
+);
@@ -0,0 +1,2 @@
+alter table challenge
+    drop column request_id;
@@ -0,0 +1,3 @@
+-- the challenges created before this column render no request ID in their message
+alter table challenge
+    add column request_id varchar not null default '';
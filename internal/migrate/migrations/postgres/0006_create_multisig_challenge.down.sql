@@ -0,0 +1,2 @@
+drop table if exists multisig_signer;
+drop table if exists multisig_challenge;
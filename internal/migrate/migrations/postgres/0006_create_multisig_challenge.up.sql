@@ -0,0 +1,20 @@
+-- public_keys are comma separated, a compressed public key has no comma
+create table if not exists multisig_challenge
+(
+    tenant_id   varchar not null,
+    nonce       varchar not null,
+    public_keys varchar not null,
+    threshold   integer not null,
+    created_at  bigint  not null,
+    expires_at  bigint  not null,
+    request_id  varchar not null default '',
+    primary key (tenant_id, nonce)
+);
+create table if not exists multisig_signer
+(
+    tenant_id  varchar not null,
+    nonce      varchar not null,
+    public_key varchar not null,
+    signed_at  bigint  not null,
+    primary key (tenant_id, nonce, public_key)
+);
@@ -0,0 +1,10 @@
+-- max_index is below 2^31, the first hardened index
+create table if not exists extended_key
+(
+    tenant_id    varchar not null,
+    xpub         varchar not null,
+    path_pattern varchar not null,
+    max_index    bigint  not null,
+    created_at   bigint  not null,
+    primary key (tenant_id, xpub)
+);
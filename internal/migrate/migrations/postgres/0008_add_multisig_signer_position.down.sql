@@ -0,0 +1,4 @@
+alter table multisig_signer
+    drop column position;
+alter table multisig_challenge
+    drop column signer_count;
@@ -0,0 +1,19 @@
+-- signed_at has a resolution of a second, the signers are ordered by the
+-- position each one got from the count of its challenge when it signed
+alter table multisig_challenge
+    add column signer_count integer not null default 0;
+alter table multisig_signer
+    add column position integer not null default 0;
+-- the signers added before these columns keep the order they were read in
+update multisig_signer
+set position = (select count(*)
+                from multisig_signer previous
+                where previous.tenant_id = multisig_signer.tenant_id
+                  and previous.nonce = multisig_signer.nonce
+                  and (previous.signed_at < multisig_signer.signed_at
+                    or (previous.signed_at = multisig_signer.signed_at and previous.public_key <= multisig_signer.public_key)));
+update multisig_challenge
+set signer_count = (select count(*)
+                    from multisig_signer
+                    where multisig_signer.tenant_id = multisig_challenge.tenant_id
+                      and multisig_signer.nonce = multisig_challenge.nonce);
@@ -0,0 +1,7 @@
+create table if not exists challenge
+(
+    id         integer primary key autoincrement,
+    public_key text    not null,
+    nonce      text    not null unique,
+    expires_at integer not null
+);
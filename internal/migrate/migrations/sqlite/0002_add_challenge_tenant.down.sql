@@ -0,0 +1,15 @@
+-- nonces may repeat across tenants, only one challenge of a nonce is kept
+create table challenge_nonce
+(
+    id         integer primary key autoincrement,
+    public_key text    not null,
+    nonce      text    not null unique,
+    expires_at integer not null
+);
+insert or ignore into challenge_nonce (id, public_key, nonce, expires_at)
+select id, public_key, nonce, expires_at
+from challenge
+order by id;
+drop table challenge;
+alter table challenge_nonce
+    rename to challenge;
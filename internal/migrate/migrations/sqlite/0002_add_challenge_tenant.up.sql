@@ -0,0 +1,17 @@
+-- sqlite cannot drop the unique constraint of the nonce, the table is rebuilt
+-- and the challenges created before the tenants belong to the default tenant
+create table challenge_tenant
+(
+    id         integer primary key autoincrement,
+    tenant_id  text    not null default 'default',
+    public_key text    not null,
+    nonce      text    not null,
+    expires_at integer not null,
+    unique (tenant_id, nonce)
+);
+insert into challenge_tenant (id, public_key, nonce, expires_at)
+select id, public_key, nonce, expires_at
+from challenge;
+drop table challenge;
+alter table challenge_tenant
+    rename to challenge;
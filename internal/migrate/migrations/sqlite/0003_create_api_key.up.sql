@@ -0,0 +1,12 @@
+create table if not exists api_key
+(
+    id           text primary key,
+    tenant_id    text    not null,
+    name         text    not null,
+    scopes       text    not null,
+    hash         blob    not null,
+    created_at   integer not null,
+    last_used_at integer not null default 0,
+    revoked_at   integer not null default 0
+);
+create index if not exists api_key_tenant_id on api_key (tenant_id);
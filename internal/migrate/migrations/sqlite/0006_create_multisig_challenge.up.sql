@@ -0,0 +1,20 @@
+-- public_keys are comma separated, a compressed public key has no comma
+create table if not exists multisig_challenge
+(
+    tenant_id   text    not null,
+    nonce       text    not null,
+    public_keys text    not null,
+    threshold   integer not null,
+    created_at  integer not null,
+    expires_at  integer not null,
+    request_id  text    not null default '',
+    primary key (tenant_id, nonce)
+);
+create table if not exists multisig_signer
+(
+    tenant_id  text    not null,
+    nonce      text    not null,
+    public_key text    not null,
+    signed_at  integer not null,
+    primary key (tenant_id, nonce, public_key)
+);
@@ -0,0 +1,10 @@
+-- max_index is below 2^31, the first hardened index
+create table if not exists extended_key
+(
+    tenant_id    text    not null,
+    xpub         text    not null,
+    path_pattern text    not null,
+    max_index    integer not null,
+    created_at   integer not null,
+    primary key (tenant_id, xpub)
+);
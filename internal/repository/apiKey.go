@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+)
+
+//go:generate mockgen -package=mock_repository -destination=./mock_repository/apiKey.go -source=apiKey.go
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *domain.APIKey) error
+	// GetAPIKey returns the key of an ID whatever its tenant, or nil when there is none.
+	GetAPIKey(ctx context.Context, id string) (*domain.APIKey, error)
+	ListAPIKeys(ctx context.Context, tenantID string) ([]*domain.APIKey, error)
+	// RevokeAPIKey marks the key of the tenant revoked, it reports false when
+	// the tenant has no such key.
+	RevokeAPIKey(ctx context.Context, tenantID, id string, revokedAt int64) (bool, error)
+	// TouchAPIKey records the last use of a key.
+	TouchAPIKey(ctx context.Context, id string, usedAt int64) error
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/database"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/tracing"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const apiKeyTableName = "api_key"
+
+var apiKeyColumns = []string{"id", "tenant_id", "name", "scopes", "hash", "created_at", "last_used_at", "revoked_at"}
+
+// scopes are kept in a single column, comma separated
+const scopeSeparator = ","
+
+type APIKeyDbRepository struct {
+	db           *database.DB
+	queryTimeout time.Duration
+	log          *logrus.Entry
+}
+
+func NewAPIKeyDbRepository(db *database.DB, cfg config.DatabaseConfig, log *logrus.Entry) *APIKeyDbRepository {
+	return &APIKeyDbRepository{
+		db:           db,
+		queryTimeout: cfg.QueryTimeout,
+		log:          log,
+	}
+}
+
+func (db *APIKeyDbRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, db.queryTimeout)
+	defer cancel()
+	ctx, span := db.startQuerySpan(ctx, "APIKeyDbRepository.CreateAPIKey", "INSERT")
+	defer func() { tracing.End(span, err) }()
+
+	_, err = queryBuilder(ctx, db.db).
+		Insert(apiKeyTableName).
+		Columns(apiKeyColumns...).
+		Values(key.ID, key.TenantID, key.Name, strings.Join(key.Scopes, scopeSeparator), key.Hash, key.CreatedAt, key.LastUsedAt, key.RevokedAt).
+		ExecContext(ctx)
+	if err != nil {
+		db.logError(ctx, "failed to execute insert query", err)
+		return err
+	}
+
+	return nil
+}
+
+func (db *APIKeyDbRepository) GetAPIKey(ctx context.Context, id string) (_ *domain.APIKey, err error) {
+	ctx, cancel := context.WithTimeout(ctx, db.queryTimeout)
+	defer cancel()
+	ctx, span := db.startQuerySpan(ctx, "APIKeyDbRepository.GetAPIKey", "SELECT")
+	defer func() { tracing.End(span, err) }()
+
+	row := queryBuilder(ctx, db.db).
+		Select(apiKeyColumns...).
+		From(apiKeyTableName).
+		Where(squirrel.Eq{"id": id}).
+		QueryRowContext(ctx)
+	key, err := scanAPIKey(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		db.logError(ctx, "failed to execute get query", err)
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (db *APIKeyDbRepository) ListAPIKeys(ctx context.Context, tenantID string) (keys []*domain.APIKey, err error) {
+	ctx, cancel := context.WithTimeout(ctx, db.queryTimeout)
+	defer cancel()
+	ctx, span := db.startQuerySpan(ctx, "APIKeyDbRepository.ListAPIKeys", "SELECT")
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := queryBuilder(ctx, db.db).
+		Select(apiKeyColumns...).
+		From(apiKeyTableName).
+		Where(squirrel.Eq{"tenant_id": tenantID}).
+		OrderBy("created_at", "id").
+		QueryContext(ctx)
+	if err != nil {
+		db.logError(ctx, "failed to execute list query", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			db.logError(ctx, "failed to scan list query", err)
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if err = rows.Err(); err != nil {
+		db.logError(ctx, "failed to read list query rows", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (db *APIKeyDbRepository) RevokeAPIKey(ctx context.Context, tenantID, id string, revokedAt int64) (_ bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, db.queryTimeout)
+	defer cancel()
+	ctx, span := db.startQuerySpan(ctx, "APIKeyDbRepository.RevokeAPIKey", "UPDATE")
+	defer func() { tracing.End(span, err) }()
+
+	// a revoked key keeps the time it was first revoked
+	result, err := queryBuilder(ctx, db.db).
+		Update(apiKeyTableName).
+		Set("revoked_at", squirrel.Expr("case when revoked_at = 0 then ? else revoked_at end", revokedAt)).
+		Where(squirrel.And{
+			squirrel.Eq{"tenant_id": tenantID},
+			squirrel.Eq{"id": id},
+		}).
+		ExecContext(ctx)
+	if err != nil {
+		db.logError(ctx, "failed to execute revoke query", err)
+		return false, err
+	}
+	revoked, err := result.RowsAffected()
+	if err != nil {
+		db.logError(ctx, "failed to read revoke query result", err)
+		return false, err
+	}
+
+	return revoked > 0, nil
+}
+
+func (db *APIKeyDbRepository) TouchAPIKey(ctx context.Context, id string, usedAt int64) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, db.queryTimeout)
+	defer cancel()
+	ctx, span := db.startQuerySpan(ctx, "APIKeyDbRepository.TouchAPIKey", "UPDATE")
+	defer func() { tracing.End(span, err) }()
+
+	_, err = queryBuilder(ctx, db.db).
+		Update(apiKeyTableName).
+		Set("last_used_at", usedAt).
+		Where(squirrel.Eq{"id": id}).
+		ExecContext(ctx)
+	if err != nil {
+		db.logError(ctx, "failed to execute touch query", err)
+		return err
+	}
+
+	return nil
+}
+
+func scanAPIKey(row squirrel.RowScanner) (*domain.APIKey, error) {
+	var key domain.APIKey
+	var scopes string
+	err := row.Scan(&key.ID, &key.TenantID, &key.Name, &scopes, &key.Hash, &key.CreatedAt, &key.LastUsedAt, &key.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+	if scopes != "" {
+		key.Scopes = strings.Split(scopes, scopeSeparator)
+	}
+
+	return &key, nil
+}
+
+func (db *APIKeyDbRepository) logError(ctx context.Context, message string, err error) {
+	logging.FromContext(ctx, db.log).WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error(message)
+}
+
+func (db *APIKeyDbRepository) startQuerySpan(ctx context.Context, name, operation string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", db.db.Dialect.System()),
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.collection.name", apiKeyTableName),
+		))
+}
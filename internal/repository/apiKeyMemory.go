@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"sort"
+	"sync"
+)
+
+// APIKeyMemoryRepository keeps the API keys in memory, for local development
+// and tests. The keys are lost when the process stops.
+type APIKeyMemoryRepository struct {
+	mu   sync.RWMutex
+	byID map[string]domain.APIKey
+}
+
+func NewAPIKeyMemoryRepository() *APIKeyMemoryRepository {
+	return &APIKeyMemoryRepository{
+		byID: map[string]domain.APIKey{},
+	}
+}
+
+func (m *APIKeyMemoryRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.byID[key.ID] = copyAPIKey(*key)
+
+	return nil
+}
+
+func (m *APIKeyMemoryRepository) GetAPIKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key, found := m.byID[id]
+	if !found {
+		return nil, nil
+	}
+	key = copyAPIKey(key)
+
+	return &key, nil
+}
+
+func (m *APIKeyMemoryRepository) ListAPIKeys(ctx context.Context, tenantID string) ([]*domain.APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var keys []*domain.APIKey
+	for _, key := range m.byID {
+		if key.TenantID == tenantID {
+			key = copyAPIKey(key)
+			keys = append(keys, &key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].CreatedAt != keys[j].CreatedAt {
+			return keys[i].CreatedAt < keys[j].CreatedAt
+		}
+		return keys[i].ID < keys[j].ID
+	})
+
+	return keys, nil
+}
+
+func (m *APIKeyMemoryRepository) RevokeAPIKey(ctx context.Context, tenantID, id string, revokedAt int64) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, found := m.byID[id]
+	if !found || key.TenantID != tenantID {
+		return false, nil
+	}
+	if key.RevokedAt == 0 {
+		key.RevokedAt = revokedAt
+		m.byID[id] = key
+	}
+
+	return true, nil
+}
+
+func (m *APIKeyMemoryRepository) TouchAPIKey(ctx context.Context, id string, usedAt int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if key, found := m.byID[id]; found {
+		key.LastUsedAt = usedAt
+		m.byID[id] = key
+	}
+
+	return nil
+}
+
+// copyAPIKey copies the slices of key, so that callers cannot change the stored key.
+func copyAPIKey(key domain.APIKey) domain.APIKey {
+	key.Scopes = append([]string(nil), key.Scopes...)
+	key.Hash = append([]byte(nil), key.Hash...)
+
+	return key
+}
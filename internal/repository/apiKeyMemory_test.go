@@ -0,0 +1,13 @@
+package repository_test
+
+import (
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/repository/repositorytest"
+	"testing"
+)
+
+func TestAPIKeyMemoryRepository_Conformance(t *testing.T) {
+	repositorytest.APIKeyRepository(t, func(t *testing.T) repository.APIKeyRepository {
+		return repository.NewAPIKeyMemoryRepository()
+	})
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/tracing"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// revokeScript revokes the key of KEYS[1] provided it belongs to the tenant
+// ARGV[1], keeping the time it was first revoked.
+var revokeScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'tenantId') ~= ARGV[1] then
+  return 0
+end
+if redis.call('HGET', KEYS[1], 'revokedAt') == '0' then
+  redis.call('HSET', KEYS[1], 'revokedAt', ARGV[2])
+end
+return 1
+`)
+
+// touchScript records the last use of the key of KEYS[1], unless it was removed.
+var touchScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+  redis.call('HSET', KEYS[1], 'lastUsedAt', ARGV[1])
+end
+return 0
+`)
+
+// APIKeyRedisRepository keeps every API key in a hash under the key of its ID,
+// and the IDs of the keys of a tenant in a set.
+type APIKeyRedisRepository struct {
+	client       redis.UniversalClient
+	keyPrefix    string
+	queryTimeout time.Duration
+	log          *logrus.Entry
+}
+
+func NewAPIKeyRedisRepository(client redis.UniversalClient, cfg config.RedisConfig, log *logrus.Entry) *APIKeyRedisRepository {
+	return &APIKeyRedisRepository{
+		client:       client,
+		keyPrefix:    cfg.KeyPrefix,
+		queryTimeout: cfg.QueryTimeout,
+		log:          log,
+	}
+}
+
+func (r *APIKeyRedisRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	ctx, span := startRedisSpan(ctx, "APIKeyRedisRepository.CreateAPIKey", "HSET")
+	defer func() { tracing.End(span, err) }()
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, r.key(key.ID), map[string]interface{}{
+			"tenantId":   key.TenantID,
+			"name":       key.Name,
+			"scopes":     strings.Join(key.Scopes, scopeSeparator),
+			"hash":       key.Hash,
+			"createdAt":  key.CreatedAt,
+			"lastUsedAt": key.LastUsedAt,
+			"revokedAt":  key.RevokedAt,
+		})
+		pipe.SAdd(ctx, r.tenantKey(key.TenantID), key.ID)
+		return nil
+	})
+	if err != nil {
+		r.logError(ctx, "failed to set api key", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *APIKeyRedisRepository) GetAPIKey(ctx context.Context, id string) (_ *domain.APIKey, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	ctx, span := startRedisSpan(ctx, "APIKeyRedisRepository.GetAPIKey", "HGETALL")
+	defer func() { tracing.End(span, err) }()
+
+	fields, err := r.client.HGetAll(ctx, r.key(id)).Result()
+	if err != nil {
+		r.logError(ctx, "failed to get api key", err)
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return r.decode(ctx, id, fields)
+}
+
+func (r *APIKeyRedisRepository) ListAPIKeys(ctx context.Context, tenantID string) (_ []*domain.APIKey, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	ctx, span := startRedisSpan(ctx, "APIKeyRedisRepository.ListAPIKeys", "SMEMBERS")
+	defer func() { tracing.End(span, err) }()
+
+	ids, err := r.client.SMembers(ctx, r.tenantKey(tenantID)).Result()
+	if err != nil {
+		r.logError(ctx, "failed to list api keys", err)
+		return nil, err
+	}
+
+	commands := make([]*redis.MapStringStringCmd, len(ids))
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, id := range ids {
+			commands[i] = pipe.HGetAll(ctx, r.key(id))
+		}
+		return nil
+	})
+	if err != nil {
+		r.logError(ctx, "failed to get api keys", err)
+		return nil, err
+	}
+
+	keys := make([]*domain.APIKey, 0, len(ids))
+	for i, command := range commands {
+		if len(command.Val()) == 0 {
+			continue
+		}
+		key, err := r.decode(ctx, ids[i], command.Val())
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].CreatedAt != keys[j].CreatedAt {
+			return keys[i].CreatedAt < keys[j].CreatedAt
+		}
+		return keys[i].ID < keys[j].ID
+	})
+
+	return keys, nil
+}
+
+func (r *APIKeyRedisRepository) RevokeAPIKey(ctx context.Context, tenantID, id string, revokedAt int64) (_ bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	ctx, span := startRedisSpan(ctx, "APIKeyRedisRepository.RevokeAPIKey", "EVALSHA")
+	defer func() { tracing.End(span, err) }()
+
+	revoked, err := revokeScript.Run(ctx, r.client, []string{r.key(id)}, tenantID, revokedAt).Int()
+	if err != nil {
+		r.logError(ctx, "failed to revoke api key", err)
+		return false, err
+	}
+
+	return revoked == 1, nil
+}
+
+func (r *APIKeyRedisRepository) TouchAPIKey(ctx context.Context, id string, usedAt int64) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	ctx, span := startRedisSpan(ctx, "APIKeyRedisRepository.TouchAPIKey", "EVALSHA")
+	defer func() { tracing.End(span, err) }()
+
+	if err = touchScript.Run(ctx, r.client, []string{r.key(id)}, usedAt).Err(); err != nil {
+		r.logError(ctx, "failed to touch api key", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *APIKeyRedisRepository) key(id string) string {
+	return r.keyPrefix + "apikey:" + id
+}
+
+func (r *APIKeyRedisRepository) tenantKey(tenantID string) string {
+	return r.keyPrefix + "apikeys:" + tenantID
+}
+
+func (r *APIKeyRedisRepository) decode(ctx context.Context, id string, fields map[string]string) (*domain.APIKey, error) {
+	key := &domain.APIKey{
+		ID:       id,
+		TenantID: fields["tenantId"],
+		Name:     fields["name"],
+		Hash:     []byte(fields["hash"]),
+	}
+	if fields["scopes"] != "" {
+		key.Scopes = strings.Split(fields["scopes"], scopeSeparator)
+	}
+	for name, value := range map[string]*int64{
+		"createdAt":  &key.CreatedAt,
+		"lastUsedAt": &key.LastUsedAt,
+		"revokedAt":  &key.RevokedAt,
+	} {
+		parsed, err := strconv.ParseInt(fields[name], 10, 64)
+		if err != nil {
+			r.logError(ctx, "failed to decode api key", err)
+			return nil, err
+		}
+		*value = parsed
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRedisRepository) logError(ctx context.Context, message string, err error) {
+	logging.FromContext(ctx, r.log).WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error(message)
+}
@@ -1,11 +1,26 @@
 package repository
 
 import (
+	"context"
 	"crypto-project-1/internal/domain"
 )
 
 //go:generate mockgen -package=mock_repository -destination=./mock_repository/challenge.go -source=challenge.go
 type ChallengeRepository interface {
-	GetChallenges(string, string) ([]*domain.Challenge, error)
-	CreateChallenge(string, string, int64) (*domain.Challenge, error)
+	// The challenges belong to a tenant, given after the context: a nonce is
+	// unique within its tenant and unknown to the others.
+	GetChallenges(ctx context.Context, tenantID, pubKey, nonce string) ([]*domain.Challenge, error)
+	// CreateChallenge stores the challenge with the ID of the request that
+	// creates it, which the message of the challenge renders.
+	CreateChallenge(ctx context.Context, tenantID, pubKey, nonce, requestID string, createdAt, expiresAt int64) (*domain.Challenge, error)
+	// ConsumeChallenge removes the challenge of the public key and nonce and
+	// returns it, or nil when there is none. Concurrent calls return a
+	// challenge to one caller only.
+	ConsumeChallenge(ctx context.Context, tenantID, pubKey, nonce string) (*domain.Challenge, error)
+	// SearchChallenges returns at most filter.Limit challenges of the filter,
+	// from the most recently created one.
+	SearchChallenges(ctx context.Context, tenantID string, filter domain.ChallengeFilter) ([]*domain.Challenge, error)
+	// ExpireChallenge makes the challenge of a nonce expire at expiresAt,
+	// unless it expires earlier, and reports whether there is such a challenge.
+	ExpireChallenge(ctx context.Context, tenantID, nonce string, expiresAt int64) (bool, error)
 }
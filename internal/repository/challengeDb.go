@@ -1,63 +1,253 @@
 package repository
 
 import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/database"
 	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/tracing"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
 	"github.com/Masterminds/squirrel"
-	logger "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// challengeColumns are the columns read into a domain.Challenge
+var challengeColumns = []string{"tenant_id", "public_key", "nonce", "created_at", "expires_at", "request_id"}
+
 const (
 	challengeTableName = "challenge"
+	tracerName         = "crypto-project-1/internal/repository"
 )
 
-type ChallengeDbRepository struct{}
+type ChallengeDbRepository struct {
+	db           *database.DB
+	queryTimeout time.Duration
+	log          *logrus.Entry
+}
 
-func (db *ChallengeDbRepository) GetChallenges(pubKey, nonce string) ([]*domain.Challenge, error) {
-	queryBuilder := dbQueryBuilder().
-		Select("public_key", "nonce", "expires_at").
+func NewChallengeDbRepository(db *database.DB, cfg config.DatabaseConfig, log *logrus.Entry) *ChallengeDbRepository {
+	return &ChallengeDbRepository{
+		db:           db,
+		queryTimeout: cfg.QueryTimeout,
+		log:          log,
+	}
+}
+
+func (db *ChallengeDbRepository) GetChallenges(ctx context.Context, tenantID, pubKey, nonce string) (challenges []*domain.Challenge, err error) {
+	ctx, cancel := context.WithTimeout(ctx, db.queryTimeout)
+	defer cancel()
+	ctx, span := db.startQuerySpan(ctx, "ChallengeDbRepository.GetChallenges", "SELECT")
+	defer func() { tracing.End(span, err) }()
+
+	query := queryBuilder(ctx, db.db).
+		Select(challengeColumns...).
 		From(challengeTableName).
 		Where(squirrel.And{
+			squirrel.Eq{"tenant_id": tenantID},
 			squirrel.Eq{"public_key": pubKey},
 			squirrel.Eq{"nonce": nonce},
 		})
-	rows, err := queryBuilder.Query()
+	rows, err := query.QueryContext(ctx)
 	if err != nil {
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "failed to create get query ", err)
+		db.logError(ctx, "failed to create get query", err)
 		return nil, err
 	}
+	defer rows.Close()
 
-	var challenges []*domain.Challenge
 	for rows.Next() {
-		var challenge domain.Challenge
-		err = rows.Scan(&challenge.PublicKey, &challenge.Nonce, &challenge.ExpiresAt)
+		challenge, err := scanChallenge(rows)
 		if err != nil {
-			logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "failed to execute get query ", err)
+			db.logError(ctx, "failed to execute get query", err)
 			return nil, err
 		}
 
-		challenges = append(challenges, &challenge)
+		challenges = append(challenges, challenge)
+	}
+	// a query cancelled while reading ends the rows early, it must not read as no challenge
+	if err = rows.Err(); err != nil {
+		db.logError(ctx, "failed to read get query rows", err)
+		return nil, err
 	}
 
 	return challenges, nil
 }
 
-func (db *ChallengeDbRepository) CreateChallenge(pubKey, nonce string, expiresAt int64) (*domain.Challenge, error) {
-	queryBuilder := dbQueryBuilder().
+func (db *ChallengeDbRepository) CreateChallenge(ctx context.Context, tenantID, pubKey, nonce, requestID string, createdAt, expiresAt int64) (_ *domain.Challenge, err error) {
+	ctx, cancel := context.WithTimeout(ctx, db.queryTimeout)
+	defer cancel()
+	ctx, span := db.startQuerySpan(ctx, "ChallengeDbRepository.CreateChallenge", "INSERT")
+	defer func() { tracing.End(span, err) }()
+
+	query := queryBuilder(ctx, db.db).
 		Insert(challengeTableName).
-		Columns("public_key", "nonce", "expires_at").
-		Values(pubKey, nonce, expiresAt).
+		Columns("tenant_id", "public_key", "key_fingerprint", "nonce", "created_at", "expires_at", "request_id").
+		Values(tenantID, pubKey, logging.KeyFingerprint(pubKey), nonce, createdAt, expiresAt, requestID).
 		Suffix("RETURNING nonce")
 
 	var createdNonce string
-	err := queryBuilder.QueryRow().Scan(&createdNonce)
+	err = query.QueryRowContext(ctx).Scan(&createdNonce)
+	if database.IsUniqueViolation(err) {
+		return nil, ErrNonceTaken
+	}
 	if err != nil {
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "failed to execute insert query ", err)
+		db.logError(ctx, "failed to execute insert query", err)
 		return nil, err
 	}
 
 	return &domain.Challenge{
+		TenantID:  tenantID,
 		PublicKey: pubKey,
 		Nonce:     createdNonce,
+		CreatedAt: createdAt,
 		ExpiresAt: expiresAt,
+		RequestID: requestID,
 	}, nil
 }
+
+func (db *ChallengeDbRepository) ConsumeChallenge(ctx context.Context, tenantID, pubKey, nonce string) (_ *domain.Challenge, err error) {
+	ctx, cancel := context.WithTimeout(ctx, db.queryTimeout)
+	defer cancel()
+	ctx, span := db.startQuerySpan(ctx, "ChallengeDbRepository.ConsumeChallenge", "DELETE")
+	defer func() { tracing.End(span, err) }()
+
+	query := queryBuilder(ctx, db.db).
+		Delete(challengeTableName).
+		Where(squirrel.And{
+			squirrel.Eq{"tenant_id": tenantID},
+			squirrel.Eq{"public_key": pubKey},
+			squirrel.Eq{"nonce": nonce},
+		}).
+		Suffix("RETURNING " + strings.Join(challengeColumns, ", "))
+
+	challenge, err := scanChallenge(query.QueryRowContext(ctx))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		db.logError(ctx, "failed to execute delete query", err)
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+func (db *ChallengeDbRepository) SearchChallenges(ctx context.Context, tenantID string, filter domain.ChallengeFilter) (challenges []*domain.Challenge, err error) {
+	ctx, cancel := context.WithTimeout(ctx, db.queryTimeout)
+	defer cancel()
+	ctx, span := db.startQuerySpan(ctx, "ChallengeDbRepository.SearchChallenges", "SELECT")
+	defer func() { tracing.End(span, err) }()
+
+	conditions := squirrel.And{squirrel.Eq{"tenant_id": tenantID}}
+	if filter.KeyFingerprint != "" {
+		conditions = append(conditions, squirrel.Eq{"key_fingerprint": filter.KeyFingerprint})
+	}
+	if filter.Nonce != "" {
+		conditions = append(conditions, squirrel.Eq{"nonce": filter.Nonce})
+	}
+	switch filter.Status {
+	case domain.ChallengeStatusPending:
+		conditions = append(conditions, squirrel.GtOrEq{"expires_at": filter.Now})
+	case domain.ChallengeStatusExpired:
+		conditions = append(conditions, squirrel.Lt{"expires_at": filter.Now})
+	}
+	if filter.CreatedFrom != 0 {
+		conditions = append(conditions, squirrel.GtOrEq{"created_at": filter.CreatedFrom})
+	}
+	if filter.CreatedTo != 0 {
+		conditions = append(conditions, squirrel.LtOrEq{"created_at": filter.CreatedTo})
+	}
+	if filter.After != nil {
+		conditions = append(conditions, squirrel.Or{
+			squirrel.Lt{"created_at": filter.After.CreatedAt},
+			squirrel.And{squirrel.Eq{"created_at": filter.After.CreatedAt}, squirrel.Lt{"nonce": filter.After.Nonce}},
+		})
+	}
+	query := queryBuilder(ctx, db.db).
+		Select(challengeColumns...).
+		From(challengeTableName).
+		Where(conditions).
+		OrderBy("created_at DESC", "nonce DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(uint64(filter.Limit))
+	}
+	rows, err := query.QueryContext(ctx)
+	if err != nil {
+		db.logError(ctx, "failed to execute search query", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		challenge, err := scanChallenge(rows)
+		if err != nil {
+			db.logError(ctx, "failed to read search query", err)
+			return nil, err
+		}
+		challenges = append(challenges, challenge)
+	}
+	if err = rows.Err(); err != nil {
+		db.logError(ctx, "failed to read search query rows", err)
+		return nil, err
+	}
+
+	return challenges, nil
+}
+
+func (db *ChallengeDbRepository) ExpireChallenge(ctx context.Context, tenantID, nonce string, expiresAt int64) (_ bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, db.queryTimeout)
+	defer cancel()
+	ctx, span := db.startQuerySpan(ctx, "ChallengeDbRepository.ExpireChallenge", "UPDATE")
+	defer func() { tracing.End(span, err) }()
+
+	// the earliest of both expiries is kept
+	query := queryBuilder(ctx, db.db).
+		Update(challengeTableName).
+		Set("expires_at", squirrel.Expr("case when expires_at > ? then ? else expires_at end", expiresAt, expiresAt)).
+		Where(squirrel.And{
+			squirrel.Eq{"tenant_id": tenantID},
+			squirrel.Eq{"nonce": nonce},
+		})
+	result, err := query.ExecContext(ctx)
+	if err != nil {
+		db.logError(ctx, "failed to execute update query", err)
+		return false, err
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		db.logError(ctx, "failed to read update query result", err)
+		return false, err
+	}
+
+	return updated > 0, nil
+}
+
+func scanChallenge(row squirrel.RowScanner) (*domain.Challenge, error) {
+	var challenge domain.Challenge
+	if err := row.Scan(&challenge.TenantID, &challenge.PublicKey, &challenge.Nonce, &challenge.CreatedAt, &challenge.ExpiresAt, &challenge.RequestID); err != nil {
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+func (db *ChallengeDbRepository) logError(ctx context.Context, message string, err error) {
+	logging.FromContext(ctx, db.log).WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error(message)
+}
+
+func (db *ChallengeDbRepository) startQuerySpan(ctx context.Context, name, operation string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", db.db.Dialect.System()),
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.collection.name", challengeTableName),
+		))
+}
@@ -0,0 +1,185 @@
+package repository_test
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/database"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/migrate"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/repository/repositorytest"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	selectChallenges = `SELECT tenant_id, public_key, nonce, created_at, expires_at, request_id FROM challenge WHERE \(tenant_id = \$1 AND public_key = \$2 AND nonce = \$3\)`
+	insertChallenge  = `INSERT INTO challenge \(tenant_id,public_key,key_fingerprint,nonce,created_at,expires_at,request_id\) VALUES \(\$1,\$2,\$3,\$4,\$5,\$6,\$7\) RETURNING nonce`
+)
+
+func newMockDB(t *testing.T) (*database.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	})
+
+	return &database.DB{DB: db, Dialect: database.Postgres}, mock
+}
+
+// openMigratedDB opens the database of cfg with the schema of the migrations.
+func openMigratedDB(t *testing.T, cfg config.DatabaseConfig) *database.DB {
+	db, err := database.Open(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrations, err := migrate.Load(db.Dialect)
+	require.NoError(t, err)
+	_, err = migrate.New(db, migrations, logrus.NewEntry(logrus.New())).Up(context.Background())
+	require.NoError(t, err)
+
+	return db
+}
+
+func newChallengeDbRepository(db *database.DB) *repository.ChallengeDbRepository {
+	return repository.NewChallengeDbRepository(db, config.Default().Database, logrus.NewEntry(logrus.New()))
+}
+
+func TestChallengeDbRepository_OwnDatabase(t *testing.T) {
+	first, firstMock := newMockDB(t)
+	second, secondMock := newMockDB(t)
+	firstMock.ExpectQuery(selectChallenges).WithArgs("tenant", "key", "nonce").
+		WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "public_key", "nonce", "created_at", "expires_at", "request_id"}).AddRow("tenant", "key", "nonce", 0, 1, "request-id"))
+	secondMock.ExpectQuery(selectChallenges).WithArgs("tenant", "key", "nonce").
+		WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "public_key", "nonce", "created_at", "expires_at", "request_id"}))
+
+	challenges, err := newChallengeDbRepository(first).GetChallenges(context.Background(), "tenant", "key", "nonce")
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Challenge{{TenantID: "tenant", PublicKey: "key", Nonce: "nonce", ExpiresAt: 1, RequestID: "request-id"}}, challenges)
+
+	challenges, err = newChallengeDbRepository(second).GetChallenges(context.Background(), "tenant", "key", "nonce")
+	require.NoError(t, err)
+	assert.Empty(t, challenges)
+}
+
+// TestChallengeDbRepository_Conformance runs against the postgres database of
+// the PG environment variables, it is skipped when PGHOST is not set.
+func TestChallengeDbRepository_Conformance(t *testing.T) {
+	if os.Getenv("PGHOST") == "" {
+		t.Skip("PGHOST is not set")
+	}
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	db := openMigratedDB(t, cfg.Database)
+
+	repositorytest.ChallengeRepository(t, func(t *testing.T) repository.ChallengeRepository {
+		return newChallengeDbRepository(db)
+	})
+}
+
+func TestChallengeDbRepository_SQLiteConformance(t *testing.T) {
+	db := openMigratedDB(t, config.DatabaseConfig{URL: "sqlite::memory:"})
+
+	repositorytest.ChallengeRepository(t, func(t *testing.T) repository.ChallengeRepository {
+		return newChallengeDbRepository(db)
+	})
+}
+
+func TestTransactor_WithinTx(t *testing.T) {
+	failure := errors.New("failure")
+
+	tests := []struct {
+		name          string
+		expect        func(sqlmock.Sqlmock)
+		fn            func(context.Context, *repository.ChallengeDbRepository, *repository.Transactor) error
+		expectedError error
+	}{
+		{
+			name: "calls are committed together",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectChallenges).WithArgs("tenant", "key", "nonce").
+					WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "public_key", "nonce", "created_at", "expires_at", "request_id"}))
+				mock.ExpectQuery(insertChallenge).WithArgs("tenant", "key", logging.KeyFingerprint("key"), "nonce", int64(0), int64(1), "request-id").
+					WillReturnRows(sqlmock.NewRows([]string{"nonce"}).AddRow("nonce"))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context, repo *repository.ChallengeDbRepository, _ *repository.Transactor) error {
+				if _, err := repo.GetChallenges(ctx, "tenant", "key", "nonce"); err != nil {
+					return err
+				}
+				_, err := repo.CreateChallenge(ctx, "tenant", "key", "nonce", "request-id", 0, 1)
+				return err
+			},
+		},
+		{
+			name: "calls are rolled back when the unit of work fails",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(insertChallenge).WithArgs("tenant", "key", logging.KeyFingerprint("key"), "nonce", int64(0), int64(1), "request-id").
+					WillReturnRows(sqlmock.NewRows([]string{"nonce"}).AddRow("nonce"))
+				mock.ExpectRollback()
+			},
+			fn: func(ctx context.Context, repo *repository.ChallengeDbRepository, _ *repository.Transactor) error {
+				if _, err := repo.CreateChallenge(ctx, "tenant", "key", "nonce", "request-id", 0, 1); err != nil {
+					return err
+				}
+				return failure
+			},
+			expectedError: failure,
+		},
+		{
+			name: "nested units of work join the transaction",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(insertChallenge).WithArgs("tenant", "key", logging.KeyFingerprint("key"), "nonce", int64(0), int64(1), "request-id").
+					WillReturnRows(sqlmock.NewRows([]string{"nonce"}).AddRow("nonce"))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context, repo *repository.ChallengeDbRepository, transactor *repository.Transactor) error {
+				return transactor.WithinTx(ctx, func(ctx context.Context) error {
+					_, err := repo.CreateChallenge(ctx, "tenant", "key", "nonce", "request-id", 0, 1)
+					return err
+				})
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			test.expect(mock)
+			repo := newChallengeDbRepository(db)
+			transactor := repository.NewTransactor(db)
+
+			err := transactor.WithinTx(context.Background(), func(ctx context.Context) error {
+				return test.fn(ctx, repo, transactor)
+			})
+			assert.ErrorIs(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestTransactor_WithinTxOtherDatabase(t *testing.T) {
+	first, firstMock := newMockDB(t)
+	second, secondMock := newMockDB(t)
+	firstMock.ExpectBegin()
+	firstMock.ExpectCommit()
+	// the query of the repository on the second database runs outside the transaction
+	secondMock.ExpectQuery(insertChallenge).WithArgs("tenant", "key", logging.KeyFingerprint("key"), "nonce", int64(0), int64(1), "request-id").
+		WillReturnRows(sqlmock.NewRows([]string{"nonce"}).AddRow("nonce"))
+
+	err := repository.NewTransactor(first).WithinTx(context.Background(), func(ctx context.Context) error {
+		_, err := newChallengeDbRepository(second).CreateChallenge(ctx, "tenant", "key", "nonce", "request-id", 0, 1)
+		return err
+	})
+	assert.NoError(t, err)
+}
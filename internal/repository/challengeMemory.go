@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"sync"
+)
+
+// ChallengeMemoryRepository keeps the challenges in memory, for local
+// development and tests. Like the database it keeps expired challenges, so
+// that they are reported as expired rather than unknown, until they are swept,
+// and it loses them all when the process stops.
+type ChallengeMemoryRepository struct {
+	mu      sync.RWMutex
+	byNonce map[tenantNonce]domain.Challenge
+}
+
+// tenantNonce is the key of a challenge, nonces are unique within a tenant.
+type tenantNonce struct {
+	tenantID string
+	nonce    string
+}
+
+func NewChallengeMemoryRepository() *ChallengeMemoryRepository {
+	return &ChallengeMemoryRepository{
+		byNonce: map[tenantNonce]domain.Challenge{},
+	}
+}
+
+func (m *ChallengeMemoryRepository) GetChallenges(ctx context.Context, tenantID, pubKey, nonce string) ([]*domain.Challenge, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	challenge, found := m.byNonce[tenantNonce{tenantID: tenantID, nonce: nonce}]
+	if !found || challenge.PublicKey != pubKey {
+		return nil, nil
+	}
+
+	return []*domain.Challenge{&challenge}, nil
+}
+
+func (m *ChallengeMemoryRepository) CreateChallenge(ctx context.Context, tenantID, pubKey, nonce, requestID string, createdAt, expiresAt int64) (*domain.Challenge, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, found := m.byNonce[tenantNonce{tenantID: tenantID, nonce: nonce}]; found {
+		return nil, ErrNonceTaken
+	}
+	challenge := domain.Challenge{
+		TenantID:  tenantID,
+		PublicKey: pubKey,
+		Nonce:     nonce,
+		CreatedAt: createdAt,
+		ExpiresAt: expiresAt,
+		RequestID: requestID,
+	}
+	m.byNonce[tenantNonce{tenantID: tenantID, nonce: nonce}] = challenge
+
+	return &challenge, nil
+}
+
+func (m *ChallengeMemoryRepository) ConsumeChallenge(ctx context.Context, tenantID, pubKey, nonce string) (*domain.Challenge, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	challenge, found := m.byNonce[tenantNonce{tenantID: tenantID, nonce: nonce}]
+	if !found || challenge.PublicKey != pubKey {
+		return nil, nil
+	}
+	delete(m.byNonce, tenantNonce{tenantID: tenantID, nonce: nonce})
+
+	return &challenge, nil
+}
+
+func (m *ChallengeMemoryRepository) SearchChallenges(ctx context.Context, tenantID string, filter domain.ChallengeFilter) ([]*domain.Challenge, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var challenges []*domain.Challenge
+	for key, challenge := range m.byNonce {
+		if key.tenantID == tenantID {
+			challenge := challenge
+			challenges = append(challenges, &challenge)
+		}
+	}
+
+	return searchChallenges(challenges, filter), nil
+}
+
+func (m *ChallengeMemoryRepository) ExpireChallenge(ctx context.Context, tenantID, nonce string, expiresAt int64) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	challenge, found := m.byNonce[tenantNonce{tenantID: tenantID, nonce: nonce}]
+	if !found {
+		return false, nil
+	}
+	if challenge.ExpiresAt > expiresAt {
+		challenge.ExpiresAt = expiresAt
+		m.byNonce[tenantNonce{tenantID: tenantID, nonce: nonce}] = challenge
+	}
+
+	return true, nil
+}
+
+// SweepExpired removes the challenges that expired before the Unix time.
+func (m *ChallengeMemoryRepository) SweepExpired(ctx context.Context, before int64) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var swept int
+	for key, challenge := range m.byNonce {
+		if challenge.ExpiresAt < before {
+			delete(m.byNonce, key)
+			swept++
+		}
+	}
+
+	return swept, nil
+}
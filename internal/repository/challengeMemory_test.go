@@ -0,0 +1,36 @@
+package repository_test
+
+import (
+	"context"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/repository/repositorytest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChallengeMemoryRepository_Conformance(t *testing.T) {
+	repositorytest.ChallengeRepository(t, func(t *testing.T) repository.ChallengeRepository {
+		return repository.NewChallengeMemoryRepository()
+	})
+}
+
+func TestChallengeMemoryRepository_SweepExpired(t *testing.T) {
+	repo := repository.NewChallengeMemoryRepository()
+	ctx := context.Background()
+	_, err := repo.CreateChallenge(ctx, "tenant", "key", "expired", "", 10, 20)
+	require.NoError(t, err)
+	_, err = repo.CreateChallenge(ctx, "tenant", "key", "pending", "", 10, 40)
+	require.NoError(t, err)
+
+	swept, err := repo.SweepExpired(ctx, 30)
+	require.NoError(t, err)
+	assert.Equal(t, 1, swept)
+	challenges, err := repo.GetChallenges(ctx, "tenant", "key", "expired")
+	require.NoError(t, err)
+	assert.Empty(t, challenges)
+	challenges, err = repo.GetChallenges(ctx, "tenant", "key", "pending")
+	require.NoError(t, err)
+	assert.Len(t, challenges, 1)
+}
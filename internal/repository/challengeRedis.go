@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/tracing"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// consumeScript deletes the challenge of a nonce and returns it, provided it
+// belongs to the public key: GETDEL alone would let any key consume it.
+var consumeScript = redis.NewScript(`
+local value = redis.call('GET', KEYS[1])
+if not value then
+  return false
+end
+if cjson.decode(value).publicKey ~= ARGV[1] then
+  return false
+end
+redis.call('DEL', KEYS[1])
+return value
+`)
+
+// scanCount is the number of keys a search reads per round trip
+const scanCount = 100
+
+// NewRedisClient creates the redis client described by cfg.
+func NewRedisClient(cfg config.RedisConfig) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+		PoolSize: cfg.PoolSize,
+	})
+}
+
+// ChallengeRedisRepository keeps every challenge under the key of its tenant and nonce,
+// set to expire with the challenge, so expired challenges are unknown rather
+// than expired.
+type ChallengeRedisRepository struct {
+	client       redis.UniversalClient
+	keyPrefix    string
+	queryTimeout time.Duration
+	log          *logrus.Entry
+}
+
+func NewChallengeRedisRepository(client redis.UniversalClient, cfg config.RedisConfig, log *logrus.Entry) *ChallengeRedisRepository {
+	return &ChallengeRedisRepository{
+		client:       client,
+		keyPrefix:    cfg.KeyPrefix,
+		queryTimeout: cfg.QueryTimeout,
+		log:          log,
+	}
+}
+
+func (r *ChallengeRedisRepository) GetChallenges(ctx context.Context, tenantID, pubKey, nonce string) (_ []*domain.Challenge, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	ctx, span := startRedisSpan(ctx, "ChallengeRedisRepository.GetChallenges", "GET")
+	defer func() { tracing.End(span, err) }()
+
+	value, err := r.client.Get(ctx, r.key(tenantID, nonce)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		r.logError(ctx, "failed to get challenge", err)
+		return nil, err
+	}
+
+	challenge, err := r.decode(ctx, value)
+	if err != nil || challenge.PublicKey != pubKey {
+		return nil, err
+	}
+
+	return []*domain.Challenge{challenge}, nil
+}
+
+func (r *ChallengeRedisRepository) CreateChallenge(ctx context.Context, tenantID, pubKey, nonce, requestID string, createdAt, expiresAt int64) (_ *domain.Challenge, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	ctx, span := startRedisSpan(ctx, "ChallengeRedisRepository.CreateChallenge", "SET")
+	defer func() { tracing.End(span, err) }()
+
+	challenge := &domain.Challenge{
+		TenantID:  tenantID,
+		PublicKey: pubKey,
+		Nonce:     nonce,
+		CreatedAt: createdAt,
+		ExpiresAt: expiresAt,
+		RequestID: requestID,
+	}
+	value, err := json.Marshal(challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.client.SetArgs(ctx, r.key(tenantID, nonce), value, redis.SetArgs{
+		Mode:     "NX",
+		ExpireAt: time.Unix(expiresAt, 0),
+	}).Err()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNonceTaken
+	}
+	if err != nil {
+		r.logError(ctx, "failed to set challenge", err)
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+func (r *ChallengeRedisRepository) ConsumeChallenge(ctx context.Context, tenantID, pubKey, nonce string) (_ *domain.Challenge, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	ctx, span := startRedisSpan(ctx, "ChallengeRedisRepository.ConsumeChallenge", "EVALSHA")
+	defer func() { tracing.End(span, err) }()
+
+	value, err := consumeScript.Run(ctx, r.client, []string{r.key(tenantID, nonce)}, pubKey).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		r.logError(ctx, "failed to consume challenge", err)
+		return nil, err
+	}
+
+	return r.decode(ctx, []byte(value))
+}
+
+// SearchChallenges scans the challenges of the tenant, a search reads them all.
+func (r *ChallengeRedisRepository) SearchChallenges(ctx context.Context, tenantID string, filter domain.ChallengeFilter) (_ []*domain.Challenge, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	ctx, span := startRedisSpan(ctx, "ChallengeRedisRepository.SearchChallenges", "SCAN")
+	defer func() { tracing.End(span, err) }()
+
+	if filter.Nonce != "" {
+		challenges, err := r.getChallenge(ctx, tenantID, filter.Nonce)
+		if err != nil {
+			return nil, err
+		}
+		return searchChallenges(challenges, filter), nil
+	}
+
+	var challenges []*domain.Challenge
+	iter := r.client.Scan(ctx, 0, r.key(tenantID, "*"), scanCount).Iterator()
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == scanCount {
+			if challenges, err = r.getChallenges(ctx, keys, challenges); err != nil {
+				return nil, err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err = iter.Err(); err != nil {
+		r.logError(ctx, "failed to scan challenges", err)
+		return nil, err
+	}
+	if challenges, err = r.getChallenges(ctx, keys, challenges); err != nil {
+		return nil, err
+	}
+
+	return searchChallenges(challenges, filter), nil
+}
+
+// ExpireChallenge sets the expiry of the key of the challenge, so a challenge
+// expired now is removed like the challenges that expire in redis.
+func (r *ChallengeRedisRepository) ExpireChallenge(ctx context.Context, tenantID, nonce string, expiresAt int64) (_ bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	ctx, span := startRedisSpan(ctx, "ChallengeRedisRepository.ExpireChallenge", "EXPIREAT")
+	defer func() { tracing.End(span, err) }()
+
+	expired, err := r.client.ExpireAt(ctx, r.key(tenantID, nonce), time.Unix(expiresAt, 0)).Result()
+	if err != nil {
+		r.logError(ctx, "failed to expire challenge", err)
+		return false, err
+	}
+
+	return expired, nil
+}
+
+func (r *ChallengeRedisRepository) getChallenge(ctx context.Context, tenantID, nonce string) ([]*domain.Challenge, error) {
+	return r.getChallenges(ctx, []string{r.key(tenantID, nonce)}, nil)
+}
+
+// getChallenges appends the challenges of keys to challenges, skipping the keys that expired meanwhile.
+func (r *ChallengeRedisRepository) getChallenges(ctx context.Context, keys []string, challenges []*domain.Challenge) ([]*domain.Challenge, error) {
+	if len(keys) == 0 {
+		return challenges, nil
+	}
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		r.logError(ctx, "failed to get challenges", err)
+		return nil, err
+	}
+	for _, value := range values {
+		encoded, ok := value.(string)
+		if !ok {
+			continue
+		}
+		challenge, err := r.decode(ctx, []byte(encoded))
+		if err != nil {
+			return nil, err
+		}
+		challenges = append(challenges, challenge)
+	}
+
+	return challenges, nil
+}
+
+func (r *ChallengeRedisRepository) key(tenantID, nonce string) string {
+	return r.keyPrefix + "challenge:" + tenantID + ":" + nonce
+}
+
+func (r *ChallengeRedisRepository) decode(ctx context.Context, value []byte) (*domain.Challenge, error) {
+	var challenge domain.Challenge
+	if err := json.Unmarshal(value, &challenge); err != nil {
+		r.logError(ctx, "failed to decode challenge", err)
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+func (r *ChallengeRedisRepository) logError(ctx context.Context, message string, err error) {
+	logging.FromContext(ctx, r.log).WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error(message)
+}
+
+func startRedisSpan(ctx context.Context, name, operation string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "redis"),
+			attribute.String("db.operation.name", operation),
+		))
+}
@@ -0,0 +1,52 @@
+package repository_test
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/repository/repositorytest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRedisRepository(t *testing.T) (*repository.ChallengeRedisRepository, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	cfg := config.Default().Redis
+	cfg.Addr = server.Addr()
+	client := repository.NewRedisClient(cfg)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return repository.NewChallengeRedisRepository(client, cfg, logrus.NewEntry(logrus.New())), server
+}
+
+func TestChallengeRedisRepository_Conformance(t *testing.T) {
+	repositorytest.ChallengeRepository(t, func(t *testing.T) repository.ChallengeRepository {
+		repo, _ := newRedisRepository(t)
+		return repo
+	})
+}
+
+func TestChallengeRedisRepository_Expiry(t *testing.T) {
+	repo, server := newRedisRepository(t)
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Minute * 5).Unix()
+
+	_, err := repo.CreateChallenge(ctx, "tenant", "key", "nonce", "request-id", time.Now().Unix(), expiresAt)
+	require.NoError(t, err)
+	ttl := server.TTL("crypto-api:challenge:tenant:nonce")
+	assert.InDelta(t, (time.Minute * 5).Seconds(), ttl.Seconds(), 2)
+
+	server.FastForward(time.Minute * 5)
+	challenges, err := repo.GetChallenges(ctx, "tenant", "key", "nonce")
+	require.NoError(t, err)
+	assert.Empty(t, challenges)
+
+	// the nonce of an expired challenge is free again
+	_, err = repo.CreateChallenge(ctx, "tenant", "key", "nonce", "request-id", time.Now().Unix(), time.Now().Add(time.Minute).Unix())
+	assert.NoError(t, err)
+}
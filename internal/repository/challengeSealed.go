@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+const (
+	// sealVersion starts every sealed nonce, it is authenticated with it
+	sealVersion = 1
+	// keyDigestLength is the length of the digest of the public key in a sealed nonce
+	keyDigestLength = 16
+	// sealedHeaderLength is the length of the creation time, the expiry and
+	// the key digest that start the plaintext of a sealed nonce
+	sealedHeaderLength = 8 + 8 + keyDigestLength
+)
+
+// ChallengeSealedRepository stores no challenge: it seals a challenge into its
+// nonce, encrypted and authenticated with AES-256-GCM under the server keys,
+// and opens the nonce to read the challenge back. The nonces of the consumed
+// challenges are remembered by a replay cache, in the memory of the process,
+// until they expire.
+type ChallengeSealedRepository struct {
+	// the first key seals, all of them open so that keys can be rotated
+	aeads  []cipher.AEAD
+	replay *replayCache
+}
+
+// sealedChallenge is the content of a sealed nonce.
+type sealedChallenge struct {
+	tenantID  string
+	keyDigest []byte
+	requestID string
+	nonce     string
+	createdAt int64
+	expiresAt int64
+}
+
+func NewChallengeSealedRepository(cfg config.ChallengeConfig, now func() time.Time) (*ChallengeSealedRepository, error) {
+	var aeads []cipher.AEAD
+	for i, key := range cfg.SealKeys {
+		decoded, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid seal key %d: %w", i, err)
+		}
+		block, err := aes.NewCipher(decoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid seal key %d: %w", i, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		aeads = append(aeads, aead)
+	}
+	if len(aeads) == 0 {
+		return nil, fmt.Errorf("a seal key is required")
+	}
+
+	return &ChallengeSealedRepository{
+		aeads:  aeads,
+		replay: newReplayCache(cfg.ReplayCacheSize, now),
+	}, nil
+}
+
+func (r *ChallengeSealedRepository) GetChallenges(ctx context.Context, tenantID, pubKey, nonce string) ([]*domain.Challenge, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	challenge := r.open(tenantID, pubKey, nonce)
+	if challenge == nil || r.replay.contains(nonce) {
+		return nil, nil
+	}
+
+	return []*domain.Challenge{challenge}, nil
+}
+
+// CreateChallenge returns the challenge with a nonce that seals the given one.
+func (r *ChallengeSealedRepository) CreateChallenge(ctx context.Context, tenantID, pubKey, nonce, requestID string, createdAt, expiresAt int64) (*domain.Challenge, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sealed, err := r.seal(sealedChallenge{
+		tenantID:  tenantID,
+		keyDigest: keyDigest(pubKey),
+		requestID: requestID,
+		nonce:     nonce,
+		createdAt: createdAt,
+		expiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Challenge{
+		TenantID:  tenantID,
+		PublicKey: pubKey,
+		Nonce:     sealed,
+		CreatedAt: createdAt,
+		ExpiresAt: expiresAt,
+		RequestID: requestID,
+	}, nil
+}
+
+func (r *ChallengeSealedRepository) ConsumeChallenge(ctx context.Context, tenantID, pubKey, nonce string) (*domain.Challenge, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	challenge := r.open(tenantID, pubKey, nonce)
+	if challenge == nil {
+		return nil, nil
+	}
+	unused, err := r.replay.add(nonce, challenge.ExpiresAt)
+	if err != nil || !unused {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// SearchChallenges finds no challenge, none is stored.
+func (r *ChallengeSealedRepository) SearchChallenges(ctx context.Context, _ string, _ domain.ChallengeFilter) ([]*domain.Challenge, error) {
+	return nil, ctx.Err()
+}
+
+// ExpireChallenge adds the nonce to the replay cache, the challenge is then
+// unknown rather than expired.
+func (r *ChallengeSealedRepository) ExpireChallenge(ctx context.Context, tenantID, nonce string, _ int64) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	challenge := r.open(tenantID, "", nonce)
+	if challenge == nil {
+		return false, nil
+	}
+	if _, err := r.replay.add(nonce, challenge.ExpiresAt); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// SweepExpired forgets the consumed nonces that expired before the Unix time,
+// no challenge is stored.
+func (r *ChallengeSealedRepository) SweepExpired(ctx context.Context, before int64) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.replay.sweep(before), nil
+}
+
+// seal returns the nonce of a challenge: its version, the nonce of AES-GCM and
+// the encrypted challenge, encoded in base64url. The challenge is encrypted as
+// its header, then its tenant and its request ID each after their length, then
+// the given nonce.
+func (r *ChallengeSealedRepository) seal(challenge sealedChallenge) (string, error) {
+	if len(challenge.tenantID) > 255 {
+		return "", fmt.Errorf("tenant %s is too long to be sealed", challenge.tenantID)
+	}
+	if len(challenge.requestID) > 255 {
+		return "", fmt.Errorf("request ID %s is too long to be sealed", challenge.requestID)
+	}
+	plaintext := make([]byte, sealedHeaderLength, sealedHeaderLength+2+len(challenge.tenantID)+len(challenge.requestID)+len(challenge.nonce))
+	binary.BigEndian.PutUint64(plaintext, uint64(challenge.createdAt))
+	binary.BigEndian.PutUint64(plaintext[8:], uint64(challenge.expiresAt))
+	copy(plaintext[16:], challenge.keyDigest)
+	plaintext = append(plaintext, byte(len(challenge.tenantID)))
+	plaintext = append(plaintext, challenge.tenantID...)
+	plaintext = append(plaintext, byte(len(challenge.requestID)))
+	plaintext = append(plaintext, challenge.requestID...)
+	plaintext = append(plaintext, challenge.nonce...)
+
+	aead := r.aeads[0]
+	sealed := make([]byte, 1+aead.NonceSize(), 1+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	sealed[0] = sealVersion
+	if _, err := rand.Read(sealed[1:]); err != nil {
+		return "", err
+	}
+	sealed = aead.Seal(sealed, sealed[1:], plaintext, sealed[:1])
+
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// open returns the challenge sealed in a nonce, or nil when the nonce was not
+// sealed by a key of the repository or for another tenant or public key. The
+// public key is not checked when it is empty.
+func (r *ChallengeSealedRepository) open(tenantID, pubKey, nonce string) *domain.Challenge {
+	// strict decoding refuses the encodings of the same bytes that differ in
+	// their unused bits, which the replay cache would not recognize
+	sealed, err := base64.RawURLEncoding.Strict().DecodeString(nonce)
+	if err != nil || len(sealed) == 0 || sealed[0] != sealVersion {
+		return nil
+	}
+
+	var plaintext []byte
+	for _, aead := range r.aeads {
+		if len(sealed) < 1+aead.NonceSize() {
+			return nil
+		}
+		if plaintext, err = aead.Open(nil, sealed[1:1+aead.NonceSize()], sealed[1+aead.NonceSize():], sealed[:1]); err == nil {
+			break
+		}
+	}
+	if err != nil || len(plaintext) < sealedHeaderLength+1 {
+		return nil
+	}
+
+	tenantLength := int(plaintext[sealedHeaderLength])
+	tenantStart := sealedHeaderLength + 1
+	if len(plaintext) < tenantStart+tenantLength || string(plaintext[tenantStart:tenantStart+tenantLength]) != tenantID {
+		return nil
+	}
+	if pubKey != "" && subtle.ConstantTimeCompare(plaintext[16:sealedHeaderLength], keyDigest(pubKey)) != 1 {
+		return nil
+	}
+	requestStart := tenantStart + tenantLength + 1
+	if len(plaintext) < requestStart {
+		return nil
+	}
+	requestLength := int(plaintext[requestStart-1])
+	if len(plaintext) < requestStart+requestLength {
+		return nil
+	}
+
+	return &domain.Challenge{
+		TenantID:  tenantID,
+		PublicKey: pubKey,
+		Nonce:     nonce,
+		CreatedAt: int64(binary.BigEndian.Uint64(plaintext)),
+		ExpiresAt: int64(binary.BigEndian.Uint64(plaintext[8:])),
+		RequestID: string(plaintext[requestStart : requestStart+requestLength]),
+	}
+}
+
+// keyDigest binds a sealed challenge to its public key.
+func keyDigest(pubKey string) []byte {
+	digest := sha256.Sum256([]byte(pubKey))
+	return digest[:keyDigestLength]
+}
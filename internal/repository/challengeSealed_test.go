@@ -0,0 +1,220 @@
+package repository_test
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/repository"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSealKey(t *testing.T) string {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func newSealedRepository(t *testing.T, now func() time.Time, keys ...string) *repository.ChallengeSealedRepository {
+	cfg := config.Default().Challenge
+	cfg.SealKeys = keys
+	cfg.ReplayCacheSize = 2
+	repo, err := repository.NewChallengeSealedRepository(cfg, now)
+	require.NoError(t, err)
+
+	return repo
+}
+
+func TestChallengeSealedRepository_GetChallenges(t *testing.T) {
+	repo := newSealedRepository(t, time.Now, newSealKey(t))
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Minute).Unix()
+
+	created, err := repo.CreateChallenge(ctx, "tenant", "key", "nonce", "request-id", 10, expiresAt)
+	require.NoError(t, err)
+	assert.NotEqual(t, "nonce", created.Nonce, "the nonce is sealed")
+	assert.Equal(t, &domain.Challenge{TenantID: "tenant", PublicKey: "key", Nonce: created.Nonce, CreatedAt: 10, ExpiresAt: expiresAt, RequestID: "request-id"}, created)
+
+	challenges, err := repo.GetChallenges(ctx, "tenant", "key", created.Nonce)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Challenge{created}, challenges)
+
+	other, err := repo.CreateChallenge(ctx, "tenant", "key", "nonce", "request-id", 10, expiresAt)
+	require.NoError(t, err)
+	assert.NotEqual(t, created.Nonce, other.Nonce)
+
+	tampered := []byte(created.Nonce)
+	tampered[len(tampered)/2] ^= 'A' ^ 'B'
+	tests := []struct {
+		name   string
+		tenant string
+		pubKey string
+		nonce  string
+	}{
+		{name: "another tenant", tenant: "other", pubKey: "key", nonce: created.Nonce},
+		{name: "another key", tenant: "tenant", pubKey: "other", nonce: created.Nonce},
+		{name: "tampered nonce", tenant: "tenant", pubKey: "key", nonce: string(tampered)},
+		{name: "nonce not sealed", tenant: "tenant", pubKey: "key", nonce: "4b8b3887-e113-4e27-adb4-06f9aa66c395"},
+		{name: "nonce sealed by another key", tenant: "tenant", pubKey: "key", nonce: func() string {
+			challenge, err := newSealedRepository(t, time.Now, newSealKey(t)).CreateChallenge(ctx, "tenant", "key", "nonce", "request-id", 10, expiresAt)
+			require.NoError(t, err)
+			return challenge.Nonce
+		}()},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			challenges, err := repo.GetChallenges(ctx, test.tenant, test.pubKey, test.nonce)
+			require.NoError(t, err)
+			assert.Empty(t, challenges)
+			consumed, err := repo.ConsumeChallenge(ctx, test.tenant, test.pubKey, test.nonce)
+			require.NoError(t, err)
+			assert.Nil(t, consumed)
+		})
+	}
+}
+
+func TestChallengeSealedRepository_KeyRotation(t *testing.T) {
+	ctx := context.Background()
+	oldKey, newKey := newSealKey(t), newSealKey(t)
+	created, err := newSealedRepository(t, time.Now, oldKey).CreateChallenge(ctx, "tenant", "key", "nonce", "request-id", 10, time.Now().Add(time.Minute).Unix())
+	require.NoError(t, err)
+
+	// the new key seals, the old key still opens the challenges it sealed
+	rotated := newSealedRepository(t, time.Now, newKey, oldKey)
+	challenges, err := rotated.GetChallenges(ctx, "tenant", "key", created.Nonce)
+	require.NoError(t, err)
+	assert.Len(t, challenges, 1)
+
+	sealed, err := rotated.CreateChallenge(ctx, "tenant", "key", "nonce", "request-id", 10, time.Now().Add(time.Minute).Unix())
+	require.NoError(t, err)
+	challenges, err = newSealedRepository(t, time.Now, newKey).GetChallenges(ctx, "tenant", "key", sealed.Nonce)
+	require.NoError(t, err)
+	assert.Len(t, challenges, 1)
+}
+
+func TestChallengeSealedRepository_ConsumeChallenge(t *testing.T) {
+	timeNow := time.Now()
+	now := timeNow
+	repo := newSealedRepository(t, func() time.Time { return now }, newSealKey(t))
+	ctx := context.Background()
+	expiresAt := timeNow.Add(time.Minute).Unix()
+	create := func() *domain.Challenge {
+		challenge, err := repo.CreateChallenge(ctx, "tenant", "key", "nonce", "request-id", timeNow.Unix(), expiresAt)
+		require.NoError(t, err)
+		return challenge
+	}
+
+	created := create()
+	consumed := make([]*domain.Challenge, 8)
+	var wg sync.WaitGroup
+	for i := range consumed {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			consumed[i], err = repo.ConsumeChallenge(ctx, "tenant", "key", created.Nonce)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+	var returned int
+	for _, challenge := range consumed {
+		if challenge != nil {
+			returned++
+		}
+	}
+	assert.Equal(t, 1, returned)
+	challenges, err := repo.GetChallenges(ctx, "tenant", "key", created.Nonce)
+	require.NoError(t, err)
+	assert.Empty(t, challenges, "a consumed challenge is gone")
+
+	// the cache holds two nonces and refuses a third one until one expires
+	second := create()
+	_, err = repo.ConsumeChallenge(ctx, "tenant", "key", second.Nonce)
+	require.NoError(t, err)
+	third := create()
+	_, err = repo.ConsumeChallenge(ctx, "tenant", "key", third.Nonce)
+	assert.ErrorIs(t, err, repository.ErrReplayCacheFull)
+
+	now = timeNow.Add(time.Minute * 2)
+	consumedThird, err := repo.ConsumeChallenge(ctx, "tenant", "key", third.Nonce)
+	require.NoError(t, err)
+	require.NotNil(t, consumedThird)
+	assert.Equal(t, expiresAt, consumedThird.ExpiresAt, "an expired challenge is returned with its expiry")
+}
+
+func TestChallengeSealedRepository_ReEncodedNonce(t *testing.T) {
+	repo := newSealedRepository(t, time.Now, newSealKey(t))
+	ctx := context.Background()
+
+	// 86 sealed bytes end with a character of 4 unused bits
+	created, err := repo.CreateChallenge(ctx, "tenant", "key", "nonce12", "request-id", 10, time.Now().Add(time.Minute).Unix())
+	require.NoError(t, err)
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	last := strings.IndexByte(alphabet, created.Nonce[len(created.Nonce)-1])
+	reEncoded := created.Nonce[:len(created.Nonce)-1] + string(alphabet[last^1])
+	lenient, err := base64.RawURLEncoding.DecodeString(reEncoded)
+	require.NoError(t, err)
+	sealed, err := base64.RawURLEncoding.DecodeString(created.Nonce)
+	require.NoError(t, err)
+	require.Equal(t, sealed, lenient, "the nonce is re-encoded")
+
+	consumed, err := repo.ConsumeChallenge(ctx, "tenant", "key", created.Nonce)
+	require.NoError(t, err)
+	require.NotNil(t, consumed)
+	consumed, err = repo.ConsumeChallenge(ctx, "tenant", "key", reEncoded)
+	require.NoError(t, err)
+	assert.Nil(t, consumed, "a re-encoded nonce is not a new challenge")
+}
+
+func TestChallengeSealedRepository_ExpireChallenge(t *testing.T) {
+	repo := newSealedRepository(t, time.Now, newSealKey(t))
+	ctx := context.Background()
+	created, err := repo.CreateChallenge(ctx, "tenant", "key", "nonce", "request-id", 10, time.Now().Add(time.Minute).Unix())
+	require.NoError(t, err)
+
+	expired, err := repo.ExpireChallenge(ctx, "other", created.Nonce, time.Now().Unix())
+	require.NoError(t, err)
+	assert.False(t, expired)
+
+	expired, err = repo.ExpireChallenge(ctx, "tenant", created.Nonce, time.Now().Unix())
+	require.NoError(t, err)
+	assert.True(t, expired)
+	challenges, err := repo.GetChallenges(ctx, "tenant", "key", created.Nonce)
+	require.NoError(t, err)
+	assert.Empty(t, challenges)
+
+	found, err := repo.SearchChallenges(ctx, "tenant", domain.ChallengeFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, found, "sealed challenges are not stored")
+}
+
+func TestChallengeSealedRepository_SweepExpired(t *testing.T) {
+	timeNow := time.Now()
+	now := timeNow
+	repo := newSealedRepository(t, func() time.Time { return now }, newSealKey(t))
+	ctx := context.Background()
+	created, err := repo.CreateChallenge(ctx, "tenant", "key", "nonce", "request-id", timeNow.Unix(), timeNow.Add(time.Minute).Unix())
+	require.NoError(t, err)
+	_, err = repo.ConsumeChallenge(ctx, "tenant", "key", created.Nonce)
+	require.NoError(t, err)
+
+	// a nonce is remembered until it expires, whatever the time swept
+	swept, err := repo.SweepExpired(ctx, timeNow.Add(time.Hour).Unix())
+	require.NoError(t, err)
+	assert.Zero(t, swept)
+
+	now = timeNow.Add(time.Minute * 2)
+	swept, err = repo.SweepExpired(ctx, now.Unix())
+	require.NoError(t, err)
+	assert.Equal(t, 1, swept)
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"sort"
+)
+
+// searchChallenges applies a filter to challenges, for the stores that cannot query them.
+func searchChallenges(challenges []*domain.Challenge, filter domain.ChallengeFilter) []*domain.Challenge {
+	var found []*domain.Challenge
+	for _, challenge := range challenges {
+		if matches(challenge, filter) {
+			found = append(found, challenge)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].CreatedAt != found[j].CreatedAt {
+			return found[i].CreatedAt > found[j].CreatedAt
+		}
+		return found[i].Nonce > found[j].Nonce
+	})
+	if filter.Limit > 0 && len(found) > filter.Limit {
+		found = found[:filter.Limit]
+	}
+
+	return found
+}
+
+func matches(challenge *domain.Challenge, filter domain.ChallengeFilter) bool {
+	switch {
+	case filter.KeyFingerprint != "" && logging.KeyFingerprint(challenge.PublicKey) != filter.KeyFingerprint:
+		return false
+	case filter.Nonce != "" && challenge.Nonce != filter.Nonce:
+		return false
+	case filter.Status == domain.ChallengeStatusPending && challenge.ExpiresAt < filter.Now:
+		return false
+	case filter.Status == domain.ChallengeStatusExpired && challenge.ExpiresAt >= filter.Now:
+		return false
+	case filter.CreatedFrom != 0 && challenge.CreatedAt < filter.CreatedFrom:
+		return false
+	case filter.CreatedTo != 0 && challenge.CreatedAt > filter.CreatedTo:
+		return false
+	case filter.After != nil && !filter.After.Before(challenge):
+		return false
+	}
+
+	return true
+}
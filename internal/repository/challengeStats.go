@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+)
+
+//go:generate mockgen -package=mock_repository -destination=./mock_repository/challengeStats.go -source=challengeStats.go
+type ChallengeStatsRepository interface {
+	// AddStats counts an event, one of domain.StatCreated, StatVerified and
+	// StatFailed, of the tenant in the hour starting at hour.
+	AddStats(ctx context.Context, tenantID string, hour int64, event string) error
+	// GetStats returns the hours of the tenant from from, the start of an hour,
+	// to to, inclusive, that counted events, in order.
+	GetStats(ctx context.Context, tenantID string, from, to int64) ([]*domain.HourlyStats, error)
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/database"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/tracing"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const challengeStatTableName = "challenge_stat"
+
+// statColumns are the counter columns of the events
+var statColumns = map[string]string{
+	domain.StatCreated:  "created",
+	domain.StatVerified: "verified",
+	domain.StatFailed:   "failed",
+}
+
+// ChallengeStatsDbRepository keeps a row of counters per tenant and hour.
+type ChallengeStatsDbRepository struct {
+	db           *database.DB
+	queryTimeout time.Duration
+	log          *logrus.Entry
+}
+
+func NewChallengeStatsDbRepository(db *database.DB, cfg config.DatabaseConfig, log *logrus.Entry) *ChallengeStatsDbRepository {
+	return &ChallengeStatsDbRepository{
+		db:           db,
+		queryTimeout: cfg.QueryTimeout,
+		log:          log,
+	}
+}
+
+func (db *ChallengeStatsDbRepository) AddStats(ctx context.Context, tenantID string, hour int64, event string) (err error) {
+	column, found := statColumns[event]
+	if !found {
+		return fmt.Errorf("unknown statistics event %s", event)
+	}
+	ctx, cancel := context.WithTimeout(ctx, db.queryTimeout)
+	defer cancel()
+	ctx, span := db.startQuerySpan(ctx, "ChallengeStatsDbRepository.AddStats", "INSERT")
+	defer func() { tracing.End(span, err) }()
+
+	// postgres and sqlite both upsert with on conflict
+	_, err = queryBuilder(ctx, db.db).
+		Insert(challengeStatTableName).
+		Columns("tenant_id", "hour", column).
+		Values(tenantID, hour, 1).
+		Suffix(fmt.Sprintf("ON CONFLICT (tenant_id, hour) DO UPDATE SET %[1]s = %[2]s.%[1]s + 1", column, challengeStatTableName)).
+		ExecContext(ctx)
+	if err != nil {
+		db.logError(ctx, "failed to execute upsert query", err)
+		return err
+	}
+
+	return nil
+}
+
+func (db *ChallengeStatsDbRepository) GetStats(ctx context.Context, tenantID string, from, to int64) (stats []*domain.HourlyStats, err error) {
+	ctx, cancel := context.WithTimeout(ctx, db.queryTimeout)
+	defer cancel()
+	ctx, span := db.startQuerySpan(ctx, "ChallengeStatsDbRepository.GetStats", "SELECT")
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := queryBuilder(ctx, db.db).
+		Select("hour", "created", "verified", "failed").
+		From(challengeStatTableName).
+		Where(squirrel.And{
+			squirrel.Eq{"tenant_id": tenantID},
+			squirrel.GtOrEq{"hour": from},
+			squirrel.LtOrEq{"hour": to},
+		}).
+		OrderBy("hour").
+		QueryContext(ctx)
+	if err != nil {
+		db.logError(ctx, "failed to execute stats query", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hourly domain.HourlyStats
+		if err := rows.Scan(&hourly.Hour, &hourly.Created, &hourly.Verified, &hourly.Failed); err != nil {
+			db.logError(ctx, "failed to scan stats query", err)
+			return nil, err
+		}
+		stats = append(stats, &hourly)
+	}
+	if err = rows.Err(); err != nil {
+		db.logError(ctx, "failed to read stats query rows", err)
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (db *ChallengeStatsDbRepository) logError(ctx context.Context, message string, err error) {
+	logging.FromContext(ctx, db.log).WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error(message)
+}
+
+func (db *ChallengeStatsDbRepository) startQuerySpan(ctx context.Context, name, operation string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", db.db.Dialect.System()),
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.collection.name", challengeStatTableName),
+		))
+}
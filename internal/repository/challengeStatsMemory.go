@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"sort"
+	"sync"
+)
+
+// ChallengeStatsMemoryRepository keeps the statistics in memory, for local
+// development and tests.
+type ChallengeStatsMemoryRepository struct {
+	mu     sync.RWMutex
+	byHour map[tenantHour]domain.HourlyStats
+}
+
+// tenantHour is the key of the statistics of an hour.
+type tenantHour struct {
+	tenantID string
+	hour     int64
+}
+
+func NewChallengeStatsMemoryRepository() *ChallengeStatsMemoryRepository {
+	return &ChallengeStatsMemoryRepository{
+		byHour: map[tenantHour]domain.HourlyStats{},
+	}
+}
+
+func (m *ChallengeStatsMemoryRepository) AddStats(ctx context.Context, tenantID string, hour int64, event string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.byHour[tenantHour{tenantID: tenantID, hour: hour}]
+	stats.Hour = hour
+	stats.Add(event, 1)
+	m.byHour[tenantHour{tenantID: tenantID, hour: hour}] = stats
+
+	return nil
+}
+
+func (m *ChallengeStatsMemoryRepository) GetStats(ctx context.Context, tenantID string, from, to int64) ([]*domain.HourlyStats, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var found []*domain.HourlyStats
+	for key, stats := range m.byHour {
+		if key.tenantID == tenantID && key.hour >= from && key.hour <= to {
+			stats := stats
+			found = append(found, &stats)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Hour < found[j].Hour })
+
+	return found, nil
+}
@@ -0,0 +1,13 @@
+package repository_test
+
+import (
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/repository/repositorytest"
+	"testing"
+)
+
+func TestChallengeStatsMemoryRepository_Conformance(t *testing.T) {
+	repositorytest.ChallengeStatsRepository(t, func(t *testing.T) repository.ChallengeStatsRepository {
+		return repository.NewChallengeStatsMemoryRepository()
+	})
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/tracing"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// secondsPerHour is the step between the hours of the statistics
+const secondsPerHour = int64(time.Hour / time.Second)
+
+// ChallengeStatsRedisRepository keeps the counters of an hour in a hash under
+// the key of its tenant and hour.
+type ChallengeStatsRedisRepository struct {
+	client       redis.UniversalClient
+	keyPrefix    string
+	queryTimeout time.Duration
+	log          *logrus.Entry
+}
+
+func NewChallengeStatsRedisRepository(client redis.UniversalClient, cfg config.RedisConfig, log *logrus.Entry) *ChallengeStatsRedisRepository {
+	return &ChallengeStatsRedisRepository{
+		client:       client,
+		keyPrefix:    cfg.KeyPrefix,
+		queryTimeout: cfg.QueryTimeout,
+		log:          log,
+	}
+}
+
+func (r *ChallengeStatsRedisRepository) AddStats(ctx context.Context, tenantID string, hour int64, event string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	ctx, span := startRedisSpan(ctx, "ChallengeStatsRedisRepository.AddStats", "HINCRBY")
+	defer func() { tracing.End(span, err) }()
+
+	if err = r.client.HIncrBy(ctx, r.key(tenantID, hour), event, 1).Err(); err != nil {
+		r.logError(ctx, "failed to increment stats", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetStats reads the hash of every hour of the range.
+func (r *ChallengeStatsRedisRepository) GetStats(ctx context.Context, tenantID string, from, to int64) (_ []*domain.HourlyStats, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	ctx, span := startRedisSpan(ctx, "ChallengeStatsRedisRepository.GetStats", "HGETALL")
+	defer func() { tracing.End(span, err) }()
+
+	var hours []int64
+	var commands []*redis.MapStringStringCmd
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for hour := from; hour <= to; hour += secondsPerHour {
+			hours = append(hours, hour)
+			commands = append(commands, pipe.HGetAll(ctx, r.key(tenantID, hour)))
+		}
+		return nil
+	})
+	if err != nil {
+		r.logError(ctx, "failed to get stats", err)
+		return nil, err
+	}
+
+	var stats []*domain.HourlyStats
+	for i, command := range commands {
+		fields := command.Val()
+		if len(fields) == 0 {
+			continue
+		}
+		hourly := &domain.HourlyStats{Hour: hours[i]}
+		for event, value := range fields {
+			count, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				r.logError(ctx, "failed to decode stats", err)
+				return nil, err
+			}
+			hourly.Add(event, count)
+		}
+		stats = append(stats, hourly)
+	}
+
+	return stats, nil
+}
+
+func (r *ChallengeStatsRedisRepository) key(tenantID string, hour int64) string {
+	return r.keyPrefix + "stats:" + tenantID + ":" + strconv.FormatInt(hour, 10)
+}
+
+func (r *ChallengeStatsRedisRepository) logError(ctx context.Context, message string, err error) {
+	logging.FromContext(ctx, r.log).WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error(message)
+}
@@ -0,0 +1,23 @@
+package repository_test
+
+import (
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/repository/repositorytest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/sirupsen/logrus"
+)
+
+func TestChallengeStatsRedisRepository_Conformance(t *testing.T) {
+	repositorytest.ChallengeStatsRepository(t, func(t *testing.T) repository.ChallengeStatsRepository {
+		server := miniredis.RunT(t)
+		cfg := config.Default().Redis
+		cfg.Addr = server.Addr()
+		client := repository.NewRedisClient(cfg)
+		t.Cleanup(func() { _ = client.Close() })
+
+		return repository.NewChallengeStatsRedisRepository(client, cfg, logrus.NewEntry(logrus.New()))
+	})
+}
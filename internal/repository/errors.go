@@ -0,0 +1,16 @@
+package repository
+
+import "errors"
+
+// ErrNonceTaken is returned when a challenge is created with the nonce of
+// another one, nonces are unique across public keys.
+var ErrNonceTaken = errors.New("nonce is already taken")
+
+// ErrReplayCacheFull is returned when a sealed challenge is consumed while the
+// replay cache holds as many unexpired nonces as it can, the challenge is not
+// consumed rather than let another one be replayed.
+var ErrReplayCacheFull = errors.New("replay cache is full")
+
+// ErrDuplicateSigner is returned when a public key signs a multi-signature
+// challenge it already signed.
+var ErrDuplicateSigner = errors.New("public key already signed the challenge")
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+)
+
+//go:generate mockgen -package=mock_repository -destination=./mock_repository/extendedKey.go -source=extendedKey.go
+type ExtendedKeyRepository interface {
+	// EnrollExtendedKey stores the extended public key of key.TenantID,
+	// replacing an earlier enrollment of the same key.
+	EnrollExtendedKey(ctx context.Context, key *domain.ExtendedKey) error
+	// GetExtendedKey returns the enrollment of the extended public key, or nil
+	// when there is none.
+	GetExtendedKey(ctx context.Context, tenantID, xpub string) (*domain.ExtendedKey, error)
+}
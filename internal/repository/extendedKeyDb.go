@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/database"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/tracing"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const extendedKeyTableName = "extended_key"
+
+// ExtendedKeyDbRepository keeps a row per enrollment, keyed by its tenant and
+// extended public key.
+type ExtendedKeyDbRepository struct {
+	db           *database.DB
+	queryTimeout time.Duration
+	log          *logrus.Entry
+}
+
+func NewExtendedKeyDbRepository(db *database.DB, cfg config.DatabaseConfig, log *logrus.Entry) *ExtendedKeyDbRepository {
+	return &ExtendedKeyDbRepository{
+		db:           db,
+		queryTimeout: cfg.QueryTimeout,
+		log:          log,
+	}
+}
+
+func (db *ExtendedKeyDbRepository) EnrollExtendedKey(ctx context.Context, key *domain.ExtendedKey) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, db.queryTimeout)
+	defer cancel()
+	ctx, span := db.startQuerySpan(ctx, "ExtendedKeyDbRepository.EnrollExtendedKey", "INSERT")
+	defer func() { tracing.End(span, err) }()
+
+	// postgres and sqlite both upsert with on conflict
+	_, err = queryBuilder(ctx, db.db).
+		Insert(extendedKeyTableName).
+		Columns("tenant_id", "xpub", "path_pattern", "max_index", "created_at").
+		Values(key.TenantID, key.Xpub, key.PathPattern, key.MaxIndex, key.CreatedAt).
+		Suffix("ON CONFLICT (tenant_id, xpub) DO UPDATE SET path_pattern = excluded.path_pattern, max_index = excluded.max_index, created_at = excluded.created_at").
+		ExecContext(ctx)
+	if err != nil {
+		db.logError(ctx, "failed to execute upsert query", err)
+		return err
+	}
+
+	return nil
+}
+
+func (db *ExtendedKeyDbRepository) GetExtendedKey(ctx context.Context, tenantID, xpub string) (_ *domain.ExtendedKey, err error) {
+	ctx, cancel := context.WithTimeout(ctx, db.queryTimeout)
+	defer cancel()
+	ctx, span := db.startQuerySpan(ctx, "ExtendedKeyDbRepository.GetExtendedKey", "SELECT")
+	defer func() { tracing.End(span, err) }()
+
+	key := domain.ExtendedKey{TenantID: tenantID, Xpub: xpub}
+	err = queryBuilder(ctx, db.db).
+		Select("path_pattern", "max_index", "created_at").
+		From(extendedKeyTableName).
+		Where(squirrel.And{
+			squirrel.Eq{"tenant_id": tenantID},
+			squirrel.Eq{"xpub": xpub},
+		}).
+		QueryRowContext(ctx).
+		Scan(&key.PathPattern, &key.MaxIndex, &key.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		db.logError(ctx, "failed to execute get query", err)
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (db *ExtendedKeyDbRepository) logError(ctx context.Context, message string, err error) {
+	logging.FromContext(ctx, db.log).WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error(message)
+}
+
+func (db *ExtendedKeyDbRepository) startQuerySpan(ctx context.Context, name, operation string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", db.db.Dialect.System()),
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.collection.name", extendedKeyTableName),
+		))
+}
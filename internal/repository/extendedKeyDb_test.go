@@ -0,0 +1,35 @@
+package repository_test
+
+import (
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/repository/repositorytest"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExtendedKeyDbRepository_Conformance runs against the postgres database
+// of the PG environment variables, it is skipped when PGHOST is not set.
+func TestExtendedKeyDbRepository_Conformance(t *testing.T) {
+	if os.Getenv("PGHOST") == "" {
+		t.Skip("PGHOST is not set")
+	}
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	db := openMigratedDB(t, cfg.Database)
+
+	repositorytest.ExtendedKeyRepository(t, func(t *testing.T) repository.ExtendedKeyRepository {
+		return repository.NewExtendedKeyDbRepository(db, cfg.Database, logrus.NewEntry(logrus.New()))
+	})
+}
+
+func TestExtendedKeyDbRepository_SQLiteConformance(t *testing.T) {
+	db := openMigratedDB(t, config.DatabaseConfig{URL: "sqlite::memory:"})
+
+	repositorytest.ExtendedKeyRepository(t, func(t *testing.T) repository.ExtendedKeyRepository {
+		return repository.NewExtendedKeyDbRepository(db, config.Default().Database, logrus.NewEntry(logrus.New()))
+	})
+}
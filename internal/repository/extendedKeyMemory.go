@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"sync"
+)
+
+// ExtendedKeyMemoryRepository keeps the enrolled extended public keys in
+// memory, for local development and tests.
+type ExtendedKeyMemoryRepository struct {
+	mu     sync.RWMutex
+	byXpub map[tenantXpub]domain.ExtendedKey
+}
+
+// tenantXpub is the key of an enrollment, a tenant enrolls an extended key once.
+type tenantXpub struct {
+	tenantID string
+	xpub     string
+}
+
+func NewExtendedKeyMemoryRepository() *ExtendedKeyMemoryRepository {
+	return &ExtendedKeyMemoryRepository{
+		byXpub: map[tenantXpub]domain.ExtendedKey{},
+	}
+}
+
+func (m *ExtendedKeyMemoryRepository) EnrollExtendedKey(ctx context.Context, key *domain.ExtendedKey) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.byXpub[tenantXpub{tenantID: key.TenantID, xpub: key.Xpub}] = *key
+
+	return nil
+}
+
+func (m *ExtendedKeyMemoryRepository) GetExtendedKey(ctx context.Context, tenantID, xpub string) (*domain.ExtendedKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key, found := m.byXpub[tenantXpub{tenantID: tenantID, xpub: xpub}]
+	if !found {
+		return nil, nil
+	}
+
+	return &key, nil
+}
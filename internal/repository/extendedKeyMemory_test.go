@@ -0,0 +1,13 @@
+package repository_test
+
+import (
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/repository/repositorytest"
+	"testing"
+)
+
+func TestExtendedKeyMemoryRepository_Conformance(t *testing.T) {
+	repositorytest.ExtendedKeyRepository(t, func(t *testing.T) repository.ExtendedKeyRepository {
+		return repository.NewExtendedKeyMemoryRepository()
+	})
+}
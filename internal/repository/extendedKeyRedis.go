@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/tracing"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// ExtendedKeyRedisRepository keeps an enrollment under the key of its tenant
+// and extended public key, without expiry.
+type ExtendedKeyRedisRepository struct {
+	client       redis.UniversalClient
+	keyPrefix    string
+	queryTimeout time.Duration
+	log          *logrus.Entry
+}
+
+func NewExtendedKeyRedisRepository(client redis.UniversalClient, cfg config.RedisConfig, log *logrus.Entry) *ExtendedKeyRedisRepository {
+	return &ExtendedKeyRedisRepository{
+		client:       client,
+		keyPrefix:    cfg.KeyPrefix,
+		queryTimeout: cfg.QueryTimeout,
+		log:          log,
+	}
+}
+
+func (r *ExtendedKeyRedisRepository) EnrollExtendedKey(ctx context.Context, key *domain.ExtendedKey) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	ctx, span := startRedisSpan(ctx, "ExtendedKeyRedisRepository.EnrollExtendedKey", "SET")
+	defer func() { tracing.End(span, err) }()
+
+	value, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	if err = r.client.Set(ctx, r.key(key.TenantID, key.Xpub), value, 0).Err(); err != nil {
+		r.logError(ctx, "failed to set extended key", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ExtendedKeyRedisRepository) GetExtendedKey(ctx context.Context, tenantID, xpub string) (_ *domain.ExtendedKey, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	ctx, span := startRedisSpan(ctx, "ExtendedKeyRedisRepository.GetExtendedKey", "GET")
+	defer func() { tracing.End(span, err) }()
+
+	value, err := r.client.Get(ctx, r.key(tenantID, xpub)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		r.logError(ctx, "failed to get extended key", err)
+		return nil, err
+	}
+
+	var key domain.ExtendedKey
+	if err := json.Unmarshal(value, &key); err != nil {
+		r.logError(ctx, "failed to decode extended key", err)
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r *ExtendedKeyRedisRepository) key(tenantID, xpub string) string {
+	return r.keyPrefix + "xpub:" + tenantID + ":" + xpub
+}
+
+func (r *ExtendedKeyRedisRepository) logError(ctx context.Context, message string, err error) {
+	logging.FromContext(ctx, r.log).WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error(message)
+}
@@ -5,6 +5,7 @@
 package mock_repository
 
 import (
+	context "context"
 	domain "crypto-project-1/internal/domain"
 	reflect "reflect"
 
@@ -34,32 +35,77 @@ func (m *MockChallengeRepository) EXPECT() *MockChallengeRepositoryMockRecorder
 	return m.recorder
 }
 
+// ConsumeChallenge mocks base method.
+func (m *MockChallengeRepository) ConsumeChallenge(ctx context.Context, tenantID, pubKey, nonce string) (*domain.Challenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeChallenge", ctx, tenantID, pubKey, nonce)
+	ret0, _ := ret[0].(*domain.Challenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeChallenge indicates an expected call of ConsumeChallenge.
+func (mr *MockChallengeRepositoryMockRecorder) ConsumeChallenge(ctx, tenantID, pubKey, nonce interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeChallenge", reflect.TypeOf((*MockChallengeRepository)(nil).ConsumeChallenge), ctx, tenantID, pubKey, nonce)
+}
+
 // CreateChallenge mocks base method.
-func (m *MockChallengeRepository) CreateChallenge(arg0, arg1 string, arg2 int64) (*domain.Challenge, error) {
+func (m *MockChallengeRepository) CreateChallenge(ctx context.Context, tenantID, pubKey, nonce, requestID string, createdAt, expiresAt int64) (*domain.Challenge, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateChallenge", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "CreateChallenge", ctx, tenantID, pubKey, nonce, requestID, createdAt, expiresAt)
 	ret0, _ := ret[0].(*domain.Challenge)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateChallenge indicates an expected call of CreateChallenge.
-func (mr *MockChallengeRepositoryMockRecorder) CreateChallenge(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockChallengeRepositoryMockRecorder) CreateChallenge(ctx, tenantID, pubKey, nonce, requestID, createdAt, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChallenge", reflect.TypeOf((*MockChallengeRepository)(nil).CreateChallenge), ctx, tenantID, pubKey, nonce, requestID, createdAt, expiresAt)
+}
+
+// ExpireChallenge mocks base method.
+func (m *MockChallengeRepository) ExpireChallenge(ctx context.Context, tenantID, nonce string, expiresAt int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireChallenge", ctx, tenantID, nonce, expiresAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireChallenge indicates an expected call of ExpireChallenge.
+func (mr *MockChallengeRepositoryMockRecorder) ExpireChallenge(ctx, tenantID, nonce, expiresAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChallenge", reflect.TypeOf((*MockChallengeRepository)(nil).CreateChallenge), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireChallenge", reflect.TypeOf((*MockChallengeRepository)(nil).ExpireChallenge), ctx, tenantID, nonce, expiresAt)
 }
 
 // GetChallenges mocks base method.
-func (m *MockChallengeRepository) GetChallenges(arg0, arg1 string) ([]*domain.Challenge, error) {
+func (m *MockChallengeRepository) GetChallenges(ctx context.Context, tenantID, pubKey, nonce string) ([]*domain.Challenge, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetChallenges", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetChallenges", ctx, tenantID, pubKey, nonce)
 	ret0, _ := ret[0].([]*domain.Challenge)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetChallenges indicates an expected call of GetChallenges.
-func (mr *MockChallengeRepositoryMockRecorder) GetChallenges(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockChallengeRepositoryMockRecorder) GetChallenges(ctx, tenantID, pubKey, nonce interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChallenges", reflect.TypeOf((*MockChallengeRepository)(nil).GetChallenges), ctx, tenantID, pubKey, nonce)
+}
+
+// SearchChallenges mocks base method.
+func (m *MockChallengeRepository) SearchChallenges(ctx context.Context, tenantID string, filter domain.ChallengeFilter) ([]*domain.Challenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchChallenges", ctx, tenantID, filter)
+	ret0, _ := ret[0].([]*domain.Challenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchChallenges indicates an expected call of SearchChallenges.
+func (mr *MockChallengeRepositoryMockRecorder) SearchChallenges(ctx, tenantID, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChallenges", reflect.TypeOf((*MockChallengeRepository)(nil).GetChallenges), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchChallenges", reflect.TypeOf((*MockChallengeRepository)(nil).SearchChallenges), ctx, tenantID, filter)
 }
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: challengeStats.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	domain "crypto-project-1/internal/domain"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockChallengeStatsRepository is a mock of ChallengeStatsRepository interface.
+type MockChallengeStatsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockChallengeStatsRepositoryMockRecorder
+}
+
+// MockChallengeStatsRepositoryMockRecorder is the mock recorder for MockChallengeStatsRepository.
+type MockChallengeStatsRepositoryMockRecorder struct {
+	mock *MockChallengeStatsRepository
+}
+
+// NewMockChallengeStatsRepository creates a new mock instance.
+func NewMockChallengeStatsRepository(ctrl *gomock.Controller) *MockChallengeStatsRepository {
+	mock := &MockChallengeStatsRepository{ctrl: ctrl}
+	mock.recorder = &MockChallengeStatsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChallengeStatsRepository) EXPECT() *MockChallengeStatsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddStats mocks base method.
+func (m *MockChallengeStatsRepository) AddStats(ctx context.Context, tenantID string, hour int64, event string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddStats", ctx, tenantID, hour, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddStats indicates an expected call of AddStats.
+func (mr *MockChallengeStatsRepositoryMockRecorder) AddStats(ctx, tenantID, hour, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddStats", reflect.TypeOf((*MockChallengeStatsRepository)(nil).AddStats), ctx, tenantID, hour, event)
+}
+
+// GetStats mocks base method.
+func (m *MockChallengeStatsRepository) GetStats(ctx context.Context, tenantID string, from, to int64) ([]*domain.HourlyStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStats", ctx, tenantID, from, to)
+	ret0, _ := ret[0].([]*domain.HourlyStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats.
+func (mr *MockChallengeStatsRepositoryMockRecorder) GetStats(ctx, tenantID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockChallengeStatsRepository)(nil).GetStats), ctx, tenantID, from, to)
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: extendedKey.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	domain "crypto-project-1/internal/domain"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockExtendedKeyRepository is a mock of ExtendedKeyRepository interface.
+type MockExtendedKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExtendedKeyRepositoryMockRecorder
+}
+
+// MockExtendedKeyRepositoryMockRecorder is the mock recorder for MockExtendedKeyRepository.
+type MockExtendedKeyRepositoryMockRecorder struct {
+	mock *MockExtendedKeyRepository
+}
+
+// NewMockExtendedKeyRepository creates a new mock instance.
+func NewMockExtendedKeyRepository(ctrl *gomock.Controller) *MockExtendedKeyRepository {
+	mock := &MockExtendedKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockExtendedKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExtendedKeyRepository) EXPECT() *MockExtendedKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// EnrollExtendedKey mocks base method.
+func (m *MockExtendedKeyRepository) EnrollExtendedKey(ctx context.Context, key *domain.ExtendedKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollExtendedKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnrollExtendedKey indicates an expected call of EnrollExtendedKey.
+func (mr *MockExtendedKeyRepositoryMockRecorder) EnrollExtendedKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollExtendedKey", reflect.TypeOf((*MockExtendedKeyRepository)(nil).EnrollExtendedKey), ctx, key)
+}
+
+// GetExtendedKey mocks base method.
+func (m *MockExtendedKeyRepository) GetExtendedKey(ctx context.Context, tenantID, xpub string) (*domain.ExtendedKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExtendedKey", ctx, tenantID, xpub)
+	ret0, _ := ret[0].(*domain.ExtendedKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExtendedKey indicates an expected call of GetExtendedKey.
+func (mr *MockExtendedKeyRepositoryMockRecorder) GetExtendedKey(ctx, tenantID, xpub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExtendedKey", reflect.TypeOf((*MockExtendedKeyRepository)(nil).GetExtendedKey), ctx, tenantID, xpub)
+}
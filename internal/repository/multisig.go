@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+)
+
+//go:generate mockgen -package=mock_repository -destination=./mock_repository/multisig.go -source=multisig.go
+type MultisigRepository interface {
+	// CreateMultisigChallenge stores a challenge of challenge.PublicKeys and
+	// challenge.Threshold, a nonce is unique within its tenant.
+	CreateMultisigChallenge(ctx context.Context, challenge *domain.Challenge) error
+	// GetMultisigChallenge returns the challenge of the nonce with its
+	// signers, or nil when there is none.
+	GetMultisigChallenge(ctx context.Context, tenantID, nonce string) (*domain.Challenge, error)
+	// AddSigner records that the public key signed the challenge of the nonce
+	// at signedAt and returns its position among the signers, from 1, which
+	// GetMultisigChallenge orders them by: a single call gets each position.
+	// Concurrent calls of a key add it once, the others return
+	// ErrDuplicateSigner. The position is 0 when there is no challenge of the
+	// nonce, the caller checks that it exists and that the key is one of it.
+	AddSigner(ctx context.Context, tenantID, nonce, pubKey string, signedAt int64) (int, error)
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/database"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/tracing"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	multisigChallengeTableName = "multisig_challenge"
+	multisigSignerTableName    = "multisig_signer"
+)
+
+// public keys are kept in a single column, comma separated
+const publicKeySeparator = ","
+
+// MultisigDbRepository keeps a row per challenge and a row per signer, whose
+// primary key lets a public key sign a challenge once. The row of a challenge
+// counts its signers, a signer takes its position from that count.
+type MultisigDbRepository struct {
+	db           *database.DB
+	tx           *Transactor
+	queryTimeout time.Duration
+	log          *logrus.Entry
+}
+
+func NewMultisigDbRepository(db *database.DB, cfg config.DatabaseConfig, log *logrus.Entry) *MultisigDbRepository {
+	return &MultisigDbRepository{
+		db:           db,
+		tx:           NewTransactor(db),
+		queryTimeout: cfg.QueryTimeout,
+		log:          log,
+	}
+}
+
+func (db *MultisigDbRepository) CreateMultisigChallenge(ctx context.Context, challenge *domain.Challenge) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, db.queryTimeout)
+	defer cancel()
+	ctx, span := db.startQuerySpan(ctx, "MultisigDbRepository.CreateMultisigChallenge", "INSERT", multisigChallengeTableName)
+	defer func() { tracing.End(span, err) }()
+
+	_, err = queryBuilder(ctx, db.db).
+		Insert(multisigChallengeTableName).
+		Columns("tenant_id", "nonce", "public_keys", "threshold", "created_at", "expires_at", "request_id").
+		Values(challenge.TenantID, challenge.Nonce, strings.Join(challenge.PublicKeys, publicKeySeparator), challenge.Threshold, challenge.CreatedAt, challenge.ExpiresAt, challenge.RequestID).
+		ExecContext(ctx)
+	if database.IsUniqueViolation(err) {
+		return ErrNonceTaken
+	}
+	if err != nil {
+		db.logError(ctx, "failed to execute insert query", err)
+		return err
+	}
+
+	return nil
+}
+
+func (db *MultisigDbRepository) GetMultisigChallenge(ctx context.Context, tenantID, nonce string) (_ *domain.Challenge, err error) {
+	ctx, cancel := context.WithTimeout(ctx, db.queryTimeout)
+	defer cancel()
+	ctx, span := db.startQuerySpan(ctx, "MultisigDbRepository.GetMultisigChallenge", "SELECT", multisigChallengeTableName)
+	defer func() { tracing.End(span, err) }()
+
+	challenge := domain.Challenge{TenantID: tenantID, Nonce: nonce}
+	var publicKeys string
+	err = queryBuilder(ctx, db.db).
+		Select("public_keys", "threshold", "created_at", "expires_at", "request_id").
+		From(multisigChallengeTableName).
+		Where(squirrel.And{
+			squirrel.Eq{"tenant_id": tenantID},
+			squirrel.Eq{"nonce": nonce},
+		}).
+		QueryRowContext(ctx).
+		Scan(&publicKeys, &challenge.Threshold, &challenge.CreatedAt, &challenge.ExpiresAt, &challenge.RequestID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		db.logError(ctx, "failed to execute get query", err)
+		return nil, err
+	}
+	challenge.PublicKeys = strings.Split(publicKeys, publicKeySeparator)
+
+	rows, err := queryBuilder(ctx, db.db).
+		Select("public_key").
+		From(multisigSignerTableName).
+		Where(squirrel.And{
+			squirrel.Eq{"tenant_id": tenantID},
+			squirrel.Eq{"nonce": nonce},
+		}).
+		OrderBy("position").
+		QueryContext(ctx)
+	if err != nil {
+		db.logError(ctx, "failed to execute signers query", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var signer string
+		if err := rows.Scan(&signer); err != nil {
+			db.logError(ctx, "failed to scan signers query", err)
+			return nil, err
+		}
+		challenge.Signers = append(challenge.Signers, signer)
+	}
+	if err = rows.Err(); err != nil {
+		db.logError(ctx, "failed to read signers query rows", err)
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+// AddSigner counts the signer on the row of the challenge, which locks it
+// until the signer is inserted: the concurrent calls of a challenge take their
+// positions one after the other, and a duplicate signer gives its position
+// back when its transaction is rolled back.
+func (db *MultisigDbRepository) AddSigner(ctx context.Context, tenantID, nonce, pubKey string, signedAt int64) (position int, err error) {
+	ctx, cancel := context.WithTimeout(ctx, db.queryTimeout)
+	defer cancel()
+	ctx, span := db.startQuerySpan(ctx, "MultisigDbRepository.AddSigner", "INSERT", multisigSignerTableName)
+	defer func() { tracing.End(span, err) }()
+
+	err = db.tx.WithinTx(ctx, func(ctx context.Context) error {
+		err := queryBuilder(ctx, db.db).
+			Update(multisigChallengeTableName).
+			Set("signer_count", squirrel.Expr("signer_count + 1")).
+			Where(squirrel.And{
+				squirrel.Eq{"tenant_id": tenantID},
+				squirrel.Eq{"nonce": nonce},
+			}).
+			Suffix("RETURNING signer_count").
+			QueryRowContext(ctx).
+			Scan(&position)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			db.logError(ctx, "failed to execute count query", err)
+			return err
+		}
+
+		_, err = queryBuilder(ctx, db.db).
+			Insert(multisigSignerTableName).
+			Columns("tenant_id", "nonce", "public_key", "signed_at", "position").
+			Values(tenantID, nonce, pubKey, signedAt, position).
+			ExecContext(ctx)
+		if database.IsUniqueViolation(err) {
+			return ErrDuplicateSigner
+		}
+		if err != nil {
+			db.logError(ctx, "failed to execute insert query", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return position, nil
+}
+
+func (db *MultisigDbRepository) logError(ctx context.Context, message string, err error) {
+	logging.FromContext(ctx, db.log).WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error(message)
+}
+
+func (db *MultisigDbRepository) startQuerySpan(ctx context.Context, name, operation, table string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", db.db.Dialect.System()),
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.collection.name", table),
+		))
+}
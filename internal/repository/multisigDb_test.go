@@ -0,0 +1,35 @@
+package repository_test
+
+import (
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/repository/repositorytest"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMultisigDbRepository_Conformance runs against the postgres database of
+// the PG environment variables, it is skipped when PGHOST is not set.
+func TestMultisigDbRepository_Conformance(t *testing.T) {
+	if os.Getenv("PGHOST") == "" {
+		t.Skip("PGHOST is not set")
+	}
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	db := openMigratedDB(t, cfg.Database)
+
+	repositorytest.MultisigRepository(t, func(t *testing.T) repository.MultisigRepository {
+		return repository.NewMultisigDbRepository(db, cfg.Database, logrus.NewEntry(logrus.New()))
+	})
+}
+
+func TestMultisigDbRepository_SQLiteConformance(t *testing.T) {
+	db := openMigratedDB(t, config.DatabaseConfig{URL: "sqlite::memory:"})
+
+	repositorytest.MultisigRepository(t, func(t *testing.T) repository.MultisigRepository {
+		return repository.NewMultisigDbRepository(db, config.Default().Database, logrus.NewEntry(logrus.New()))
+	})
+}
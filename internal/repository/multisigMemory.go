@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"sync"
+)
+
+// MultisigMemoryRepository keeps the multi-signature challenges in memory, for
+// local development and tests.
+type MultisigMemoryRepository struct {
+	mu      sync.RWMutex
+	byNonce map[tenantNonce]domain.Challenge
+}
+
+func NewMultisigMemoryRepository() *MultisigMemoryRepository {
+	return &MultisigMemoryRepository{
+		byNonce: map[tenantNonce]domain.Challenge{},
+	}
+}
+
+func (m *MultisigMemoryRepository) CreateMultisigChallenge(ctx context.Context, challenge *domain.Challenge) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := tenantNonce{tenantID: challenge.TenantID, nonce: challenge.Nonce}
+	if _, found := m.byNonce[key]; found {
+		return ErrNonceTaken
+	}
+	stored := *challenge
+	stored.PublicKeys = append([]string(nil), challenge.PublicKeys...)
+	stored.Signers = nil
+	m.byNonce[key] = stored
+
+	return nil
+}
+
+func (m *MultisigMemoryRepository) GetMultisigChallenge(ctx context.Context, tenantID, nonce string) (*domain.Challenge, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	challenge, found := m.byNonce[tenantNonce{tenantID: tenantID, nonce: nonce}]
+	if !found {
+		return nil, nil
+	}
+	// the slices are shared with the stored challenge, signers are appended under the lock only
+	challenge.PublicKeys = append([]string(nil), challenge.PublicKeys...)
+	challenge.Signers = append([]string(nil), challenge.Signers...)
+
+	return &challenge, nil
+}
+
+// AddSigner appends the signer, the signers are kept in the order they signed.
+func (m *MultisigMemoryRepository) AddSigner(ctx context.Context, tenantID, nonce, pubKey string, signedAt int64) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := tenantNonce{tenantID: tenantID, nonce: nonce}
+	challenge, found := m.byNonce[key]
+	if !found {
+		return 0, nil
+	}
+	for _, signer := range challenge.Signers {
+		if signer == pubKey {
+			return 0, ErrDuplicateSigner
+		}
+	}
+	challenge.Signers = append(append([]string(nil), challenge.Signers...), pubKey)
+	m.byNonce[key] = challenge
+
+	return len(challenge.Signers), nil
+}
+
+// SweepExpired removes the challenges that expired before the Unix time, with
+// their signers.
+func (m *MultisigMemoryRepository) SweepExpired(ctx context.Context, before int64) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var swept int
+	for key, challenge := range m.byNonce {
+		if challenge.ExpiresAt < before {
+			delete(m.byNonce, key)
+			swept++
+		}
+	}
+
+	return swept, nil
+}
@@ -0,0 +1,46 @@
+package repository_test
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/repository/repositorytest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultisigMemoryRepository_Conformance(t *testing.T) {
+	repositorytest.MultisigRepository(t, func(t *testing.T) repository.MultisigRepository {
+		return repository.NewMultisigMemoryRepository()
+	})
+}
+
+func TestMultisigMemoryRepository_SweepExpired(t *testing.T) {
+	repo := repository.NewMultisigMemoryRepository()
+	ctx := context.Background()
+	for nonce, expiresAt := range map[string]int64{"expired": 20, "pending": 40} {
+		require.NoError(t, repo.CreateMultisigChallenge(ctx, &domain.Challenge{
+			TenantID:   "tenant",
+			Nonce:      nonce,
+			CreatedAt:  10,
+			ExpiresAt:  expiresAt,
+			PublicKeys: []string{"first", "second"},
+			Threshold:  2,
+		}))
+		_, err := repo.AddSigner(ctx, "tenant", nonce, "first", 10)
+		require.NoError(t, err)
+	}
+
+	swept, err := repo.SweepExpired(ctx, 30)
+	require.NoError(t, err)
+	assert.Equal(t, 1, swept)
+	challenge, err := repo.GetMultisigChallenge(ctx, "tenant", "expired")
+	require.NoError(t, err)
+	assert.Nil(t, challenge)
+	challenge, err = repo.GetMultisigChallenge(ctx, "tenant", "pending")
+	require.NoError(t, err)
+	require.NotNil(t, challenge)
+	assert.Equal(t, []string{"first"}, challenge.Signers)
+}
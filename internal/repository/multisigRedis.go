@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/tracing"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// addSignerScript adds a signer to the set of a challenge unless it is in it
+// already, scored by its position, and sets the set to expire with the
+// challenge. It returns the position, 0 for a signer in the set already and
+// -1 for a challenge that expired meanwhile, which gets no signer.
+var addSignerScript = redis.NewScript(`
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+  return -1
+end
+if redis.call('ZSCORE', KEYS[2], ARGV[1]) then
+  return 0
+end
+local position = redis.call('ZCARD', KEYS[2]) + 1
+redis.call('ZADD', KEYS[2], position, ARGV[1])
+redis.call('PEXPIRE', KEYS[2], ttl)
+return position
+`)
+
+// MultisigRedisRepository keeps a challenge under the key of its tenant and
+// nonce and its signers in a sorted set scored by the order they signed, both
+// set to expire with the challenge.
+type MultisigRedisRepository struct {
+	client       redis.UniversalClient
+	keyPrefix    string
+	queryTimeout time.Duration
+	log          *logrus.Entry
+}
+
+func NewMultisigRedisRepository(client redis.UniversalClient, cfg config.RedisConfig, log *logrus.Entry) *MultisigRedisRepository {
+	return &MultisigRedisRepository{
+		client:       client,
+		keyPrefix:    cfg.KeyPrefix,
+		queryTimeout: cfg.QueryTimeout,
+		log:          log,
+	}
+}
+
+func (r *MultisigRedisRepository) CreateMultisigChallenge(ctx context.Context, challenge *domain.Challenge) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	ctx, span := startRedisSpan(ctx, "MultisigRedisRepository.CreateMultisigChallenge", "SET")
+	defer func() { tracing.End(span, err) }()
+
+	stored := *challenge
+	stored.Signers = nil
+	value, err := json.Marshal(&stored)
+	if err != nil {
+		return err
+	}
+
+	err = r.client.SetArgs(ctx, r.key(challenge.TenantID, challenge.Nonce), value, redis.SetArgs{
+		Mode:     "NX",
+		ExpireAt: time.Unix(challenge.ExpiresAt, 0),
+	}).Err()
+	if errors.Is(err, redis.Nil) {
+		return ErrNonceTaken
+	}
+	if err != nil {
+		r.logError(ctx, "failed to set multisig challenge", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *MultisigRedisRepository) GetMultisigChallenge(ctx context.Context, tenantID, nonce string) (_ *domain.Challenge, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	ctx, span := startRedisSpan(ctx, "MultisigRedisRepository.GetMultisigChallenge", "GET")
+	defer func() { tracing.End(span, err) }()
+
+	var get *redis.StringCmd
+	var signers *redis.StringSliceCmd
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, r.key(tenantID, nonce))
+		signers = pipe.ZRange(ctx, r.signersKey(tenantID, nonce), 0, -1)
+		return nil
+	})
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		r.logError(ctx, "failed to get multisig challenge", err)
+		return nil, err
+	}
+
+	var challenge domain.Challenge
+	if err := json.Unmarshal([]byte(get.Val()), &challenge); err != nil {
+		r.logError(ctx, "failed to decode multisig challenge", err)
+		return nil, err
+	}
+	if len(signers.Val()) > 0 {
+		challenge.Signers = signers.Val()
+	}
+
+	return &challenge, nil
+}
+
+func (r *MultisigRedisRepository) AddSigner(ctx context.Context, tenantID, nonce, pubKey string, signedAt int64) (_ int, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	ctx, span := startRedisSpan(ctx, "MultisigRedisRepository.AddSigner", "EVALSHA")
+	defer func() { tracing.End(span, err) }()
+
+	position, err := addSignerScript.Run(ctx, r.client, []string{r.key(tenantID, nonce), r.signersKey(tenantID, nonce)}, pubKey).Int()
+	if err != nil {
+		r.logError(ctx, "failed to add multisig signer", err)
+		return 0, err
+	}
+	switch {
+	case position == 0:
+		return 0, ErrDuplicateSigner
+	case position < 0:
+		return 0, nil
+	}
+
+	return position, nil
+}
+
+func (r *MultisigRedisRepository) key(tenantID, nonce string) string {
+	return r.keyPrefix + "multisig:" + tenantID + ":" + nonce
+}
+
+func (r *MultisigRedisRepository) signersKey(tenantID, nonce string) string {
+	return r.keyPrefix + "multisig-signers:" + tenantID + ":" + nonce
+}
+
+func (r *MultisigRedisRepository) logError(ctx context.Context, message string, err error) {
+	logging.FromContext(ctx, r.log).WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error(message)
+}
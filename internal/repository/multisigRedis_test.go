@@ -0,0 +1,61 @@
+package repository_test
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/repository/repositorytest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMultisigRedisRepository(t *testing.T) (*repository.MultisigRedisRepository, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	cfg := config.Default().Redis
+	cfg.Addr = server.Addr()
+	client := repository.NewRedisClient(cfg)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return repository.NewMultisigRedisRepository(client, cfg, logrus.NewEntry(logrus.New())), server
+}
+
+func TestMultisigRedisRepository_Conformance(t *testing.T) {
+	repositorytest.MultisigRepository(t, func(t *testing.T) repository.MultisigRepository {
+		repo, _ := newMultisigRedisRepository(t)
+		return repo
+	})
+}
+
+func TestMultisigRedisRepository_Expiry(t *testing.T) {
+	repo, server := newMultisigRedisRepository(t)
+	ctx := context.Background()
+
+	err := repo.CreateMultisigChallenge(ctx, &domain.Challenge{
+		TenantID:   "tenant",
+		Nonce:      "nonce",
+		ExpiresAt:  time.Now().Add(time.Minute * 5).Unix(),
+		PublicKeys: []string{"first", "second"},
+		Threshold:  2,
+	})
+	require.NoError(t, err)
+	_, err = repo.AddSigner(ctx, "tenant", "nonce", "first", time.Now().Unix())
+	require.NoError(t, err)
+	ttl := server.TTL("crypto-api:multisig-signers:tenant:nonce")
+	assert.InDelta(t, (time.Minute * 5).Seconds(), ttl.Seconds(), 2)
+
+	server.FastForward(time.Minute * 5)
+	challenge, err := repo.GetMultisigChallenge(ctx, "tenant", "nonce")
+	require.NoError(t, err)
+	assert.Nil(t, challenge)
+	// an expired challenge gets no signer
+	position, err := repo.AddSigner(ctx, "tenant", "nonce", "second", time.Now().Unix())
+	require.NoError(t, err)
+	assert.Zero(t, position)
+	assert.False(t, server.Exists("crypto-api:multisig-signers:tenant:nonce"))
+}
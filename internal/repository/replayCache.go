@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// replayCache remembers the nonces of the consumed sealed challenges until
+// they expire, it holds at most size of them.
+type replayCache struct {
+	mu       sync.Mutex
+	size     int
+	now      func() time.Time
+	byNonce  map[string]struct{}
+	expiries expiryHeap
+}
+
+func newReplayCache(size int, now func() time.Time) *replayCache {
+	return &replayCache{
+		size:    size,
+		now:     now,
+		byNonce: map[string]struct{}{},
+	}
+}
+
+// add remembers a nonce until expiresAt and reports whether it was unknown.
+// A nonce that has expired already is not remembered, it can not be used.
+func (c *replayCache) add(nonce string, expiresAt int64) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.purge()
+	if _, found := c.byNonce[nonce]; found {
+		return false, nil
+	}
+	if expiresAt < c.now().Unix() {
+		return true, nil
+	}
+	if len(c.byNonce) >= c.size {
+		return false, ErrReplayCacheFull
+	}
+	c.byNonce[nonce] = struct{}{}
+	heap.Push(&c.expiries, expiry{nonce: nonce, expiresAt: expiresAt})
+
+	return true, nil
+}
+
+func (c *replayCache) contains(nonce string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.purge()
+	_, found := c.byNonce[nonce]
+	return found
+}
+
+// purge forgets the nonces that expired.
+func (c *replayCache) purge() {
+	c.purgeBefore(c.now().Unix())
+}
+
+// sweep forgets the nonces that expired before the Unix time, without waiting
+// for the next nonce to be added.
+func (c *replayCache) sweep(before int64) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now := c.now().Unix(); before > now {
+		before = now
+	}
+	return c.purgeBefore(before)
+}
+
+func (c *replayCache) purgeBefore(before int64) int {
+	var purged int
+	for len(c.expiries) > 0 && c.expiries[0].expiresAt < before {
+		delete(c.byNonce, heap.Pop(&c.expiries).(expiry).nonce)
+		purged++
+	}
+
+	return purged
+}
+
+type expiry struct {
+	nonce     string
+	expiresAt int64
+}
+
+// expiryHeap orders the nonces of the replay cache from the first to expire.
+type expiryHeap []expiry
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].expiresAt < h[j].expiresAt }
+func (h expiryHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *expiryHeap) Push(x interface{}) {
+	*h = append(*h, x.(expiry))
+}
+
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
@@ -1,11 +1,39 @@
 package repository
 
+import "context"
+
+// UnitOfWork runs fn so that the repository calls it makes with the given
+// context succeed or fail together.
+type UnitOfWork interface {
+	WithinTx(ctx context.Context, fn func(context.Context) error) error
+}
+
+type noTx struct{}
+
+func (noTx) WithinTx(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+// NoTx runs units of work without a transaction, for repositories that have
+// none such as mocks.
+var NoTx UnitOfWork = noTx{}
+
 type Repository struct {
-	ChallengeRepo ChallengeRepository
+	UnitOfWork      UnitOfWork
+	ChallengeRepo   ChallengeRepository
+	APIKeyRepo      APIKeyRepository
+	StatsRepo       ChallengeStatsRepository
+	MultisigRepo    MultisigRepository
+	ExtendedKeyRepo ExtendedKeyRepository
 }
 
-func NewRepository(challengeRepository ChallengeRepository) *Repository {
+func NewRepository(unitOfWork UnitOfWork, challengeRepository ChallengeRepository, apiKeyRepository APIKeyRepository, statsRepository ChallengeStatsRepository, multisigRepository MultisigRepository, extendedKeyRepository ExtendedKeyRepository) *Repository {
 	return &Repository{
-		ChallengeRepo: challengeRepository,
+		UnitOfWork:      unitOfWork,
+		ChallengeRepo:   challengeRepository,
+		APIKeyRepo:      apiKeyRepository,
+		StatsRepo:       statsRepository,
+		MultisigRepo:    multisigRepository,
+		ExtendedKeyRepo: extendedKeyRepository,
 	}
 }
@@ -1,66 +1,60 @@
 package repository
 
 import (
-	"crypto-project-1/internal/domain"
+	"context"
+	"crypto-project-1/internal/database"
 	"database/sql"
 	"fmt"
+
 	"github.com/Masterminds/squirrel"
-	// use pq as a library to create postgres client
-	_ "github.com/lib/pq"
-	"os"
-	"strconv"
 )
 
-const (
-	dbPortVar = "PGPORT"
-	dbHostVar = "PGHOST"
-	dbNameVar = "PGDATABASE"
-	dbUserVar = "PGUSER"
-	dbPassVar = "PGPASSWORD"
-)
+// txKey binds a transaction to the context, keyed by its database so that a
+// repository on another database never runs its queries in it.
+type txKey struct {
+	db *sql.DB
+}
 
-var db *sql.DB
+// Transactor runs units of work in a database transaction.
+type Transactor struct {
+	db *sql.DB
+}
 
-func dbQueryBuilder() squirrel.StatementBuilderType {
-	return squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db)
+func NewTransactor(db *database.DB) *Transactor {
+	return &Transactor{db: db.DB}
 }
 
-func NewDB() (*sql.DB, error) {
-	host, found := os.LookupEnv(dbHostVar)
-	if !found {
-		err := fmt.Errorf("%s missing env variable %s", domain.CryptoAPIError, dbHostVar)
-		return nil, err
-	}
-	p, found := os.LookupEnv(dbPortVar)
-	if !found {
-		err := fmt.Errorf("%s missing env variable %s", domain.CryptoAPIError, dbPortVar)
-		return nil, err
+// WithinTx runs fn in a transaction: the queries the repositories of the
+// database run with the context given to fn are part of it. The transaction is
+// committed when fn returns nil and rolled back otherwise. Nested calls join
+// the transaction of their caller.
+func (t *Transactor) WithinTx(ctx context.Context, fn func(context.Context) error) error {
+	if _, found := ctx.Value(txKey{db: t.db}).(*sql.Tx); found {
+		return fn(ctx)
 	}
-	port, err := strconv.Atoi(p)
+
+	tx, err := t.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("could not begin transaction: %w", err)
 	}
-	dbname, found := os.LookupEnv(dbNameVar)
-	if !found {
-		err := fmt.Errorf("%s missing env variable %s", domain.CryptoAPIError, dbNameVar)
-		return nil, err
+	if err := fn(context.WithValue(ctx, txKey{db: t.db}, tx)); err != nil {
+		_ = tx.Rollback()
+		return err
 	}
-	user, found := os.LookupEnv(dbUserVar)
-	if !found {
-		err := fmt.Errorf("%s missing env variable %s", domain.CryptoAPIError, dbUserVar)
-		return nil, err
-	}
-	password, found := os.LookupEnv(dbPassVar)
-	if !found {
-		err := fmt.Errorf("%s missing env variable %s", domain.CryptoAPIError, dbPassVar)
-		return nil, err
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
 	}
 
-	psqlInfo := fmt.Sprintf("host=%s port=%d user=%s dbname=%s password=%s sslmode=disable",
-		host, port, user, dbname, password)
-	db, err = sql.Open("postgres", psqlInfo)
-	if err != nil {
-		return nil, err
+	return nil
+}
+
+// queryBuilder returns a statement builder in the dialect of db, running on
+// the transaction of ctx or on db when ctx carries none.
+func queryBuilder(ctx context.Context, db *database.DB) squirrel.StatementBuilderType {
+	builder := squirrel.StatementBuilder.PlaceholderFormat(db.Dialect.Placeholder())
+	if tx, found := ctx.Value(txKey{db: db.DB}).(*sql.Tx); found {
+		return builder.RunWith(tx)
 	}
-	return db, nil
+
+	return builder.RunWith(db.DB)
 }
@@ -0,0 +1,112 @@
+package repositorytest
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/repository"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// APIKeyRepository runs the conformance tests of APIKeyRepository against the
+// repositories returned by newRepo. The repositories may share their storage,
+// every test uses its own tenants and keys.
+func APIKeyRepository(t *testing.T, newRepo func(t *testing.T) repository.APIKeyRepository) {
+	ctx := context.Background()
+	newKey := func(tenantID string, createdAt int64) *domain.APIKey {
+		return &domain.APIKey{
+			ID:        uuid.NewString(),
+			TenantID:  tenantID,
+			Name:      "backend",
+			Scopes:    []string{domain.ScopeCreate, domain.ScopeVerify},
+			Hash:      []byte{0x00, 0xff, 0x10, 0x2c},
+			CreatedAt: createdAt,
+		}
+	}
+
+	t.Run("created key is found by id", func(t *testing.T) {
+		repo := newRepo(t)
+		key := newKey(uuid.NewString(), 10)
+		require.NoError(t, repo.CreateAPIKey(ctx, key))
+
+		found, err := repo.GetAPIKey(ctx, key.ID)
+		require.NoError(t, err)
+		assert.Equal(t, key, found)
+
+		found, err = repo.GetAPIKey(ctx, uuid.NewString())
+		require.NoError(t, err)
+		assert.Nil(t, found)
+	})
+
+	t.Run("keys are listed by tenant in creation order", func(t *testing.T) {
+		repo := newRepo(t)
+		tenant := uuid.NewString()
+		second, first, other := newKey(tenant, 20), newKey(tenant, 10), newKey(uuid.NewString(), 10)
+		for _, key := range []*domain.APIKey{second, first, other} {
+			require.NoError(t, repo.CreateAPIKey(ctx, key))
+		}
+
+		keys, err := repo.ListAPIKeys(ctx, tenant)
+		require.NoError(t, err)
+		assert.Equal(t, []*domain.APIKey{first, second}, keys)
+	})
+
+	t.Run("key is revoked by its tenant only", func(t *testing.T) {
+		repo := newRepo(t)
+		key := newKey(uuid.NewString(), 10)
+		require.NoError(t, repo.CreateAPIKey(ctx, key))
+
+		revoked, err := repo.RevokeAPIKey(ctx, uuid.NewString(), key.ID, 30)
+		require.NoError(t, err)
+		assert.False(t, revoked, "another tenant must not revoke the key")
+
+		revoked, err = repo.RevokeAPIKey(ctx, key.TenantID, key.ID, 30)
+		require.NoError(t, err)
+		assert.True(t, revoked)
+		revoked, err = repo.RevokeAPIKey(ctx, key.TenantID, key.ID, 40)
+		require.NoError(t, err)
+		assert.True(t, revoked)
+
+		found, err := repo.GetAPIKey(ctx, key.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(30), found.RevokedAt)
+
+		revoked, err = repo.RevokeAPIKey(ctx, key.TenantID, uuid.NewString(), 30)
+		require.NoError(t, err)
+		assert.False(t, revoked)
+	})
+
+	t.Run("last use is recorded", func(t *testing.T) {
+		repo := newRepo(t)
+		key := newKey(uuid.NewString(), 10)
+		require.NoError(t, repo.CreateAPIKey(ctx, key))
+
+		require.NoError(t, repo.TouchAPIKey(ctx, key.ID, 50))
+		found, err := repo.GetAPIKey(ctx, key.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(50), found.LastUsedAt)
+
+		// an unknown key is left unknown
+		require.NoError(t, repo.TouchAPIKey(ctx, uuid.NewString(), 50))
+	})
+
+	t.Run("cancelled context fails the calls", func(t *testing.T) {
+		repo := newRepo(t)
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err := repo.CreateAPIKey(cancelled, newKey(uuid.NewString(), 10))
+		assert.ErrorIs(t, err, context.Canceled)
+		_, err = repo.GetAPIKey(cancelled, uuid.NewString())
+		assert.ErrorIs(t, err, context.Canceled)
+		_, err = repo.ListAPIKeys(cancelled, uuid.NewString())
+		assert.ErrorIs(t, err, context.Canceled)
+		_, err = repo.RevokeAPIKey(cancelled, uuid.NewString(), uuid.NewString(), 30)
+		assert.ErrorIs(t, err, context.Canceled)
+		err = repo.TouchAPIKey(cancelled, uuid.NewString(), 50)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,66 @@
+package repositorytest
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/repository"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ChallengeStatsRepository runs the conformance tests of ChallengeStatsRepository
+// against the repositories returned by newRepo. The repositories may share
+// their storage, every test uses its own tenants.
+func ChallengeStatsRepository(t *testing.T, newRepo func(t *testing.T) repository.ChallengeStatsRepository) {
+	ctx := context.Background()
+	const hour = 1700000000 / 3600 * 3600
+
+	t.Run("events are counted per hour", func(t *testing.T) {
+		repo := newRepo(t)
+		tenant := uuid.NewString()
+		for _, event := range []string{domain.StatCreated, domain.StatCreated, domain.StatVerified, domain.StatFailed} {
+			require.NoError(t, repo.AddStats(ctx, tenant, hour, event))
+		}
+		require.NoError(t, repo.AddStats(ctx, tenant, hour+3600, domain.StatCreated))
+
+		stats, err := repo.GetStats(ctx, tenant, hour, hour+3600)
+		require.NoError(t, err)
+		assert.Equal(t, []*domain.HourlyStats{
+			{Hour: hour, Created: 2, Verified: 1, Failed: 1},
+			{Hour: hour + 3600, Created: 1},
+		}, stats)
+	})
+
+	t.Run("hours out of the range or without events are omitted", func(t *testing.T) {
+		repo := newRepo(t)
+		tenant := uuid.NewString()
+		require.NoError(t, repo.AddStats(ctx, tenant, hour, domain.StatVerified))
+		require.NoError(t, repo.AddStats(ctx, tenant, hour+7200, domain.StatFailed))
+
+		stats, err := repo.GetStats(ctx, tenant, hour+3600, hour+7200)
+		require.NoError(t, err)
+		assert.Equal(t, []*domain.HourlyStats{{Hour: hour + 7200, Failed: 1}}, stats)
+	})
+
+	t.Run("tenants are isolated", func(t *testing.T) {
+		repo := newRepo(t)
+		require.NoError(t, repo.AddStats(ctx, uuid.NewString(), hour, domain.StatCreated))
+
+		stats, err := repo.GetStats(ctx, uuid.NewString(), hour, hour)
+		require.NoError(t, err)
+		assert.Empty(t, stats)
+	})
+
+	t.Run("cancelled context fails the calls", func(t *testing.T) {
+		repo := newRepo(t)
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		assert.ErrorIs(t, repo.AddStats(cancelled, uuid.NewString(), hour, domain.StatCreated), context.Canceled)
+		_, err := repo.GetStats(cancelled, uuid.NewString(), hour, hour)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,305 @@
+// Package repositorytest holds the tests every repository implementation must
+// pass, so that they can replace one another.
+package repositorytest
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/repository"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ChallengeRepository runs the conformance tests of ChallengeRepository
+// against the repositories returned by newRepo. The repositories may share
+// their storage, every test uses its own keys and nonces.
+func ChallengeRepository(t *testing.T, newRepo func(t *testing.T) repository.ChallengeRepository) {
+	ctx := context.Background()
+	createdAt := time.Now().Unix()
+	expiresAt := time.Now().Add(time.Minute).Unix()
+	tenant := uuid.NewString()
+	requestID := uuid.NewString()
+
+	t.Run("created challenge is found by key and nonce", func(t *testing.T) {
+		repo := newRepo(t)
+		pubKey, nonce := uuid.NewString(), uuid.NewString()
+
+		created, err := repo.CreateChallenge(ctx, tenant, pubKey, nonce, requestID, createdAt, expiresAt)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.Challenge{TenantID: tenant, PublicKey: pubKey, Nonce: nonce, CreatedAt: createdAt, ExpiresAt: expiresAt, RequestID: requestID}, created)
+
+		challenges, err := repo.GetChallenges(ctx, tenant, pubKey, nonce)
+		require.NoError(t, err)
+		assert.Equal(t, []*domain.Challenge{created}, challenges)
+	})
+
+	t.Run("challenge is not found with another key or nonce", func(t *testing.T) {
+		repo := newRepo(t)
+		pubKey, nonce := uuid.NewString(), uuid.NewString()
+		_, err := repo.CreateChallenge(ctx, tenant, pubKey, nonce, requestID, createdAt, expiresAt)
+		require.NoError(t, err)
+
+		challenges, err := repo.GetChallenges(ctx, tenant, uuid.NewString(), nonce)
+		require.NoError(t, err)
+		assert.Empty(t, challenges)
+
+		challenges, err = repo.GetChallenges(ctx, tenant, pubKey, uuid.NewString())
+		require.NoError(t, err)
+		assert.Empty(t, challenges)
+	})
+
+	t.Run("nonce is unique across keys", func(t *testing.T) {
+		repo := newRepo(t)
+		nonce := uuid.NewString()
+		_, err := repo.CreateChallenge(ctx, tenant, uuid.NewString(), nonce, requestID, createdAt, expiresAt)
+		require.NoError(t, err)
+
+		_, err = repo.CreateChallenge(ctx, tenant, uuid.NewString(), nonce, requestID, createdAt, expiresAt)
+		assert.ErrorIs(t, err, repository.ErrNonceTaken)
+	})
+
+	t.Run("tenants are isolated", func(t *testing.T) {
+		repo := newRepo(t)
+		pubKey, nonce, otherTenant := uuid.NewString(), uuid.NewString(), uuid.NewString()
+		_, err := repo.CreateChallenge(ctx, tenant, pubKey, nonce, requestID, createdAt, expiresAt)
+		require.NoError(t, err)
+
+		challenges, err := repo.GetChallenges(ctx, otherTenant, pubKey, nonce)
+		require.NoError(t, err)
+		assert.Empty(t, challenges)
+		consumed, err := repo.ConsumeChallenge(ctx, otherTenant, pubKey, nonce)
+		require.NoError(t, err)
+		assert.Nil(t, consumed, "another tenant must not consume the challenge")
+
+		// nonces are unique within a tenant only
+		created, err := repo.CreateChallenge(ctx, otherTenant, pubKey, nonce, requestID, createdAt, expiresAt)
+		require.NoError(t, err)
+		assert.Equal(t, otherTenant, created.TenantID)
+		challenges, err = repo.GetChallenges(ctx, tenant, pubKey, nonce)
+		require.NoError(t, err)
+		require.Len(t, challenges, 1)
+		assert.Equal(t, tenant, challenges[0].TenantID)
+	})
+
+	t.Run("expired challenge is dropped or kept with its expiry", func(t *testing.T) {
+		repo := newRepo(t)
+		pubKey, nonce := uuid.NewString(), uuid.NewString()
+		expiredAt := time.Now().Add(-time.Minute).Unix()
+		_, err := repo.CreateChallenge(ctx, tenant, pubKey, nonce, requestID, createdAt, expiredAt)
+		require.NoError(t, err)
+
+		// stores with native expiry drop it, the others keep it so that it is reported as expired
+		challenges, err := repo.GetChallenges(ctx, tenant, pubKey, nonce)
+		require.NoError(t, err)
+		for _, challenge := range challenges {
+			assert.Equal(t, expiredAt, challenge.ExpiresAt)
+		}
+	})
+
+	t.Run("consumed challenge is returned once", func(t *testing.T) {
+		repo := newRepo(t)
+		pubKey, nonce := uuid.NewString(), uuid.NewString()
+		created, err := repo.CreateChallenge(ctx, tenant, pubKey, nonce, requestID, createdAt, expiresAt)
+		require.NoError(t, err)
+
+		consumed, err := repo.ConsumeChallenge(ctx, tenant, uuid.NewString(), nonce)
+		require.NoError(t, err)
+		assert.Nil(t, consumed, "another key must not consume the challenge")
+
+		consumed, err = repo.ConsumeChallenge(ctx, tenant, pubKey, nonce)
+		require.NoError(t, err)
+		assert.Equal(t, created, consumed)
+
+		consumed, err = repo.ConsumeChallenge(ctx, tenant, pubKey, nonce)
+		require.NoError(t, err)
+		assert.Nil(t, consumed)
+		challenges, err := repo.GetChallenges(ctx, tenant, pubKey, nonce)
+		require.NoError(t, err)
+		assert.Empty(t, challenges)
+	})
+
+	t.Run("concurrent consumptions return the challenge once", func(t *testing.T) {
+		repo := newRepo(t)
+		pubKey, nonce := uuid.NewString(), uuid.NewString()
+		_, err := repo.CreateChallenge(ctx, tenant, pubKey, nonce, requestID, createdAt, expiresAt)
+		require.NoError(t, err)
+
+		consumed := make([]*domain.Challenge, 8)
+		var wg sync.WaitGroup
+		for i := range consumed {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				var err error
+				consumed[i], err = repo.ConsumeChallenge(ctx, tenant, pubKey, nonce)
+				assert.NoError(t, err)
+			}(i)
+		}
+		wg.Wait()
+
+		var returned int
+		for _, challenge := range consumed {
+			if challenge != nil {
+				returned++
+			}
+		}
+		assert.Equal(t, 1, returned)
+	})
+
+	t.Run("concurrent creations of a nonce keep one challenge", func(t *testing.T) {
+		repo := newRepo(t)
+		nonce := uuid.NewString()
+		pubKeys := make([]string, 8)
+		errs := make([]error, len(pubKeys))
+
+		var wg sync.WaitGroup
+		for i := range pubKeys {
+			pubKeys[i] = uuid.NewString()
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = repo.CreateChallenge(ctx, tenant, pubKeys[i], nonce, requestID, createdAt, expiresAt)
+			}(i)
+		}
+		wg.Wait()
+
+		var kept []*domain.Challenge
+		for i, err := range errs {
+			if err != nil {
+				assert.ErrorIs(t, err, repository.ErrNonceTaken)
+				continue
+			}
+			challenges, err := repo.GetChallenges(ctx, tenant, pubKeys[i], nonce)
+			require.NoError(t, err)
+			kept = append(kept, challenges...)
+		}
+		assert.Len(t, kept, 1)
+	})
+
+	t.Run("search filters challenges by key, nonce and creation time", func(t *testing.T) {
+		repo := newRepo(t)
+		tenant, pubKey, otherKey := uuid.NewString(), uuid.NewString(), uuid.NewString()
+		first, err := repo.CreateChallenge(ctx, tenant, pubKey, uuid.NewString(), requestID, createdAt-20, expiresAt)
+		require.NoError(t, err)
+		second, err := repo.CreateChallenge(ctx, tenant, pubKey, uuid.NewString(), requestID, createdAt-10, expiresAt)
+		require.NoError(t, err)
+		other, err := repo.CreateChallenge(ctx, tenant, otherKey, uuid.NewString(), requestID, createdAt, expiresAt)
+		require.NoError(t, err)
+
+		found, err := repo.SearchChallenges(ctx, tenant, domain.ChallengeFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []*domain.Challenge{other, second, first}, found, "the most recent challenge comes first")
+
+		found, err = repo.SearchChallenges(ctx, tenant, domain.ChallengeFilter{KeyFingerprint: logging.KeyFingerprint(pubKey)})
+		require.NoError(t, err)
+		assert.Equal(t, []*domain.Challenge{second, first}, found)
+
+		found, err = repo.SearchChallenges(ctx, tenant, domain.ChallengeFilter{Nonce: first.Nonce})
+		require.NoError(t, err)
+		assert.Equal(t, []*domain.Challenge{first}, found)
+
+		found, err = repo.SearchChallenges(ctx, tenant, domain.ChallengeFilter{CreatedFrom: createdAt - 15, CreatedTo: createdAt - 5})
+		require.NoError(t, err)
+		assert.Equal(t, []*domain.Challenge{second}, found)
+
+		found, err = repo.SearchChallenges(ctx, uuid.NewString(), domain.ChallengeFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, found, "another tenant must not find the challenges")
+	})
+
+	t.Run("search filters challenges by status", func(t *testing.T) {
+		repo := newRepo(t)
+		tenant := uuid.NewString()
+		pending, err := repo.CreateChallenge(ctx, tenant, uuid.NewString(), uuid.NewString(), requestID, createdAt, expiresAt)
+		require.NoError(t, err)
+		_, err = repo.CreateChallenge(ctx, tenant, uuid.NewString(), uuid.NewString(), requestID, createdAt, time.Now().Add(-time.Minute).Unix())
+		require.NoError(t, err)
+
+		found, err := repo.SearchChallenges(ctx, tenant, domain.ChallengeFilter{Status: domain.ChallengeStatusPending, Now: createdAt})
+		require.NoError(t, err)
+		assert.Equal(t, []*domain.Challenge{pending}, found)
+
+		// stores with native expiry drop the expired challenge
+		found, err = repo.SearchChallenges(ctx, tenant, domain.ChallengeFilter{Status: domain.ChallengeStatusExpired, Now: createdAt})
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(found), 1)
+		for _, challenge := range found {
+			assert.NotEqual(t, pending.Nonce, challenge.Nonce)
+		}
+	})
+
+	t.Run("search pages through challenges after a cursor", func(t *testing.T) {
+		repo := newRepo(t)
+		tenant := uuid.NewString()
+		// challenges created in the same second are ordered by nonce
+		for i := 0; i < 5; i++ {
+			_, err := repo.CreateChallenge(ctx, tenant, uuid.NewString(), uuid.NewString(), requestID, createdAt-int64(i%2), expiresAt)
+			require.NoError(t, err)
+		}
+		all, err := repo.SearchChallenges(ctx, tenant, domain.ChallengeFilter{})
+		require.NoError(t, err)
+		require.Len(t, all, 5)
+
+		var paged []*domain.Challenge
+		filter := domain.ChallengeFilter{Limit: 2}
+		for {
+			page, err := repo.SearchChallenges(ctx, tenant, filter)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(page), 2)
+			if len(page) == 0 {
+				break
+			}
+			paged = append(paged, page...)
+			last := page[len(page)-1]
+			filter.After = &domain.ChallengeCursor{CreatedAt: last.CreatedAt, Nonce: last.Nonce}
+		}
+		assert.Equal(t, all, paged)
+	})
+
+	t.Run("expired challenge is no longer pending", func(t *testing.T) {
+		repo := newRepo(t)
+		pubKey, nonce := uuid.NewString(), uuid.NewString()
+		_, err := repo.CreateChallenge(ctx, tenant, pubKey, nonce, requestID, createdAt, expiresAt)
+		require.NoError(t, err)
+
+		now := time.Now().Unix()
+		expired, err := repo.ExpireChallenge(ctx, tenant, nonce, now)
+		require.NoError(t, err)
+		assert.True(t, expired)
+		challenges, err := repo.GetChallenges(ctx, tenant, pubKey, nonce)
+		require.NoError(t, err)
+		for _, challenge := range challenges {
+			assert.LessOrEqual(t, challenge.ExpiresAt, now)
+		}
+
+		expired, err = repo.ExpireChallenge(ctx, tenant, uuid.NewString(), now)
+		require.NoError(t, err)
+		assert.False(t, expired)
+		expired, err = repo.ExpireChallenge(ctx, uuid.NewString(), nonce, now)
+		require.NoError(t, err)
+		assert.False(t, expired, "another tenant must not expire the challenge")
+	})
+
+	t.Run("cancelled context fails the calls", func(t *testing.T) {
+		repo := newRepo(t)
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := repo.CreateChallenge(cancelled, tenant, uuid.NewString(), uuid.NewString(), requestID, createdAt, expiresAt)
+		assert.ErrorIs(t, err, context.Canceled)
+		_, err = repo.GetChallenges(cancelled, tenant, uuid.NewString(), uuid.NewString())
+		assert.ErrorIs(t, err, context.Canceled)
+		_, err = repo.ConsumeChallenge(cancelled, tenant, uuid.NewString(), uuid.NewString())
+		assert.ErrorIs(t, err, context.Canceled)
+		_, err = repo.SearchChallenges(cancelled, tenant, domain.ChallengeFilter{})
+		assert.ErrorIs(t, err, context.Canceled)
+		_, err = repo.ExpireChallenge(cancelled, tenant, uuid.NewString(), createdAt)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,89 @@
+package repositorytest
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ExtendedKeyRepository runs the conformance tests of ExtendedKeyRepository
+// against the repositories returned by newRepo. The repositories may share
+// their storage, every test uses its own keys.
+func ExtendedKeyRepository(t *testing.T, newRepo func(t *testing.T) repository.ExtendedKeyRepository) {
+	ctx := context.Background()
+	createdAt := time.Now().Unix()
+	tenant := uuid.NewString()
+	newKey := func() *domain.ExtendedKey {
+		return &domain.ExtendedKey{
+			TenantID:    tenant,
+			Xpub:        uuid.NewString(),
+			PathPattern: "0/*",
+			MaxIndex:    1<<31 - 1,
+			CreatedAt:   createdAt,
+		}
+	}
+
+	t.Run("enrolled key is found", func(t *testing.T) {
+		repo := newRepo(t)
+		key := newKey()
+		require.NoError(t, repo.EnrollExtendedKey(ctx, key))
+
+		found, err := repo.GetExtendedKey(ctx, tenant, key.Xpub)
+		require.NoError(t, err)
+		assert.Equal(t, key, found)
+
+		found, err = repo.GetExtendedKey(ctx, tenant, uuid.NewString())
+		require.NoError(t, err)
+		assert.Nil(t, found)
+	})
+
+	t.Run("enrolling a key again replaces it", func(t *testing.T) {
+		repo := newRepo(t)
+		key := newKey()
+		require.NoError(t, repo.EnrollExtendedKey(ctx, key))
+
+		again := *key
+		again.PathPattern = "1/*"
+		again.MaxIndex = 10
+		again.CreatedAt = createdAt + 1
+		require.NoError(t, repo.EnrollExtendedKey(ctx, &again))
+
+		found, err := repo.GetExtendedKey(ctx, tenant, key.Xpub)
+		require.NoError(t, err)
+		assert.Equal(t, &again, found)
+	})
+
+	t.Run("tenants are isolated", func(t *testing.T) {
+		repo := newRepo(t)
+		key := newKey()
+		require.NoError(t, repo.EnrollExtendedKey(ctx, key))
+
+		found, err := repo.GetExtendedKey(ctx, uuid.NewString(), key.Xpub)
+		require.NoError(t, err)
+		assert.Nil(t, found)
+
+		other := *key
+		other.TenantID = uuid.NewString()
+		other.MaxIndex = 5
+		require.NoError(t, repo.EnrollExtendedKey(ctx, &other))
+		found, err = repo.GetExtendedKey(ctx, tenant, key.Xpub)
+		require.NoError(t, err)
+		assert.Equal(t, key, found)
+	})
+
+	t.Run("cancelled context fails the calls", func(t *testing.T) {
+		repo := newRepo(t)
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		assert.ErrorIs(t, repo.EnrollExtendedKey(cancelled, newKey()), context.Canceled)
+		_, err := repo.GetExtendedKey(cancelled, tenant, uuid.NewString())
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
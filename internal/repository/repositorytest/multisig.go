@@ -0,0 +1,189 @@
+package repositorytest
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/repository"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MultisigRepository runs the conformance tests of MultisigRepository against
+// the repositories returned by newRepo. The repositories may share their
+// storage, every test uses its own nonces.
+func MultisigRepository(t *testing.T, newRepo func(t *testing.T) repository.MultisigRepository) {
+	ctx := context.Background()
+	createdAt := time.Now().Unix()
+	expiresAt := time.Now().Add(time.Minute).Unix()
+	tenant := uuid.NewString()
+	newChallenge := func() *domain.Challenge {
+		return &domain.Challenge{
+			TenantID:   tenant,
+			Nonce:      uuid.NewString(),
+			CreatedAt:  createdAt,
+			ExpiresAt:  expiresAt,
+			RequestID:  uuid.NewString(),
+			PublicKeys: []string{uuid.NewString(), uuid.NewString(), uuid.NewString()},
+			Threshold:  2,
+		}
+	}
+
+	t.Run("created challenge is found by nonce", func(t *testing.T) {
+		repo := newRepo(t)
+		challenge := newChallenge()
+		require.NoError(t, repo.CreateMultisigChallenge(ctx, challenge))
+
+		found, err := repo.GetMultisigChallenge(ctx, tenant, challenge.Nonce)
+		require.NoError(t, err)
+		assert.Equal(t, challenge, found)
+
+		found, err = repo.GetMultisigChallenge(ctx, tenant, uuid.NewString())
+		require.NoError(t, err)
+		assert.Nil(t, found)
+	})
+
+	t.Run("nonce is unique", func(t *testing.T) {
+		repo := newRepo(t)
+		challenge := newChallenge()
+		require.NoError(t, repo.CreateMultisigChallenge(ctx, challenge))
+
+		assert.ErrorIs(t, repo.CreateMultisigChallenge(ctx, challenge), repository.ErrNonceTaken)
+	})
+
+	t.Run("signers are kept in the order they signed", func(t *testing.T) {
+		repo := newRepo(t)
+		challenge := newChallenge()
+		require.NoError(t, repo.CreateMultisigChallenge(ctx, challenge))
+
+		// within the same second, which orders neither key first
+		for i, key := range []int{2, 0, 1} {
+			position, err := repo.AddSigner(ctx, tenant, challenge.Nonce, challenge.PublicKeys[key], createdAt)
+			require.NoError(t, err)
+			assert.Equal(t, i+1, position)
+		}
+
+		found, err := repo.GetMultisigChallenge(ctx, tenant, challenge.Nonce)
+		require.NoError(t, err)
+		assert.Equal(t, []string{challenge.PublicKeys[2], challenge.PublicKeys[0], challenge.PublicKeys[1]}, found.Signers)
+		assert.True(t, found.Verified())
+	})
+
+	t.Run("signer of no challenge is not added", func(t *testing.T) {
+		repo := newRepo(t)
+
+		position, err := repo.AddSigner(ctx, tenant, uuid.NewString(), uuid.NewString(), createdAt)
+		require.NoError(t, err)
+		assert.Zero(t, position)
+	})
+
+	t.Run("key signs once", func(t *testing.T) {
+		repo := newRepo(t)
+		challenge := newChallenge()
+		require.NoError(t, repo.CreateMultisigChallenge(ctx, challenge))
+		_, err := repo.AddSigner(ctx, tenant, challenge.Nonce, challenge.PublicKeys[0], createdAt)
+		require.NoError(t, err)
+
+		_, err = repo.AddSigner(ctx, tenant, challenge.Nonce, challenge.PublicKeys[0], createdAt+1)
+		assert.ErrorIs(t, err, repository.ErrDuplicateSigner)
+		// the duplicate takes no position
+		position, err := repo.AddSigner(ctx, tenant, challenge.Nonce, challenge.PublicKeys[1], createdAt+1)
+		require.NoError(t, err)
+		assert.Equal(t, 2, position)
+		found, err := repo.GetMultisigChallenge(ctx, tenant, challenge.Nonce)
+		require.NoError(t, err)
+		assert.Equal(t, []string{challenge.PublicKeys[0], challenge.PublicKeys[1]}, found.Signers)
+	})
+
+	t.Run("concurrent signatures of a key add it once", func(t *testing.T) {
+		repo := newRepo(t)
+		challenge := newChallenge()
+		require.NoError(t, repo.CreateMultisigChallenge(ctx, challenge))
+
+		const callers = 10
+		var wg sync.WaitGroup
+		errs := make(chan error, callers)
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repo.AddSigner(ctx, tenant, challenge.Nonce, challenge.PublicKeys[1], createdAt)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		added := 0
+		for err := range errs {
+			if err == nil {
+				added++
+				continue
+			}
+			assert.ErrorIs(t, err, repository.ErrDuplicateSigner)
+		}
+		assert.Equal(t, 1, added)
+	})
+
+	t.Run("concurrent signers take distinct positions", func(t *testing.T) {
+		repo := newRepo(t)
+		challenge := newChallenge()
+		require.NoError(t, repo.CreateMultisigChallenge(ctx, challenge))
+
+		var wg sync.WaitGroup
+		positions := make(chan int, len(challenge.PublicKeys))
+		for _, key := range challenge.PublicKeys {
+			wg.Add(1)
+			go func(key string) {
+				defer wg.Done()
+				position, err := repo.AddSigner(ctx, tenant, challenge.Nonce, key, createdAt)
+				assert.NoError(t, err)
+				positions <- position
+			}(key)
+		}
+		wg.Wait()
+		close(positions)
+
+		var taken []int
+		for position := range positions {
+			taken = append(taken, position)
+		}
+		assert.ElementsMatch(t, []int{1, 2, 3}, taken)
+	})
+
+	t.Run("tenants are isolated", func(t *testing.T) {
+		repo := newRepo(t)
+		challenge := newChallenge()
+		require.NoError(t, repo.CreateMultisigChallenge(ctx, challenge))
+
+		found, err := repo.GetMultisigChallenge(ctx, uuid.NewString(), challenge.Nonce)
+		require.NoError(t, err)
+		assert.Nil(t, found)
+
+		// nonces are unique within a tenant only
+		other := *challenge
+		other.TenantID = uuid.NewString()
+		require.NoError(t, repo.CreateMultisigChallenge(ctx, &other))
+		_, err = repo.AddSigner(ctx, other.TenantID, other.Nonce, other.PublicKeys[0], createdAt)
+		require.NoError(t, err)
+		found, err = repo.GetMultisigChallenge(ctx, tenant, challenge.Nonce)
+		require.NoError(t, err)
+		assert.Empty(t, found.Signers)
+	})
+
+	t.Run("cancelled context fails the calls", func(t *testing.T) {
+		repo := newRepo(t)
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		assert.ErrorIs(t, repo.CreateMultisigChallenge(cancelled, newChallenge()), context.Canceled)
+		_, err := repo.GetMultisigChallenge(cancelled, tenant, uuid.NewString())
+		assert.ErrorIs(t, err, context.Canceled)
+		_, err = repo.AddSigner(cancelled, tenant, uuid.NewString(), uuid.NewString(), createdAt)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/repository"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultSearchLimit is the size of a page of challenges when none is requested
+	DefaultSearchLimit = 50
+	MaxSearchLimit     = 500
+	// MaxStatsRange bounds the statistics of a request, a bucket per hour
+	MaxStatsRange = time.Hour * 24 * 31
+	// defaultStatsRange is the range of the statistics when none is requested
+	defaultStatsRange = time.Hour * 24
+
+	cursorSeparator = ":"
+)
+
+// AdminService lets operators inspect and manage the challenges of a tenant.
+type AdminService interface {
+	// SearchChallenges returns a page of the challenges of the filter, its
+	// After and Now are set from cursor, the NextCursor of the previous page.
+	SearchChallenges(ctx context.Context, tenantID string, filter domain.ChallengeFilter, cursor string) (*domain.ChallengePage, error)
+	// ExpireChallenge makes a challenge expire now, so that its token is no longer verified.
+	ExpireChallenge(ctx context.Context, tenantID, nonce string) error
+	// GetStats returns the statistics of every hour from from to to, the last
+	// day when they are zero.
+	GetStats(ctx context.Context, tenantID string, from, to int64) ([]*domain.HourlyStats, error)
+}
+
+type adminService struct {
+	repo    *repository.Repository
+	now     func() time.Time
+	tenants *tenants
+	log     *logrus.Entry
+}
+
+func NewAdminService(repo *repository.Repository, now func() time.Time, tenancy config.TenancyConfig, log *logrus.Entry) AdminService {
+	return &adminService{
+		repo:    repo,
+		now:     now,
+		tenants: newTenants(tenancy, config.ChallengeConfig{}),
+		log:     log,
+	}
+}
+
+func (s *adminService) SearchChallenges(ctx context.Context, tenantID string, filter domain.ChallengeFilter, cursor string) (*domain.ChallengePage, error) {
+	tenant, err := s.tenants.get(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Status != "" && filter.Status != domain.ChallengeStatusPending && filter.Status != domain.ChallengeStatusExpired {
+		return nil, fmt.Errorf("%w: status must be %s or %s", domain.ErrInvalidFilter, domain.ChallengeStatusPending, domain.ChallengeStatusExpired)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultSearchLimit
+	}
+	if filter.Limit < 0 || filter.Limit > MaxSearchLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidFilter, MaxSearchLimit)
+	}
+	if cursor != "" {
+		if filter.After, err = decodeCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+	filter.Now = s.now().Unix()
+
+	// one more challenge than the page tells whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+	challenges, err := s.repo.ChallengeRepo.SearchChallenges(ctx, tenant.ID, filter)
+	if err != nil {
+		s.logError(ctx, tenant.ID, "failed to search challenges", err)
+		return nil, err
+	}
+
+	page := &domain.ChallengePage{Challenges: challenges}
+	if len(challenges) > limit {
+		page.Challenges = challenges[:limit]
+		page.NextCursor = encodeCursor(challenges[limit-1])
+	}
+	if page.Challenges == nil {
+		page.Challenges = []*domain.Challenge{}
+	}
+
+	return page, nil
+}
+
+func (s *adminService) ExpireChallenge(ctx context.Context, tenantID, nonce string) error {
+	tenant, err := s.tenants.get(tenantID)
+	if err != nil {
+		return err
+	}
+
+	// a challenge is valid up to its expiry included
+	expired, err := s.repo.ChallengeRepo.ExpireChallenge(ctx, tenant.ID, nonce, s.now().Unix()-1)
+	if err != nil {
+		s.logError(ctx, tenant.ID, "failed to expire challenge", err)
+		return err
+	}
+	if !expired {
+		return domain.ErrChallengeNotFound
+	}
+	logging.FromContext(ctx, s.log).WithFields(logrus.Fields{
+		logging.FieldTenant: tenant.ID,
+		logging.FieldNonce:  nonce,
+	}).Info("challenge expired by an operator")
+
+	return nil
+}
+
+func (s *adminService) GetStats(ctx context.Context, tenantID string, from, to int64) ([]*domain.HourlyStats, error) {
+	tenant, err := s.tenants.get(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	end := s.now()
+	if to != 0 {
+		end = time.Unix(to, 0)
+	}
+	start := end.Add(-defaultStatsRange)
+	if from != 0 {
+		start = time.Unix(from, 0)
+	}
+	start = start.Truncate(time.Hour)
+	if end.Before(start) {
+		return nil, fmt.Errorf("%w: from must not be after to", domain.ErrInvalidFilter)
+	}
+	if end.Sub(start) > MaxStatsRange {
+		return nil, fmt.Errorf("%w: the range must not exceed %s", domain.ErrInvalidFilter, MaxStatsRange)
+	}
+
+	counted, err := s.repo.StatsRepo.GetStats(ctx, tenant.ID, start.Unix(), end.Unix())
+	if err != nil {
+		s.logError(ctx, tenant.ID, "failed to get challenge stats", err)
+		return nil, err
+	}
+
+	// the hours without events are reported with zero counts
+	byHour := map[int64]*domain.HourlyStats{}
+	for _, hourly := range counted {
+		byHour[hourly.Hour] = hourly
+	}
+	var stats []*domain.HourlyStats
+	for hour := start; !hour.After(end); hour = hour.Add(time.Hour) {
+		hourly, found := byHour[hour.Unix()]
+		if !found {
+			hourly = &domain.HourlyStats{Hour: hour.Unix()}
+		}
+		stats = append(stats, hourly)
+	}
+
+	return stats, nil
+}
+
+func (s *adminService) logError(ctx context.Context, tenantID, message string, err error) {
+	logging.FromContext(ctx, s.log).WithFields(logrus.Fields{
+		logging.FieldTenant:    tenantID,
+		logging.FieldErrorCode: domain.UnexpectedError,
+	}).WithError(err).Error(message)
+}
+
+// encodeCursor returns the opaque cursor of the position of a challenge.
+func encodeCursor(challenge *domain.Challenge) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(challenge.CreatedAt, 10) + cursorSeparator + challenge.Nonce))
+}
+
+func decodeCursor(cursor string) (*domain.ChallengeCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidFilter)
+	}
+	createdAt, nonce, found := strings.Cut(string(decoded), cursorSeparator)
+	if !found {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidFilter)
+	}
+	position, err := strconv.ParseInt(createdAt, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidFilter)
+	}
+
+	return &domain.ChallengeCursor{CreatedAt: position, Nonce: nonce}, nil
+}
@@ -0,0 +1,112 @@
+package service_test
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/service"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAdminService(now time.Time) (service.AdminService, *repository.Repository) {
+	repo := repository.NewRepository(repository.NoTx, repository.NewChallengeMemoryRepository(), nil, repository.NewChallengeStatsMemoryRepository(), repository.NewMultisigMemoryRepository(), repository.NewExtendedKeyMemoryRepository())
+
+	return service.NewAdminService(repo, func() time.Time { return now }, config.Default().Tenancy, logrus.NewEntry(logrus.New())), repo
+}
+
+func TestAdminService_SearchChallenges(t *testing.T) {
+	timeNow := time.Now()
+	adminService, repo := newAdminService(timeNow)
+	ctx := context.Background()
+	for _, nonce := range []string{"a", "b", "c"} {
+		_, err := repo.ChallengeRepo.CreateChallenge(ctx, "default", "key", nonce, "", timeNow.Unix(), timeNow.Add(time.Minute).Unix())
+		require.NoError(t, err)
+	}
+
+	var nonces []string
+	cursor := ""
+	for {
+		page, err := adminService.SearchChallenges(ctx, "", domain.ChallengeFilter{Limit: 2}, cursor)
+		require.NoError(t, err)
+		for _, challenge := range page.Challenges {
+			nonces = append(nonces, challenge.Nonce)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	assert.Equal(t, []string{"c", "b", "a"}, nonces)
+
+	page, err := adminService.SearchChallenges(ctx, "", domain.ChallengeFilter{Nonce: "unknown"}, "")
+	require.NoError(t, err)
+	assert.NotNil(t, page.Challenges)
+	assert.Empty(t, page.Challenges)
+
+	tests := []struct {
+		name   string
+		filter domain.ChallengeFilter
+		cursor string
+	}{
+		{name: "unknown status", filter: domain.ChallengeFilter{Status: "consumed"}},
+		{name: "negative limit", filter: domain.ChallengeFilter{Limit: -1}},
+		{name: "limit above the maximum", filter: domain.ChallengeFilter{Limit: service.MaxSearchLimit + 1}},
+		{name: "malformed cursor", cursor: "not a cursor"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := adminService.SearchChallenges(ctx, "", test.filter, test.cursor)
+			assert.ErrorIs(t, err, domain.ErrInvalidFilter)
+		})
+	}
+
+	_, err = adminService.SearchChallenges(ctx, "unknown", domain.ChallengeFilter{}, "")
+	assert.ErrorIs(t, err, domain.ErrUnknownTenant)
+}
+
+func TestAdminService_ExpireChallenge(t *testing.T) {
+	timeNow := time.Now()
+	adminService, repo := newAdminService(timeNow)
+	ctx := context.Background()
+	_, err := repo.ChallengeRepo.CreateChallenge(ctx, "default", "key", "nonce", "", timeNow.Unix(), timeNow.Add(time.Minute).Unix())
+	require.NoError(t, err)
+
+	require.NoError(t, adminService.ExpireChallenge(ctx, "", "nonce"))
+	page, err := adminService.SearchChallenges(ctx, "", domain.ChallengeFilter{Status: domain.ChallengeStatusExpired}, "")
+	require.NoError(t, err)
+	require.Len(t, page.Challenges, 1)
+	assert.Less(t, page.Challenges[0].ExpiresAt, timeNow.Unix())
+
+	assert.ErrorIs(t, adminService.ExpireChallenge(ctx, "", "unknown"), domain.ErrChallengeNotFound)
+}
+
+func TestAdminService_GetStats(t *testing.T) {
+	timeNow := time.Date(2026, 1, 2, 10, 30, 0, 0, time.UTC)
+	adminService, repo := newAdminService(timeNow)
+	ctx := context.Background()
+	hour := timeNow.Truncate(time.Hour).Unix()
+	require.NoError(t, repo.StatsRepo.AddStats(ctx, "default", hour, domain.StatCreated))
+	require.NoError(t, repo.StatsRepo.AddStats(ctx, "default", hour, domain.StatVerified))
+
+	// the last day by default, the hours without events included
+	stats, err := adminService.GetStats(ctx, "", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, stats, 25)
+	assert.Equal(t, &domain.HourlyStats{Hour: hour - 24*3600}, stats[0])
+	assert.Equal(t, &domain.HourlyStats{Hour: hour, Created: 1, Verified: 1}, stats[24])
+
+	stats, err = adminService.GetStats(ctx, "", hour-3600, hour)
+	require.NoError(t, err)
+	assert.Len(t, stats, 2)
+
+	_, err = adminService.GetStats(ctx, "", hour, hour-3600)
+	assert.ErrorIs(t, err, domain.ErrInvalidFilter)
+	_, err = adminService.GetStats(ctx, "", hour-int64((service.MaxStatsRange+time.Hour).Seconds()), hour)
+	assert.ErrorIs(t, err, domain.ErrInvalidFilter)
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/repository"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// apiKeyPrefix starts every API key, so that leaked keys are easy to find
+	apiKeyPrefix = "ck_"
+	// apiKeySeparator ends the ID of a key, it is not part of the base64url alphabet of the secret
+	apiKeySeparator = "."
+
+	apiKeyIDBytes     = 12
+	apiKeySecretBytes = 32
+
+	// adminKeyID is the ID of the admin key of the configuration
+	adminKeyID = "admin"
+)
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, tenantID, name string, scopes []string) (*domain.IssuedAPIKey, error)
+	// RotateAPIKey issues a key with the name and scopes of a key of the tenant and revokes it.
+	RotateAPIKey(ctx context.Context, tenantID, id string) (*domain.IssuedAPIKey, error)
+	RevokeAPIKey(ctx context.Context, tenantID, id string) error
+	GetAPIKey(ctx context.Context, tenantID, id string) (*domain.APIKey, error)
+	ListAPIKeys(ctx context.Context, tenantID string) ([]*domain.APIKey, error)
+	// Authenticate returns the key of a caller, or ErrInvalidAPIKey. The admin
+	// key of the configuration belongs to no tenant and acts for any of them.
+	Authenticate(ctx context.Context, key string) (*domain.APIKey, error)
+}
+
+type apiKeyService struct {
+	repo               *repository.Repository
+	now                func() time.Time
+	tenants            *tenants
+	adminKey           string
+	lastUsedResolution time.Duration
+	log                *logrus.Entry
+}
+
+func NewAPIKeyService(repo *repository.Repository, now func() time.Time, cfg config.AuthConfig, tenancy config.TenancyConfig, log *logrus.Entry) APIKeyService {
+	return &apiKeyService{
+		repo:               repo,
+		now:                now,
+		tenants:            newTenants(tenancy, config.ChallengeConfig{}),
+		adminKey:           cfg.AdminKey,
+		lastUsedResolution: cfg.LastUsedResolution,
+		log:                log,
+	}
+}
+
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, tenantID, name string, scopes []string) (*domain.IssuedAPIKey, error) {
+	tenant, err := s.tenants.get(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", domain.ErrInvalidScope)
+	}
+	for _, scope := range scopes {
+		if !contains(domain.Scopes, scope) {
+			return nil, fmt.Errorf("%w: %s is not one of %s", domain.ErrInvalidScope, scope, strings.Join(domain.Scopes, ", "))
+		}
+	}
+
+	issued, err := s.issue(tenant.ID, name, scopes)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.APIKeyRepo.CreateAPIKey(ctx, issued.APIKey); err != nil {
+		s.logError(ctx, tenant.ID, "failed to store api key", err)
+		return nil, err
+	}
+	s.logKey(ctx, issued.APIKey).Info("api key created")
+
+	return issued, nil
+}
+
+func (s *apiKeyService) RotateAPIKey(ctx context.Context, tenantID, id string) (*domain.IssuedAPIKey, error) {
+	tenant, err := s.tenants.get(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var issued *domain.IssuedAPIKey
+	err = s.repo.UnitOfWork.WithinTx(ctx, func(ctx context.Context) error {
+		key, err := s.repo.APIKeyRepo.GetAPIKey(ctx, id)
+		if err != nil {
+			return err
+		}
+		if key == nil || key.TenantID != tenant.ID || key.RevokedAt != 0 {
+			return domain.ErrAPIKeyNotFound
+		}
+
+		issued, err = s.issue(tenant.ID, key.Name, key.Scopes)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.APIKeyRepo.CreateAPIKey(ctx, issued.APIKey); err != nil {
+			return err
+		}
+		_, err = s.repo.APIKeyRepo.RevokeAPIKey(ctx, tenant.ID, id, s.now().Unix())
+		return err
+	})
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		s.logError(ctx, tenant.ID, "failed to rotate api key", err)
+		return nil, err
+	}
+	s.logKey(ctx, issued.APIKey).WithField("rotated_api_key_id", id).Info("api key rotated")
+
+	return issued, nil
+}
+
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, tenantID, id string) error {
+	tenant, err := s.tenants.get(tenantID)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := s.repo.APIKeyRepo.RevokeAPIKey(ctx, tenant.ID, id, s.now().Unix())
+	if err != nil {
+		s.logError(ctx, tenant.ID, "failed to revoke api key", err)
+		return err
+	}
+	if !revoked {
+		return domain.ErrAPIKeyNotFound
+	}
+	s.logKey(ctx, &domain.APIKey{ID: id, TenantID: tenant.ID}).Info("api key revoked")
+
+	return nil
+}
+
+func (s *apiKeyService) GetAPIKey(ctx context.Context, tenantID, id string) (*domain.APIKey, error) {
+	tenant, err := s.tenants.get(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.repo.APIKeyRepo.GetAPIKey(ctx, id)
+	if err != nil {
+		s.logError(ctx, tenant.ID, "failed to get api key", err)
+		return nil, err
+	}
+	if key == nil || key.TenantID != tenant.ID {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	return key, nil
+}
+
+func (s *apiKeyService) ListAPIKeys(ctx context.Context, tenantID string) ([]*domain.APIKey, error) {
+	tenant, err := s.tenants.get(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.repo.APIKeyRepo.ListAPIKeys(ctx, tenant.ID)
+	if err != nil {
+		s.logError(ctx, tenant.ID, "failed to list api keys", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (s *apiKeyService) Authenticate(ctx context.Context, key string) (*domain.APIKey, error) {
+	if s.adminKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(s.adminKey)) == 1 {
+		return &domain.APIKey{ID: adminKeyID, Name: adminKeyID, Scopes: []string{domain.ScopeAdmin}}, nil
+	}
+
+	id, secret, found := strings.Cut(strings.TrimPrefix(key, apiKeyPrefix), apiKeySeparator)
+	if !found || !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	stored, err := s.repo.APIKeyRepo.GetAPIKey(ctx, id)
+	if err != nil {
+		s.logError(ctx, "", "failed to get api key", err)
+		return nil, err
+	}
+	hash := sha256.Sum256([]byte(secret))
+	if stored == nil || subtle.ConstantTimeCompare(hash[:], stored.Hash) != 1 || stored.RevokedAt != 0 {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	// the last use is recorded at most once per resolution, so that every call does not write
+	now := s.now()
+	if now.Sub(time.Unix(stored.LastUsedAt, 0)) >= s.lastUsedResolution {
+		if err := s.repo.APIKeyRepo.TouchAPIKey(ctx, stored.ID, now.Unix()); err != nil {
+			// the caller is authenticated all the same
+			s.logError(ctx, stored.TenantID, "failed to record api key use", err)
+		} else {
+			stored.LastUsedAt = now.Unix()
+		}
+	}
+
+	return stored, nil
+}
+
+// issue generates a key of the tenant.
+func (s *apiKeyService) issue(tenantID, name string, scopes []string) (*domain.IssuedAPIKey, error) {
+	id := make([]byte, apiKeyIDBytes)
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("could not generate api key: %w", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("could not generate api key: %w", err)
+	}
+	encodedSecret := base64.RawURLEncoding.EncodeToString(secret)
+	hash := sha256.Sum256([]byte(encodedSecret))
+
+	key := &domain.APIKey{
+		ID:        hex.EncodeToString(id),
+		TenantID:  tenantID,
+		Name:      name,
+		Scopes:    scopes,
+		Hash:      hash[:],
+		CreatedAt: s.now().Unix(),
+	}
+
+	return &domain.IssuedAPIKey{
+		APIKey: key,
+		Key:    apiKeyPrefix + key.ID + apiKeySeparator + encodedSecret,
+	}, nil
+}
+
+func (s *apiKeyService) logKey(ctx context.Context, key *domain.APIKey) *logrus.Entry {
+	return logging.FromContext(ctx, s.log).WithFields(logrus.Fields{
+		logging.FieldTenant: key.TenantID,
+		logging.FieldAPIKey: key.ID,
+	})
+}
+
+func (s *apiKeyService) logError(ctx context.Context, tenantID, message string, err error) {
+	logging.FromContext(ctx, s.log).WithFields(logrus.Fields{
+		logging.FieldTenant:    tenantID,
+		logging.FieldErrorCode: domain.UnexpectedError,
+	}).WithError(err).Error(message)
+}
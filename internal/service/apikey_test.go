@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAdminKey = "0123456789abcdef0123456789abcdef"
+
+func newAPIKeyService(now func() time.Time) service.APIKeyService {
+	cfg := config.Default().Auth
+	cfg.AdminKey = testAdminKey
+	tenancy := config.TenancyConfig{
+		DefaultTenant: "shop",
+		Tenants:       []config.TenantConfig{{ID: "shop"}, {ID: "bank"}},
+	}
+	repo := repository.NewRepository(repository.NoTx, nil, repository.NewAPIKeyMemoryRepository(), nil, nil, nil)
+
+	return service.NewAPIKeyService(repo, now, cfg, tenancy, logrus.NewEntry(logrus.New()))
+}
+
+func TestAPIKeyService_CreateAPIKey(t *testing.T) {
+	apiKeyService := newAPIKeyService(time.Now)
+	ctx := context.Background()
+
+	issued, err := apiKeyService.CreateAPIKey(ctx, "", "checkout", []string{domain.ScopeCreate, domain.ScopeVerify})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(issued.Key, "ck_"+issued.ID+"."))
+	assert.Equal(t, "shop", issued.TenantID)
+
+	key, err := apiKeyService.Authenticate(ctx, issued.Key)
+	require.NoError(t, err)
+	assert.Equal(t, issued.ID, key.ID)
+	assert.True(t, key.HasScope(domain.ScopeVerify))
+	assert.False(t, key.HasScope(domain.ScopeAdmin))
+
+	_, err = apiKeyService.Authenticate(ctx, issued.Key+"x")
+	assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+	_, err = apiKeyService.Authenticate(ctx, "ck_unknown.secret")
+	assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+
+	_, err = apiKeyService.CreateAPIKey(ctx, "shop", "checkout", []string{"delete"})
+	assert.ErrorIs(t, err, domain.ErrInvalidScope)
+	_, err = apiKeyService.CreateAPIKey(ctx, "shop", "checkout", nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidScope)
+	_, err = apiKeyService.CreateAPIKey(ctx, "unknown", "checkout", []string{domain.ScopeCreate})
+	assert.ErrorIs(t, err, domain.ErrUnknownTenant)
+}
+
+func TestAPIKeyService_RevokeAPIKey(t *testing.T) {
+	apiKeyService := newAPIKeyService(time.Now)
+	ctx := context.Background()
+	issued, err := apiKeyService.CreateAPIKey(ctx, "shop", "checkout", []string{domain.ScopeCreate})
+	require.NoError(t, err)
+
+	// a tenant can not revoke the keys of another tenant
+	assert.ErrorIs(t, apiKeyService.RevokeAPIKey(ctx, "bank", issued.ID), domain.ErrAPIKeyNotFound)
+	_, err = apiKeyService.Authenticate(ctx, issued.Key)
+	require.NoError(t, err)
+
+	require.NoError(t, apiKeyService.RevokeAPIKey(ctx, "shop", issued.ID))
+	_, err = apiKeyService.Authenticate(ctx, issued.Key)
+	assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+
+	keys, err := apiKeyService.ListAPIKeys(ctx, "shop")
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.NotZero(t, keys[0].RevokedAt)
+
+	assert.ErrorIs(t, apiKeyService.RevokeAPIKey(ctx, "shop", "unknown"), domain.ErrAPIKeyNotFound)
+}
+
+func TestAPIKeyService_RotateAPIKey(t *testing.T) {
+	apiKeyService := newAPIKeyService(time.Now)
+	ctx := context.Background()
+	issued, err := apiKeyService.CreateAPIKey(ctx, "shop", "checkout", []string{domain.ScopeVerify})
+	require.NoError(t, err)
+
+	_, err = apiKeyService.RotateAPIKey(ctx, "bank", issued.ID)
+	assert.ErrorIs(t, err, domain.ErrAPIKeyNotFound)
+
+	rotated, err := apiKeyService.RotateAPIKey(ctx, "shop", issued.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, issued.ID, rotated.ID)
+	assert.Equal(t, issued.Name, rotated.Name)
+	assert.Equal(t, issued.Scopes, rotated.Scopes)
+
+	_, err = apiKeyService.Authenticate(ctx, issued.Key)
+	assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+	_, err = apiKeyService.Authenticate(ctx, rotated.Key)
+	assert.NoError(t, err)
+
+	// a revoked key can not be rotated again
+	_, err = apiKeyService.RotateAPIKey(ctx, "shop", issued.ID)
+	assert.ErrorIs(t, err, domain.ErrAPIKeyNotFound)
+}
+
+func TestAPIKeyService_LastUsed(t *testing.T) {
+	timeNow := time.Now()
+	now := timeNow
+	apiKeyService := newAPIKeyService(func() time.Time { return now })
+	ctx := context.Background()
+	issued, err := apiKeyService.CreateAPIKey(ctx, "shop", "checkout", []string{domain.ScopeCreate})
+	require.NoError(t, err)
+
+	key, err := apiKeyService.Authenticate(ctx, issued.Key)
+	require.NoError(t, err)
+	assert.Equal(t, timeNow.Unix(), key.LastUsedAt)
+
+	// uses within the resolution are not recorded
+	now = timeNow.Add(config.Default().Auth.LastUsedResolution / 2)
+	key, err = apiKeyService.Authenticate(ctx, issued.Key)
+	require.NoError(t, err)
+	assert.Equal(t, timeNow.Unix(), key.LastUsedAt)
+
+	now = timeNow.Add(config.Default().Auth.LastUsedResolution)
+	key, err = apiKeyService.Authenticate(ctx, issued.Key)
+	require.NoError(t, err)
+	assert.Equal(t, now.Unix(), key.LastUsedAt)
+}
+
+func TestAPIKeyService_AdminKey(t *testing.T) {
+	key, err := newAPIKeyService(time.Now).Authenticate(context.Background(), testAdminKey)
+	require.NoError(t, err)
+	assert.True(t, key.HasScope(domain.ScopeAdmin))
+	assert.Empty(t, key.TenantID)
+}
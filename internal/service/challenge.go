@@ -3,68 +3,248 @@ package service
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
+	"crypto-project-1/internal/bip32"
+	"crypto-project-1/internal/config"
 	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
 	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/tracing"
+	"crypto/ecdsa"
+	"crypto/rsa"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"encoding/pem"
 	"errors"
+	"fmt"
 	"github.com/dgrijalva/jwt-go"
-	"github.com/google/uuid"
-	logger "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
 	"io/ioutil"
+	"strings"
 	"time"
 )
 
 type ChallengeService interface {
-	CreateChallenge(string) (*domain.Challenge, error)
-	VerifyChallenge(string) (*domain.ChallengeValidationResult, error)
+	// The tenant is given after the context, the default tenant when empty.
+	CreateChallenge(ctx context.Context, tenantID, pubKey string) (*domain.Challenge, error)
+	VerifyChallenge(ctx context.Context, tenantID, signedToken string) (*domain.ChallengeValidationResult, error)
+	// CreateFrostChallenge creates a challenge of the group key of a FROST
+	// threshold group, which signs its message detached.
+	CreateFrostChallenge(ctx context.Context, tenantID, groupKey string) (*domain.Challenge, error)
+	// VerifyFrostSignature verifies the aggregated signature of the group of
+	// the challenge of the nonce.
+	VerifyFrostSignature(ctx context.Context, tenantID, groupKey, nonce, signature string) (*domain.ChallengeValidationResult, error)
+	// GetChallengeStatus reports the progress of a multi-signature challenge
+	// to any of its public keys.
+	GetChallengeStatus(ctx context.Context, tenantID, pubKey, nonce string) (*domain.ChallengeStatus, error)
+	// CreateMultisigChallenge creates a challenge that threshold of the
+	// public keys must sign, each with a token of its own.
+	CreateMultisigChallenge(ctx context.Context, tenantID string, pubKeys []string, threshold int) (*domain.Challenge, error)
+	// SignMultisigChallenge adds the signers of the tokens to the challenge of
+	// the nonce, which is verified once it has its threshold of signers.
+	SignMultisigChallenge(ctx context.Context, tenantID, nonce string, tokens []string) (*domain.MultisigResult, error)
+	// EnrollExtendedKey enrolls a BIP-32 extended public key, whose challenges
+	// are signed by its children at the paths of pathPattern up to maxIndex.
+	EnrollExtendedKey(ctx context.Context, tenantID, xpub, pathPattern string, maxIndex uint32) (*domain.ExtendedKey, error)
+}
+
+// challengeClaims are the claims of a token, Message is the message of its
+// challenge when its tenant has a domain.
+type challengeClaims struct {
+	jwt.StandardClaims
+	Message string `json:"msg,omitempty"`
 }
 
 type challengeService struct {
-	repo *repository.Repository
-	now  func() time.Time
+	repo          *repository.Repository
+	now           func() time.Time
+	tenants       *tenants
+	nonces        NonceGenerators
+	createTimeout time.Duration
+	verifyTimeout time.Duration
+	statusTimeout time.Duration
+	singleUse     bool
+	log           *logrus.Entry
 }
 
 const (
-	nonceTimeToLive = time.Minute * 5
 	publicKeyHeader = "kid"
+	tracerName      = "crypto-project-1/internal/service"
+
+	ValidationErrorInvalidNonce   = "invalid nonce"
+	ValidationErrorExpiredNonce   = "expired nonce"
+	ValidationErrorInvalidMessage = "invalid message"
+	// a multi-signature challenge accepts a token of each of its keys once
+	ValidationErrorUnknownSigner   = "unknown signer"
+	ValidationErrorDuplicateSigner = "duplicate signer"
+
+	// values of the outcome log field
+	outcomeValid        = "valid"
+	outcomeInvalidToken = "invalid_token"
+	outcomeInvalidNonce = "invalid_nonce"
+	outcomeExpiredNonce = "expired_nonce"
+	outcomeInvalidMsg   = "invalid_message"
+	outcomeInvalidKey   = "invalid_public_key"
+	outcomeUnknownKey   = "unknown_signer"
+	outcomeDuplicateKey = "duplicate_signer"
+	outcomeCreated      = "created"
+	outcomeEnrolled     = "enrolled"
+	outcomeError        = "error"
 )
 
-func NewChallengeService(repo *repository.Repository, now func() time.Time) ChallengeService {
+func NewChallengeService(repo *repository.Repository, now func() time.Time, cfg config.ChallengeConfig, tenancy config.TenancyConfig, nonces NonceGenerators, log *logrus.Entry) ChallengeService {
 	return &challengeService{
-		repo,
-		now,
+		repo:          repo,
+		now:           now,
+		tenants:       newTenants(tenancy, cfg),
+		nonces:        nonces,
+		createTimeout: cfg.CreateTimeout,
+		verifyTimeout: cfg.VerifyTimeout,
+		statusTimeout: cfg.StatusTimeout,
+		singleUse:     cfg.SingleUse,
+		log:           log,
 	}
 }
 
-func (cs *challengeService) CreateChallenge(pubKey string) (*domain.Challenge, error) {
-	// validate public key
-	if _, err := decompressPublicKey(pubKey); err != nil {
+func (cs *challengeService) CreateChallenge(ctx context.Context, tenantID, pubKey string) (_ *domain.Challenge, err error) {
+	ctx, cancel := context.WithTimeout(ctx, cs.createTimeout)
+	defer cancel()
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "ChallengeService.CreateChallenge")
+	defer func() { tracing.End(span, err) }()
+
+	tenant, err := cs.tenants.get(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	log := logging.FromContext(ctx, cs.log).WithFields(logrus.Fields{
+		logging.FieldTenant:         tenant.ID,
+		logging.FieldKeyFingerprint: logging.KeyFingerprint(pubKey),
+	})
+
+	if err := cs.validatePublicKey(ctx, tenant, pubKey); err != nil {
+		log.WithField(logging.FieldOutcome, outcomeInvalidKey).WithError(err).Info("challenge not created")
 		return nil, err
 	}
 
-	return cs.repo.ChallengeRepo.CreateChallenge(pubKey, uuid.NewString(), cs.now().Add(nonceTimeToLive).Unix())
+	return cs.createChallenge(ctx, tenant, pubKey, log)
 }
 
-func (cs *challengeService) VerifyChallenge(signedToken string) (*domain.ChallengeValidationResult, error) {
-	claims := &jwt.StandardClaims{}
+// createChallenge stores a new challenge of a validated public key.
+func (cs *challengeService) createChallenge(ctx context.Context, tenant domain.Tenant, pubKey string, log *logrus.Entry) (*domain.Challenge, error) {
+	nonce, err := cs.newNonce(tenant)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			logging.FieldOutcome:   outcomeError,
+			logging.FieldErrorCode: domain.UnexpectedError,
+		}).WithError(err).Error("failed to generate nonce")
+		return nil, err
+	}
 
-	token, err := jwt.ParseWithClaims(signedToken, claims, getPublicKey)
+	now := cs.now()
+	challenge, err := cs.repo.ChallengeRepo.CreateChallenge(ctx, tenant.ID, pubKey, nonce, logging.RequestID(ctx), now.Unix(), now.Add(tenant.NonceTimeToLive).Unix())
 	if err != nil {
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "failed to parse and validate token ", err)
+		log.WithFields(logrus.Fields{
+			logging.FieldOutcome:   outcomeError,
+			logging.FieldErrorCode: domain.UnexpectedError,
+		}).WithError(err).Error("failed to store challenge")
+		return nil, err
+	}
+	if challenge.Message, err = domain.RenderMessage(tenant, challenge); err != nil {
+		log.WithFields(logrus.Fields{
+			logging.FieldOutcome:   outcomeError,
+			logging.FieldErrorCode: domain.UnexpectedError,
+		}).WithError(err).Error("failed to render challenge message")
+		return nil, err
+	}
+	log.WithFields(logrus.Fields{
+		logging.FieldNonce:   challenge.Nonce,
+		logging.FieldOutcome: outcomeCreated,
+	}).Info("challenge created")
+	cs.addStats(ctx, tenant.ID, domain.StatCreated)
+
+	return challenge, nil
+}
+
+// validatePublicKey checks that the tenant allows the format of the public key
+// and that it decodes, or that an extended key is enrolled.
+func (cs *challengeService) validatePublicKey(ctx context.Context, tenant domain.Tenant, pubKey string) (err error) {
+	if bip32.IsExtendedKey(pubKey) {
+		_, err := cs.enrolledKey(ctx, tenant, pubKey)
+		return err
+	}
+	if !contains(tenant.KeyFormats, domain.KeyFormatCompressedPEM) {
+		return fmt.Errorf("%w: key format %s is not allowed", domain.ErrInvalidPublicKey, domain.KeyFormatCompressedPEM)
+	}
+
+	_, decodeSpan := otel.Tracer(tracerName).Start(ctx, "decodePublicKey")
+	_, err = decompressPublicKey(pubKey)
+	tracing.End(decodeSpan, err)
+	if err != nil {
+		return fmt.Errorf("%w: %s", domain.ErrInvalidPublicKey, err)
+	}
+
+	return nil
+}
+
+// newNonce generates a nonce in the format of the tenant.
+func (cs *challengeService) newNonce(tenant domain.Tenant) (string, error) {
+	generator, found := cs.nonces[tenant.NonceFormat]
+	if !found {
+		return "", fmt.Errorf("no generator of nonce format %s", tenant.NonceFormat)
+	}
+
+	return generator.NewNonce()
+}
+
+func (cs *challengeService) VerifyChallenge(ctx context.Context, tenantID, signedToken string) (result *domain.ChallengeValidationResult, err error) {
+	ctx, cancel := context.WithTimeout(ctx, cs.verifyTimeout)
+	defer cancel()
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "ChallengeService.VerifyChallenge")
+	defer func() { tracing.End(span, err) }()
+
+	tenant, err := cs.tenants.get(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err == nil {
+			cs.addStats(ctx, tenant.ID, verificationStat(result))
+		}
+	}()
+	log := logging.FromContext(ctx, cs.log).WithField(logging.FieldTenant, tenant.ID)
+	token, claims, pubKey, validationError, err := cs.parseToken(ctx, tenant, signedToken)
+	if validationError != "" {
+		log.WithField(logging.FieldOutcome, outcomeInvalidToken).WithError(err).Info("challenge verification failed")
 		return &domain.ChallengeValidationResult{
 			Valid:           false,
-			ValidationError: err.Error(),
+			ValidationError: validationError,
 		}, nil
 	}
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			logging.FieldOutcome:   outcomeError,
+			logging.FieldErrorCode: domain.UnexpectedError,
+		}).WithError(err).Error("failed to get public key of token")
+		return &domain.ChallengeValidationResult{
+			Valid: false,
+		}, err
+	}
 
 	compressedPubKey := (token.Header["kid"]).(string)
+	log = log.WithFields(logrus.Fields{
+		logging.FieldKeyFingerprint: logging.KeyFingerprint(compressedPubKey),
+		logging.FieldNonce:          claims.Id,
+	})
 	// get challenge from repo using pub key and nonce
-	challenges, err := cs.repo.ChallengeRepo.GetChallenges(compressedPubKey, claims.Id)
+	challenges, err := cs.verifiedChallenges(ctx, tenant.ID, compressedPubKey, claims.Id)
 	if err != nil {
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "failed to get challenge from repo; nonce: ", claims.Id)
+		log.WithFields(logrus.Fields{
+			logging.FieldOutcome:   outcomeError,
+			logging.FieldErrorCode: domain.UnexpectedError,
+		}).WithError(err).Error("failed to get challenge from repo")
 		return &domain.ChallengeValidationResult{
 			Valid: false,
 		}, err
@@ -72,56 +252,264 @@ func (cs *challengeService) VerifyChallenge(signedToken string) (*domain.Challen
 
 	// if no challenge found in repo for the pubkey+nonce combination, it means token nonce is invalid
 	if len(challenges) == 0 {
+		log.WithField(logging.FieldOutcome, outcomeInvalidNonce).Info("challenge verification failed")
 		return &domain.ChallengeValidationResult{
 			Valid:           false,
-			ValidationError: "invalid nonce",
+			ValidationError: ValidationErrorInvalidNonce,
 		}, nil
 	}
 
 	if challenges[0].ExpiresAt < cs.now().Unix() {
+		log.WithField(logging.FieldOutcome, outcomeExpiredNonce).Info("challenge verification failed")
+		return &domain.ChallengeValidationResult{
+			Valid:           false,
+			ValidationError: ValidationErrorExpiredNonce,
+		}, nil
+	}
+
+	// the message binds the token to what its signer was shown
+	message, err := domain.RenderMessage(tenant, challenges[0])
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			logging.FieldOutcome:   outcomeError,
+			logging.FieldErrorCode: domain.UnexpectedError,
+		}).WithError(err).Error("failed to render challenge message")
+		return &domain.ChallengeValidationResult{
+			Valid: false,
+		}, err
+	}
+	if claims.Message != message {
+		log.WithField(logging.FieldOutcome, outcomeInvalidMsg).Info("challenge verification failed")
 		return &domain.ChallengeValidationResult{
 			Valid:           false,
-			ValidationError: "expired nonce",
+			ValidationError: ValidationErrorInvalidMessage,
 		}, nil
 	}
 
-	return &domain.ChallengeValidationResult{
-		Valid: token.Valid,
-	}, nil
+	result, err = cs.validResult(signedToken, token, pubKey, challenges[0])
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			logging.FieldOutcome:   outcomeError,
+			logging.FieldErrorCode: domain.UnexpectedError,
+		}).WithError(err).Error("failed to decode token claims")
+		return &domain.ChallengeValidationResult{
+			Valid: false,
+		}, err
+	}
+
+	log.WithField(logging.FieldOutcome, outcomeValid).Info("challenge verified")
+	return result, nil
+}
+
+// parseToken verifies the signature of a token with the public key of its kid
+// header, and that the tenant allows its algorithm and audience. The
+// validation error of an invalid token comes with the error that caused it,
+// a failure to read the enrollment of an extended key has none.
+func (cs *challengeService) parseToken(ctx context.Context, tenant domain.Tenant, signedToken string) (*jwt.Token, *challengeClaims, interface{}, string, error) {
+	claims := &challengeClaims{}
+	parser := &jwt.Parser{ValidMethods: tenant.Algorithms}
+
+	// the key is decoded ahead of the signature check so that both are traced apart,
+	// jwt reports the decoding error through the key function
+	pubKey, keyErr := cs.decodeTokenPublicKey(ctx, tenant, signedToken)
+	if errors.Is(keyErr, errExtendedKeyLookup) {
+		return nil, nil, nil, "", keyErr
+	}
+	_, verifySpan := otel.Tracer(tracerName).Start(ctx, "verifySignature")
+	token, err := parser.ParseWithClaims(signedToken, claims, func(*jwt.Token) (interface{}, error) {
+		return pubKey, keyErr
+	})
+	tracing.End(verifySpan, err)
+	if err != nil {
+		validationError := err.Error()
+		if token != nil && token.Method != nil && !contains(tenant.Algorithms, token.Method.Alg()) {
+			validationError = ValidationErrorAlgorithmNotAllowed
+		}
+		return nil, nil, nil, validationError, err
+	}
+	if !allowsAudience(tenant, claims.Audience) {
+		return nil, nil, nil, ValidationErrorAudienceNotAllowed, errors.New(ValidationErrorAudienceNotAllowed)
+	}
+
+	return token, claims, pubKey, "", nil
+}
+
+// validResult tells what a valid token of the challenge proved.
+func (cs *challengeService) validResult(signedToken string, token *jwt.Token, pubKey interface{}, challenge *domain.Challenge) (*domain.ChallengeValidationResult, error) {
+	verifiedClaims, err := tokenClaims(signedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.ChallengeValidationResult{
+		Valid:          token.Valid,
+		KeyFingerprint: logging.KeyFingerprint((token.Header[publicKeyHeader]).(string)),
+		KeyType:        keyType(pubKey),
+		Algorithm:      token.Method.Alg(),
+		Claims:         verifiedClaims,
+		CreatedAt:      challenge.CreatedAt,
+		ExpiresAt:      challenge.ExpiresAt,
+		VerifiedAt:     cs.now().Unix(),
+	}
+	if derived, ok := pubKey.(*derivedKey); ok {
+		result.DerivationPath = derived.path
+	}
+
+	return result, nil
+}
+
+// tokenClaims decodes every claim of a verified token, the custom ones
+// included. Numbers are kept as they were signed.
+func tokenClaims(signedToken string) (map[string]interface{}, error) {
+	parts := strings.Split(signedToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token does not have 3 parts")
+	}
+	payload, err := jwt.DecodeSegment(parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	claims := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// keyType names the type of a public key like JWK does, with its curve or size.
+func keyType(pubKey interface{}) string {
+	switch key := pubKey.(type) {
+	case *ecdsa.PublicKey:
+		return "EC " + key.Curve.Params().Name
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", key.N.BitLen())
+	case *derivedKey:
+		return "EC secp256k1"
+	}
+
+	return ""
+}
+
+// verifiedChallenges returns the challenges of a token whose signature is
+// valid. Single-use challenges are removed as they are read, so that a replay
+// of the token, even a concurrent one, finds none.
+func (cs *challengeService) verifiedChallenges(ctx context.Context, tenantID, pubKey, nonce string) ([]*domain.Challenge, error) {
+	if !cs.singleUse {
+		return cs.repo.ChallengeRepo.GetChallenges(ctx, tenantID, pubKey, nonce)
+	}
+
+	challenge, err := cs.repo.ChallengeRepo.ConsumeChallenge(ctx, tenantID, pubKey, nonce)
+	if err != nil || challenge == nil {
+		return nil, err
+	}
+
+	return []*domain.Challenge{challenge}, nil
+}
+
+func (cs *challengeService) GetChallengeStatus(ctx context.Context, tenantID, pubKey, nonce string) (*domain.ChallengeStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, cs.statusTimeout)
+	defer cancel()
+
+	tenant, err := cs.tenants.get(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	challenges, err := cs.repo.ChallengeRepo.GetChallenges(ctx, tenant.ID, pubKey, nonce)
+	if err != nil {
+		logging.FromContext(ctx, cs.log).WithFields(logrus.Fields{
+			logging.FieldTenant:         tenant.ID,
+			logging.FieldKeyFingerprint: logging.KeyFingerprint(pubKey),
+			logging.FieldNonce:          nonce,
+			logging.FieldErrorCode:      domain.UnexpectedError,
+		}).WithError(err).Error("failed to get challenge from repo")
+		return nil, err
+	}
+
+	status := &domain.ChallengeStatus{
+		PublicKey: pubKey,
+		Nonce:     nonce,
+		Status:    domain.ChallengeStatusNotFound,
+	}
+	if len(challenges) == 0 {
+		return cs.multisigStatus(ctx, tenant, status)
+	}
+
+	status.ExpiresAt = challenges[0].ExpiresAt
+	status.Status = domain.ChallengeStatusPending
+	if challenges[0].ExpiresAt < cs.now().Unix() {
+		status.Status = domain.ChallengeStatusExpired
+	}
+
+	return status, nil
+}
+
+// addStats counts an event of the tenant in the current hour. The statistics
+// are informative, a failure to count is logged only.
+func (cs *challengeService) addStats(ctx context.Context, tenantID, event string) {
+	hour := cs.now().Truncate(time.Hour).Unix()
+	if err := cs.repo.StatsRepo.AddStats(ctx, tenantID, hour, event); err != nil {
+		logging.FromContext(ctx, cs.log).WithFields(logrus.Fields{
+			logging.FieldTenant:    tenantID,
+			logging.FieldErrorCode: domain.UnexpectedError,
+		}).WithError(err).Error("failed to add challenge stats")
+	}
+}
+
+func verificationStat(result *domain.ChallengeValidationResult) string {
+	if result.Valid {
+		return domain.StatVerified
+	}
+
+	return domain.StatFailed
+}
+
+// decodeTokenPublicKey reads the public key of the kid header of the
+// unverified token, the child of an enrolled extended key is derived.
+func (cs *challengeService) decodeTokenPublicKey(ctx context.Context, tenant domain.Tenant, signedToken string) (_ interface{}, err error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "decodePublicKey")
+	defer func() { tracing.End(span, err) }()
+
+	token, _, err := new(jwt.Parser).ParseUnverified(signedToken, &jwt.StandardClaims{})
+	if err != nil {
+		return nil, err
+	}
+	if kid, ok := token.Header[publicKeyHeader].(string); ok && bip32.IsExtendedKey(kid) {
+		return cs.tokenDerivedKey(ctx, tenant, token, kid)
+	}
+
+	return getPublicKey(token)
 }
 
 func getPublicKey(token *jwt.Token) (interface{}, error) {
 	pubKeyHeader, found := token.Header[publicKeyHeader]
 	if !found {
-		message := "public key header not found"
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError, message)
-		err := errors.New(message)
-		return nil, err
+		return nil, errors.New("public key header not found")
 	}
 
 	compressedPublicKey, ok := pubKeyHeader.(string)
 	if !ok {
-		message := "failed to parse public key header to string"
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError, message)
-		err := errors.New(message)
-		return nil, err
+		return nil, errors.New("failed to parse public key header to string")
 	}
 
 	decompressedPublicKey, err := decompressPublicKey(compressedPublicKey)
 	if err != nil {
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "failed to decompress public key ", err)
 		return nil, err
 	}
 	stringKey, err := hex.DecodeString(string(decompressedPublicKey))
 	if err != nil {
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "failed to decode hex public key string ", err)
 		return nil, err
 	}
 
 	decodedKey, _ := pem.Decode(stringKey)
+	if decodedKey == nil {
+		return nil, errors.New("public key is not PEM encoded")
+	}
 	pubKey, err := x509.ParsePKIXPublicKey(decodedKey.Bytes)
 	if err != nil {
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "failed to parse public key ", err)
 		return nil, err
 	}
 
@@ -131,17 +519,14 @@ func getPublicKey(token *jwt.Token) (interface{}, error) {
 func decompressPublicKey(compressed string) ([]byte, error) {
 	decoded, err := base64.StdEncoding.DecodeString(compressed)
 	if err != nil {
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "failed to decode compressed pub key string ", err)
 		return nil, err
 	}
 	gzipReader, err := gzip.NewReader(bytes.NewReader(decoded))
 	if err != nil {
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "failed to create gzip reader ", err)
 		return nil, err
 	}
 	decompressedPublicKey, err := ioutil.ReadAll(gzipReader)
 	if err != nil {
-		logger.Error(domain.CryptoAPIError, domain.UnexpectedError, "failed to read bytes from gzip reader ", err)
 		return nil, err
 	}
 
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"crypto"
+	"crypto-project-1/internal/domain"
+	"crypto/rand"
+	"crypto/sha256"
+
+	"github.com/dgrijalva/jwt-go"
+	"gitlab.com/yawning/secp256k1-voi/secec"
+)
+
+// signingMethodES256K is ES256K of RFC 8812, ECDSA on secp256k1 with SHA-256
+// and r||s signatures, which jwt does not implement.
+type signingMethodES256K struct{}
+
+var es256kOptions = &secec.ECDSAOptions{Hash: crypto.SHA256, Encoding: secec.EncodingCompact}
+
+func init() {
+	jwt.RegisterSigningMethod(domain.AlgorithmES256K, func() jwt.SigningMethod {
+		return signingMethodES256K{}
+	})
+}
+
+func (signingMethodES256K) Alg() string {
+	return domain.AlgorithmES256K
+}
+
+// Verify takes the public key of a child of an extended key, or a secp256k1
+// public key.
+func (signingMethodES256K) Verify(signingString, signature string, key interface{}) error {
+	var pubKey *secec.PublicKey
+	switch key := key.(type) {
+	case *derivedKey:
+		pubKey = key.PublicKey
+	case *secec.PublicKey:
+		pubKey = key
+	default:
+		return jwt.ErrInvalidKeyType
+	}
+
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(signingString))
+	if !pubKey.Verify(digest[:], sig, es256kOptions) {
+		return jwt.ErrECDSAVerification
+	}
+
+	return nil
+}
+
+func (signingMethodES256K) Sign(signingString string, key interface{}) (string, error) {
+	privateKey, ok := key.(*secec.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+
+	digest := sha256.Sum256([]byte(signingString))
+	sig, err := privateKey.Sign(rand.Reader, digest[:], es256kOptions)
+	if err != nil {
+		return "", err
+	}
+
+	return jwt.EncodeSegment(sig), nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/frost"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/tracing"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+)
+
+// keyTypeFrost is the key type of the results of threshold challenges.
+const keyTypeFrost = "FROST"
+
+func (cs *challengeService) CreateFrostChallenge(ctx context.Context, tenantID, groupKey string) (_ *domain.Challenge, err error) {
+	ctx, cancel := context.WithTimeout(ctx, cs.createTimeout)
+	defer cancel()
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "ChallengeService.CreateFrostChallenge")
+	defer func() { tracing.End(span, err) }()
+
+	tenant, err := cs.tenants.get(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	// the challenges are stored under the lower case hex of the key
+	groupKey = strings.ToLower(groupKey)
+	log := logging.FromContext(ctx, cs.log).WithFields(logrus.Fields{
+		logging.FieldTenant:         tenant.ID,
+		logging.FieldKeyFingerprint: logging.KeyFingerprint(groupKey),
+	})
+
+	key, err := parseGroupKey(ctx, tenant, groupKey)
+	if err != nil {
+		log.WithField(logging.FieldOutcome, outcomeInvalidKey).WithError(err).Info("threshold challenge not created")
+		return nil, err
+	}
+
+	challenge, err := cs.createChallenge(ctx, tenant, groupKey, log)
+	if err != nil {
+		return nil, err
+	}
+	challenge.Ciphersuite = key.Ciphersuite
+
+	return challenge, nil
+}
+
+// parseGroupKey checks that the tenant allows FROST group keys and that the
+// key decodes.
+func parseGroupKey(ctx context.Context, tenant domain.Tenant, groupKey string) (_ *frost.GroupKey, err error) {
+	if !contains(tenant.KeyFormats, domain.KeyFormatFrost) {
+		return nil, fmt.Errorf("%w: key format %s is not allowed", domain.ErrInvalidPublicKey, domain.KeyFormatFrost)
+	}
+
+	_, decodeSpan := otel.Tracer(tracerName).Start(ctx, "decodePublicKey")
+	key, err := frost.ParseGroupKey(groupKey)
+	tracing.End(decodeSpan, err)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidPublicKey, err)
+	}
+
+	return key, nil
+}
+
+// VerifyFrostSignature checks the signature before a single-use challenge is
+// consumed, as the signed message is rendered from the challenge.
+func (cs *challengeService) VerifyFrostSignature(ctx context.Context, tenantID, groupKey, nonce, signature string) (result *domain.ChallengeValidationResult, err error) {
+	ctx, cancel := context.WithTimeout(ctx, cs.verifyTimeout)
+	defer cancel()
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "ChallengeService.VerifyFrostSignature")
+	defer func() { tracing.End(span, err) }()
+
+	tenant, err := cs.tenants.get(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err == nil {
+			cs.addStats(ctx, tenant.ID, verificationStat(result))
+		}
+	}()
+	groupKey = strings.ToLower(groupKey)
+	log := logging.FromContext(ctx, cs.log).WithFields(logrus.Fields{
+		logging.FieldTenant:         tenant.ID,
+		logging.FieldKeyFingerprint: logging.KeyFingerprint(groupKey),
+		logging.FieldNonce:          nonce,
+	})
+	invalid := func(outcome, validationError string, err error) (*domain.ChallengeValidationResult, error) {
+		log.WithField(logging.FieldOutcome, outcome).WithError(err).Info("threshold signature verification failed")
+		return &domain.ChallengeValidationResult{ValidationError: validationError}, nil
+	}
+	failed := func(message string, err error) (*domain.ChallengeValidationResult, error) {
+		log.WithFields(logrus.Fields{
+			logging.FieldOutcome:   outcomeError,
+			logging.FieldErrorCode: domain.UnexpectedError,
+		}).WithError(err).Error(message)
+		return &domain.ChallengeValidationResult{Valid: false}, err
+	}
+
+	key, err := parseGroupKey(ctx, tenant, groupKey)
+	if err != nil {
+		return invalid(outcomeInvalidKey, err.Error(), err)
+	}
+	challenges, err := cs.repo.ChallengeRepo.GetChallenges(ctx, tenant.ID, groupKey, nonce)
+	if err != nil {
+		return failed("failed to get challenge from repo", err)
+	}
+	if len(challenges) == 0 {
+		return invalid(outcomeInvalidNonce, ValidationErrorInvalidNonce, nil)
+	}
+	challenge := challenges[0]
+	if challenge.ExpiresAt < cs.now().Unix() {
+		return invalid(outcomeExpiredNonce, ValidationErrorExpiredNonce, nil)
+	}
+	if challenge.Message, err = domain.RenderMessage(tenant, challenge); err != nil {
+		return failed("failed to render challenge message", err)
+	}
+
+	_, verifySpan := otel.Tracer(tracerName).Start(ctx, "verifySignature")
+	err = key.Verify(challenge.SignedMessage(), signature)
+	tracing.End(verifySpan, err)
+	if err != nil {
+		return invalid(outcomeInvalidToken, err.Error(), err)
+	}
+
+	// the signature is verified once, even by concurrent calls
+	if cs.singleUse {
+		consumed, err := cs.repo.ChallengeRepo.ConsumeChallenge(ctx, tenant.ID, groupKey, nonce)
+		if err != nil {
+			return failed("failed to get challenge from repo", err)
+		}
+		if consumed == nil {
+			return invalid(outcomeInvalidNonce, ValidationErrorInvalidNonce, nil)
+		}
+	}
+
+	log.WithField(logging.FieldOutcome, outcomeValid).Info("threshold signature verified")
+	return &domain.ChallengeValidationResult{
+		Valid:          true,
+		KeyFingerprint: logging.KeyFingerprint(groupKey),
+		KeyType:        keyTypeFrost,
+		Algorithm:      key.Ciphersuite,
+		CreatedAt:      challenge.CreatedAt,
+		ExpiresAt:      challenge.ExpiresAt,
+		VerifiedAt:     cs.now().Unix(),
+	}, nil
+}
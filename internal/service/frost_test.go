@@ -0,0 +1,134 @@
+package service_test
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/frost"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/service"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChallengeService_Frost(t *testing.T) {
+	timeNow := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	now := timeNow
+	tenancy := config.TenancyConfig{
+		Tenants: []config.TenantConfig{
+			{ID: "shop", Domain: "shop.example"},
+			{ID: "plain"},
+			{ID: "pem", KeyFormats: []string{domain.KeyFormatCompressedPEM}},
+		},
+	}
+	statsRepo := repository.NewChallengeStatsMemoryRepository()
+	repo := repository.NewRepository(repository.NoTx, repository.NewChallengeMemoryRepository(), nil, statsRepo, repository.NewMultisigMemoryRepository(), repository.NewExtendedKeyMemoryRepository())
+	challengeService := service.NewChallengeService(repo, func() time.Time { return now }, config.Default().Challenge, tenancy, service.NewNonceGenerators(), logrus.NewEntry(logrus.New()))
+	ctx := context.Background()
+
+	// a FROST(Ed25519, SHA-512) signature verifies as one of RFC 8032
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	groupKey := hex.EncodeToString(pub)
+	sign := func(challenge *domain.Challenge) string {
+		return hex.EncodeToString(ed25519.Sign(priv, challenge.SignedMessage()))
+	}
+
+	t.Run("group key is validated", func(t *testing.T) {
+		_, err := challengeService.CreateFrostChallenge(ctx, "plain", "01"+strings.Repeat("00", 31))
+		assert.ErrorIs(t, err, domain.ErrInvalidPublicKey)
+		_, err = challengeService.CreateFrostChallenge(ctx, "pem", groupKey)
+		assert.ErrorIs(t, err, domain.ErrInvalidPublicKey)
+	})
+
+	t.Run("signature of the message is verified", func(t *testing.T) {
+		challenge, err := challengeService.CreateFrostChallenge(ctx, "shop", strings.ToUpper(groupKey))
+		require.NoError(t, err)
+		assert.Equal(t, groupKey, challenge.PublicKey)
+		assert.Equal(t, frost.Ed25519, challenge.Ciphersuite)
+		assert.Contains(t, challenge.Message, challenge.Nonce)
+
+		result, err := challengeService.VerifyFrostSignature(ctx, "shop", groupKey, challenge.Nonce, sign(challenge))
+		require.NoError(t, err)
+		assert.Equal(t, &domain.ChallengeValidationResult{
+			Valid:          true,
+			KeyFingerprint: result.KeyFingerprint,
+			KeyType:        "FROST",
+			Algorithm:      frost.Ed25519,
+			CreatedAt:      timeNow.Unix(),
+			ExpiresAt:      challenge.ExpiresAt,
+			VerifiedAt:     timeNow.Unix(),
+		}, result)
+		assert.NotEmpty(t, result.KeyFingerprint)
+
+		// the signature of the nonce alone does not sign the message
+		result, err = challengeService.VerifyFrostSignature(ctx, "shop", groupKey, challenge.Nonce, hex.EncodeToString(ed25519.Sign(priv, []byte(challenge.Nonce))))
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.ValidationError, frost.ErrInvalidSignature.Error())
+	})
+
+	t.Run("nonce is signed without a message", func(t *testing.T) {
+		challenge, err := challengeService.CreateFrostChallenge(ctx, "plain", groupKey)
+		require.NoError(t, err)
+		assert.Empty(t, challenge.Message)
+
+		result, err := challengeService.VerifyFrostSignature(ctx, "plain", groupKey, challenge.Nonce, hex.EncodeToString(ed25519.Sign(priv, []byte(challenge.Nonce))))
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+
+		status, err := challengeService.GetChallengeStatus(ctx, "plain", groupKey, challenge.Nonce)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ChallengeStatusPending, status.Status)
+	})
+
+	t.Run("challenge must be of the group and not expired", func(t *testing.T) {
+		challenge, err := challengeService.CreateFrostChallenge(ctx, "plain", groupKey)
+		require.NoError(t, err)
+		otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		result, err := challengeService.VerifyFrostSignature(ctx, "plain", hex.EncodeToString(otherPub), challenge.Nonce, sign(challenge))
+		require.NoError(t, err)
+		assert.Equal(t, service.ValidationErrorInvalidNonce, result.ValidationError)
+
+		result, err = challengeService.VerifyFrostSignature(ctx, "pem", groupKey, challenge.Nonce, sign(challenge))
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.ValidationError, domain.ErrInvalidPublicKey.Error())
+
+		now = timeNow.Add(config.Default().Challenge.NonceTimeToLive + time.Second)
+		defer func() { now = timeNow }()
+		result, err = challengeService.VerifyFrostSignature(ctx, "plain", groupKey, challenge.Nonce, sign(challenge))
+		require.NoError(t, err)
+		assert.Equal(t, service.ValidationErrorExpiredNonce, result.ValidationError)
+	})
+
+	t.Run("single-use signature cannot be replayed", func(t *testing.T) {
+		cfg := config.Default().Challenge
+		cfg.SingleUse = true
+		challengeService := service.NewChallengeService(repo, func() time.Time { return now }, cfg, tenancy, service.NewNonceGenerators(), logrus.NewEntry(logrus.New()))
+		challenge, err := challengeService.CreateFrostChallenge(ctx, "plain", groupKey)
+		require.NoError(t, err)
+
+		// a wrong signature does not consume the challenge
+		result, err := challengeService.VerifyFrostSignature(ctx, "plain", groupKey, challenge.Nonce, strings.Repeat("00", 64))
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+
+		result, err = challengeService.VerifyFrostSignature(ctx, "plain", groupKey, challenge.Nonce, sign(challenge))
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		result, err = challengeService.VerifyFrostSignature(ctx, "plain", groupKey, challenge.Nonce, sign(challenge))
+		require.NoError(t, err)
+		assert.Equal(t, service.ValidationErrorInvalidNonce, result.ValidationError)
+	})
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/tracing"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+)
+
+// MaxMultisigKeys is the number of public keys a multi-signature challenge
+// has at most.
+const MaxMultisigKeys = 20
+
+func (cs *challengeService) CreateMultisigChallenge(ctx context.Context, tenantID string, pubKeys []string, threshold int) (_ *domain.Challenge, err error) {
+	ctx, cancel := context.WithTimeout(ctx, cs.createTimeout)
+	defer cancel()
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "ChallengeService.CreateMultisigChallenge")
+	defer func() { tracing.End(span, err) }()
+
+	tenant, err := cs.tenants.get(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	log := logging.FromContext(ctx, cs.log).WithField(logging.FieldTenant, tenant.ID)
+
+	if err := cs.validatePublicKeys(ctx, tenant, pubKeys); err != nil {
+		log.WithField(logging.FieldOutcome, outcomeInvalidKey).WithError(err).Info("multisig challenge not created")
+		return nil, err
+	}
+	if threshold < 1 || threshold > len(pubKeys) {
+		return nil, fmt.Errorf("%w: %d of %d public keys", domain.ErrInvalidThreshold, threshold, len(pubKeys))
+	}
+
+	nonce, err := cs.newNonce(tenant)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			logging.FieldOutcome:   outcomeError,
+			logging.FieldErrorCode: domain.UnexpectedError,
+		}).WithError(err).Error("failed to generate nonce")
+		return nil, err
+	}
+
+	now := cs.now()
+	challenge := &domain.Challenge{
+		TenantID:   tenant.ID,
+		Nonce:      nonce,
+		CreatedAt:  now.Unix(),
+		ExpiresAt:  now.Add(tenant.NonceTimeToLive).Unix(),
+		RequestID:  logging.RequestID(ctx),
+		PublicKeys: pubKeys,
+		Threshold:  threshold,
+	}
+	log = log.WithField(logging.FieldNonce, nonce)
+	if err := cs.repo.MultisigRepo.CreateMultisigChallenge(ctx, challenge); err != nil {
+		log.WithFields(logrus.Fields{
+			logging.FieldOutcome:   outcomeError,
+			logging.FieldErrorCode: domain.UnexpectedError,
+		}).WithError(err).Error("failed to store multisig challenge")
+		return nil, err
+	}
+	if challenge.Message, err = domain.RenderMessage(tenant, challenge); err != nil {
+		log.WithFields(logrus.Fields{
+			logging.FieldOutcome:   outcomeError,
+			logging.FieldErrorCode: domain.UnexpectedError,
+		}).WithError(err).Error("failed to render challenge message")
+		return nil, err
+	}
+	log.WithField(logging.FieldOutcome, outcomeCreated).Info("multisig challenge created")
+	cs.addStats(ctx, tenant.ID, domain.StatCreated)
+
+	return challenge, nil
+}
+
+// validatePublicKeys checks the public keys of a multi-signature challenge,
+// which must be distinct.
+func (cs *challengeService) validatePublicKeys(ctx context.Context, tenant domain.Tenant, pubKeys []string) error {
+	if len(pubKeys) == 0 || len(pubKeys) > MaxMultisigKeys {
+		return fmt.Errorf("%w: a multisig challenge has 1 to %d public keys", domain.ErrInvalidPublicKey, MaxMultisigKeys)
+	}
+	seen := map[string]bool{}
+	for i, pubKey := range pubKeys {
+		if seen[pubKey] {
+			return fmt.Errorf("%w: public key %d is repeated", domain.ErrInvalidPublicKey, i)
+		}
+		seen[pubKey] = true
+		if err := cs.validatePublicKey(ctx, tenant, pubKey); err != nil {
+			return fmt.Errorf("public key %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// SignMultisigChallenge checks every token as VerifyChallenge does, then adds
+// its signer to the challenge. The challenge counts as verified in the
+// statistics once, by the call that added its threshold-th signer.
+func (cs *challengeService) SignMultisigChallenge(ctx context.Context, tenantID, nonce string, tokens []string) (_ *domain.MultisigResult, err error) {
+	ctx, cancel := context.WithTimeout(ctx, cs.verifyTimeout)
+	defer cancel()
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "ChallengeService.SignMultisigChallenge")
+	defer func() { tracing.End(span, err) }()
+
+	tenant, err := cs.tenants.get(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	log := logging.FromContext(ctx, cs.log).WithFields(logrus.Fields{
+		logging.FieldTenant: tenant.ID,
+		logging.FieldNonce:  nonce,
+	})
+
+	challenge, err := cs.repo.MultisigRepo.GetMultisigChallenge(ctx, tenant.ID, nonce)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			logging.FieldOutcome:   outcomeError,
+			logging.FieldErrorCode: domain.UnexpectedError,
+		}).WithError(err).Error("failed to get multisig challenge from repo")
+		return nil, err
+	}
+	var message string
+	if challenge != nil {
+		if message, err = domain.RenderMessage(tenant, challenge); err != nil {
+			log.WithFields(logrus.Fields{
+				logging.FieldOutcome:   outcomeError,
+				logging.FieldErrorCode: domain.UnexpectedError,
+			}).WithError(err).Error("failed to render challenge message")
+			return nil, err
+		}
+	}
+
+	result := &domain.MultisigResult{Nonce: nonce, Signers: []string{}}
+	added, reachedThreshold := 0, false
+	for _, signedToken := range tokens {
+		signature, position, err := cs.addSignature(ctx, tenant, challenge, message, signedToken, log)
+		if err != nil {
+			return nil, err
+		}
+		if signature.Valid {
+			added++
+			reachedThreshold = reachedThreshold || position == challenge.Threshold
+		} else {
+			cs.addStats(ctx, tenant.ID, domain.StatFailed)
+		}
+		result.Signatures = append(result.Signatures, signature)
+	}
+	if challenge == nil {
+		return result, nil
+	}
+
+	// the challenge is read again for the signers added meanwhile by other calls
+	if added > 0 {
+		current, err := cs.repo.MultisigRepo.GetMultisigChallenge(ctx, tenant.ID, nonce)
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				logging.FieldOutcome:   outcomeError,
+				logging.FieldErrorCode: domain.UnexpectedError,
+			}).WithError(err).Error("failed to get multisig challenge from repo")
+			return nil, err
+		}
+		// a store with native expiry may have dropped it since
+		if current != nil {
+			challenge = current
+		}
+	}
+	result.Verified = challenge.Verified()
+	result.Threshold = challenge.Threshold
+	result.ExpiresAt = challenge.ExpiresAt
+	result.Signers = append(result.Signers, challenge.Signers...)
+	if reachedThreshold {
+		log.WithField(logging.FieldOutcome, outcomeValid).Info("multisig challenge verified")
+		cs.addStats(ctx, tenant.ID, domain.StatVerified)
+	}
+
+	return result, nil
+}
+
+// addSignature adds the signer of a token to the challenge, nil when there is
+// none of the nonce, and returns the result of the token with the position of
+// its signer, 0 when none was added.
+func (cs *challengeService) addSignature(ctx context.Context, tenant domain.Tenant, challenge *domain.Challenge, message, signedToken string, log *logrus.Entry) (*domain.ChallengeValidationResult, int, error) {
+	token, claims, pubKey, validationError, err := cs.parseToken(ctx, tenant, signedToken)
+	if validationError != "" {
+		log.WithField(logging.FieldOutcome, outcomeInvalidToken).WithError(err).Info("multisig signature rejected")
+		return &domain.ChallengeValidationResult{ValidationError: validationError}, 0, nil
+	}
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			logging.FieldOutcome:   outcomeError,
+			logging.FieldErrorCode: domain.UnexpectedError,
+		}).WithError(err).Error("failed to get public key of token")
+		return nil, 0, err
+	}
+
+	compressedPubKey := (token.Header[publicKeyHeader]).(string)
+	log = log.WithField(logging.FieldKeyFingerprint, logging.KeyFingerprint(compressedPubKey))
+	invalid := func(outcome, validationError string) (*domain.ChallengeValidationResult, int, error) {
+		log.WithField(logging.FieldOutcome, outcome).Info("multisig signature rejected")
+		return &domain.ChallengeValidationResult{ValidationError: validationError}, 0, nil
+	}
+	switch {
+	case challenge == nil || claims.Id != challenge.Nonce:
+		return invalid(outcomeInvalidNonce, ValidationErrorInvalidNonce)
+	case challenge.ExpiresAt < cs.now().Unix():
+		return invalid(outcomeExpiredNonce, ValidationErrorExpiredNonce)
+	case !contains(challenge.PublicKeys, compressedPubKey):
+		return invalid(outcomeUnknownKey, ValidationErrorUnknownSigner)
+	case claims.Message != message:
+		return invalid(outcomeInvalidMsg, ValidationErrorInvalidMessage)
+	}
+
+	result, err := cs.validResult(signedToken, token, pubKey, challenge)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			logging.FieldOutcome:   outcomeError,
+			logging.FieldErrorCode: domain.UnexpectedError,
+		}).WithError(err).Error("failed to decode token claims")
+		return nil, 0, err
+	}
+	position, err := cs.repo.MultisigRepo.AddSigner(ctx, tenant.ID, challenge.Nonce, compressedPubKey, result.VerifiedAt)
+	if errors.Is(err, repository.ErrDuplicateSigner) {
+		return invalid(outcomeDuplicateKey, ValidationErrorDuplicateSigner)
+	}
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			logging.FieldOutcome:   outcomeError,
+			logging.FieldErrorCode: domain.UnexpectedError,
+		}).WithError(err).Error("failed to add multisig signer")
+		return nil, 0, err
+	}
+	// the challenge expired since it was read, a store with native expiry dropped it
+	if position == 0 {
+		return invalid(outcomeExpiredNonce, ValidationErrorExpiredNonce)
+	}
+	log.WithField(logging.FieldOutcome, outcomeValid).Info("multisig signature added")
+
+	return result, position, nil
+}
+
+// multisigStatus is the status of the multi-signature challenge of a nonce,
+// reported to any of its public keys.
+func (cs *challengeService) multisigStatus(ctx context.Context, tenant domain.Tenant, status *domain.ChallengeStatus) (*domain.ChallengeStatus, error) {
+	challenge, err := cs.repo.MultisigRepo.GetMultisigChallenge(ctx, tenant.ID, status.Nonce)
+	if err != nil {
+		logging.FromContext(ctx, cs.log).WithFields(logrus.Fields{
+			logging.FieldTenant:         tenant.ID,
+			logging.FieldKeyFingerprint: logging.KeyFingerprint(status.PublicKey),
+			logging.FieldNonce:          status.Nonce,
+			logging.FieldErrorCode:      domain.UnexpectedError,
+		}).WithError(err).Error("failed to get multisig challenge from repo")
+		return nil, err
+	}
+	if challenge == nil || !contains(challenge.PublicKeys, status.PublicKey) {
+		return status, nil
+	}
+
+	status.ExpiresAt = challenge.ExpiresAt
+	status.Threshold = challenge.Threshold
+	status.Signers = append([]string{}, challenge.Signers...)
+	switch {
+	case challenge.Verified():
+		status.Status = domain.ChallengeStatusVerified
+	case challenge.ExpiresAt < cs.now().Unix():
+		status.Status = domain.ChallengeStatusExpired
+	default:
+		status.Status = domain.ChallengeStatusPending
+	}
+
+	return status, nil
+}
@@ -0,0 +1,224 @@
+package service_test
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/repository/mock_repository"
+	"crypto-project-1/internal/service"
+	"crypto-project-1/public/client"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChallengeService_Multisig(t *testing.T) {
+	// the tokens expire with their challenge, which jwt checks against the clock
+	timeNow := time.Now()
+	now := timeNow
+	statsRepo := repository.NewChallengeStatsMemoryRepository()
+	repo := repository.NewRepository(repository.NoTx, repository.NewChallengeMemoryRepository(), nil, statsRepo, repository.NewMultisigMemoryRepository(), repository.NewExtendedKeyMemoryRepository())
+	challengeService := service.NewChallengeService(repo, func() time.Time { return now }, config.Default().Challenge, config.Default().Tenancy, service.NewNonceGenerators(), logrus.NewEntry(logrus.New()))
+	ctx := context.Background()
+
+	keys := make([]*ecdsa.PrivateKey, 4)
+	pubKeys := make([]string, 4)
+	for i := range keys {
+		var err error
+		keys[i], err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		pubKeys[i], err = client.CompressPublicKey(keys[i].Public())
+		require.NoError(t, err)
+	}
+	// the last key is not one of the challenges
+	signers := pubKeys[:3]
+	sign := func(t *testing.T, key *ecdsa.PrivateKey, challenge *domain.Challenge) string {
+		token, err := client.SignChallenge(key, &client.Challenge{Nonce: challenge.Nonce, ExpiresAt: challenge.ExpiresAt}, "wheltee", now)
+		require.NoError(t, err)
+		return token
+	}
+
+	t.Run("keys and threshold are validated", func(t *testing.T) {
+		_, err := challengeService.CreateMultisigChallenge(ctx, "", signers, 0)
+		assert.ErrorIs(t, err, domain.ErrInvalidThreshold)
+		_, err = challengeService.CreateMultisigChallenge(ctx, "", signers, 4)
+		assert.ErrorIs(t, err, domain.ErrInvalidThreshold)
+		_, err = challengeService.CreateMultisigChallenge(ctx, "", []string{pubKeys[0], pubKeys[0]}, 1)
+		assert.ErrorIs(t, err, domain.ErrInvalidPublicKey)
+		_, err = challengeService.CreateMultisigChallenge(ctx, "", []string{pubKeys[0], "invalid"}, 1)
+		assert.ErrorIs(t, err, domain.ErrInvalidPublicKey)
+	})
+
+	t.Run("challenge is verified once its threshold of keys signed it", func(t *testing.T) {
+		challenge, err := challengeService.CreateMultisigChallenge(ctx, "", signers, 2)
+		require.NoError(t, err)
+		assert.Empty(t, challenge.PublicKey)
+		assert.Equal(t, signers, challenge.PublicKeys)
+		assert.Equal(t, 2, challenge.Threshold)
+
+		result, err := challengeService.SignMultisigChallenge(ctx, "", challenge.Nonce, []string{sign(t, keys[0], challenge)})
+		require.NoError(t, err)
+		assert.False(t, result.Verified)
+		assert.Equal(t, []string{pubKeys[0]}, result.Signers)
+		require.Len(t, result.Signatures, 1)
+		assert.True(t, result.Signatures[0].Valid)
+
+		status, err := challengeService.GetChallengeStatus(ctx, "", pubKeys[1], challenge.Nonce)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.ChallengeStatus{
+			PublicKey: pubKeys[1],
+			Nonce:     challenge.Nonce,
+			Status:    domain.ChallengeStatusPending,
+			ExpiresAt: challenge.ExpiresAt,
+			Threshold: 2,
+			Signers:   []string{pubKeys[0]},
+		}, status)
+
+		// the repeated signer is rejected, the other one is added
+		result, err = challengeService.SignMultisigChallenge(ctx, "", challenge.Nonce, []string{sign(t, keys[0], challenge), sign(t, keys[2], challenge)})
+		require.NoError(t, err)
+		assert.True(t, result.Verified)
+		assert.Equal(t, []string{pubKeys[0], pubKeys[2]}, result.Signers)
+		require.Len(t, result.Signatures, 2)
+		assert.Equal(t, service.ValidationErrorDuplicateSigner, result.Signatures[0].ValidationError)
+		assert.True(t, result.Signatures[1].Valid)
+
+		status, err = challengeService.GetChallengeStatus(ctx, "", pubKeys[0], challenge.Nonce)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ChallengeStatusVerified, status.Status)
+
+		// the challenge counts as verified once
+		result, err = challengeService.SignMultisigChallenge(ctx, "", challenge.Nonce, []string{sign(t, keys[1], challenge)})
+		require.NoError(t, err)
+		assert.True(t, result.Verified)
+		stats, err := statsRepo.GetStats(ctx, "default", now.Truncate(time.Hour).Unix(), now.Unix())
+		require.NoError(t, err)
+		require.Len(t, stats, 1)
+		assert.Equal(t, int64(1), stats[0].Verified)
+	})
+
+	t.Run("tokens of other keys and nonces are rejected", func(t *testing.T) {
+		challenge, err := challengeService.CreateMultisigChallenge(ctx, "", signers, 1)
+		require.NoError(t, err)
+		other, err := challengeService.CreateMultisigChallenge(ctx, "", signers, 1)
+		require.NoError(t, err)
+
+		result, err := challengeService.SignMultisigChallenge(ctx, "", challenge.Nonce, []string{
+			sign(t, keys[3], challenge),
+			sign(t, keys[0], other),
+			"invalid",
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Verified)
+		assert.Empty(t, result.Signers)
+		require.Len(t, result.Signatures, 3)
+		assert.Equal(t, service.ValidationErrorUnknownSigner, result.Signatures[0].ValidationError)
+		assert.Equal(t, service.ValidationErrorInvalidNonce, result.Signatures[1].ValidationError)
+		assert.NotEmpty(t, result.Signatures[2].ValidationError)
+
+		// the status is reported to the keys of the challenge only
+		status, err := challengeService.GetChallengeStatus(ctx, "", pubKeys[3], challenge.Nonce)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ChallengeStatusNotFound, status.Status)
+
+		result, err = challengeService.SignMultisigChallenge(ctx, "", "unknown", []string{sign(t, keys[0], challenge)})
+		require.NoError(t, err)
+		assert.Equal(t, service.ValidationErrorInvalidNonce, result.Signatures[0].ValidationError)
+	})
+
+	t.Run("expired challenge is not signed", func(t *testing.T) {
+		challenge, err := challengeService.CreateMultisigChallenge(ctx, "", signers, 1)
+		require.NoError(t, err)
+		token := sign(t, keys[0], challenge)
+		now = timeNow.Add(config.Default().Challenge.NonceTimeToLive + time.Second)
+		defer func() { now = timeNow }()
+
+		result, err := challengeService.SignMultisigChallenge(ctx, "", challenge.Nonce, []string{token})
+		require.NoError(t, err)
+		assert.False(t, result.Verified)
+		assert.Equal(t, service.ValidationErrorExpiredNonce, result.Signatures[0].ValidationError)
+
+		status, err := challengeService.GetChallengeStatus(ctx, "", pubKeys[0], challenge.Nonce)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ChallengeStatusExpired, status.Status)
+	})
+
+	t.Run("concurrent signers count the challenge as verified once", func(t *testing.T) {
+		verified := func() int64 {
+			stats, err := statsRepo.GetStats(ctx, "default", now.Truncate(time.Hour).Unix(), now.Unix())
+			require.NoError(t, err)
+			require.Len(t, stats, 1)
+			return stats[0].Verified
+		}
+		before := verified()
+		challenge, err := challengeService.CreateMultisigChallenge(ctx, "", signers, 2)
+		require.NoError(t, err)
+
+		// the signers sign within the same second
+		var wg sync.WaitGroup
+		for _, key := range keys[:3] {
+			token := sign(t, key, challenge)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := challengeService.SignMultisigChallenge(ctx, "", challenge.Nonce, []string{token})
+				assert.NoError(t, err)
+				assert.True(t, result.Signatures[0].Valid)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, before+1, verified())
+	})
+}
+
+func TestChallengeService_SignMultisigChallenge_ExpiredMeanwhile(t *testing.T) {
+	now := time.Now()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	multisigRepo := mock_repository.NewMockMultisigRepository(ctrl)
+	statsRepo := repository.NewChallengeStatsMemoryRepository()
+	repo := repository.NewRepository(repository.NoTx, repository.NewChallengeMemoryRepository(), nil, statsRepo, multisigRepo, repository.NewExtendedKeyMemoryRepository())
+	challengeService := service.NewChallengeService(repo, func() time.Time { return now }, config.Default().Challenge, config.Default().Tenancy, service.NewNonceGenerators(), logrus.NewEntry(logrus.New()))
+	ctx := context.Background()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	pubKey, err := client.CompressPublicKey(key.Public())
+	require.NoError(t, err)
+	challenge := &domain.Challenge{
+		TenantID:   "default",
+		Nonce:      "nonce",
+		CreatedAt:  now.Unix(),
+		ExpiresAt:  now.Add(time.Minute).Unix(),
+		PublicKeys: []string{pubKey},
+		Threshold:  1,
+	}
+	token, err := client.SignChallenge(key, &client.Challenge{Nonce: challenge.Nonce, ExpiresAt: challenge.ExpiresAt}, "wheltee", now)
+	require.NoError(t, err)
+
+	// the store dropped the challenge between the read and the signer
+	multisigRepo.EXPECT().GetMultisigChallenge(gomock.Any(), "default", "nonce").Return(challenge, nil)
+	multisigRepo.EXPECT().AddSigner(gomock.Any(), "default", "nonce", pubKey, gomock.Any()).Return(0, nil)
+
+	result, err := challengeService.SignMultisigChallenge(ctx, "", "nonce", []string{token})
+	require.NoError(t, err)
+	assert.False(t, result.Verified)
+	assert.Empty(t, result.Signers)
+	require.Len(t, result.Signatures, 1)
+	assert.False(t, result.Signatures[0].Valid)
+	assert.Equal(t, service.ValidationErrorExpiredNonce, result.Signatures[0].ValidationError)
+	stats, err := statsRepo.GetStats(ctx, "default", now.Truncate(time.Hour).Unix(), now.Unix())
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Zero(t, stats[0].Verified)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"crypto-project-1/internal/domain"
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// alphanumericNonceLength gives the alphanumeric nonces about 190 bits of
+	// randomness, EIP-4361 requires at least 8 characters
+	alphanumericNonceLength = 32
+	alphanumerics           = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+// NonceGenerator returns the nonces of the new challenges in a format.
+type NonceGenerator interface {
+	NewNonce() (string, error)
+}
+
+// NonceGenerators are the generators of the nonce formats, by format; a
+// tenant creates its challenges with the generator of its nonce format.
+type NonceGenerators map[string]NonceGenerator
+
+// NewNonceGenerators returns a generator of every format of domain.NonceFormats,
+// from a cryptographically secure source.
+func NewNonceGenerators() NonceGenerators {
+	return NonceGenerators{
+		domain.NonceFormatUUIDv4:       uuidV4Generator{},
+		domain.NonceFormatUUIDv7:       uuidV7Generator{},
+		domain.NonceFormatBase64URL128: base64URLGenerator{size: 16},
+		domain.NonceFormatBase64URL256: base64URLGenerator{size: 32},
+		domain.NonceFormatAlphanumeric: alphanumericGenerator{length: alphanumericNonceLength},
+	}
+}
+
+type uuidV4Generator struct{}
+
+func (uuidV4Generator) NewNonce() (string, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+type uuidV7Generator struct{}
+
+func (uuidV7Generator) NewNonce() (string, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+// base64URLGenerator encodes size random bytes in base64url, without padding.
+type base64URLGenerator struct {
+	size int
+}
+
+func (g base64URLGenerator) NewNonce() (string, error) {
+	random := make([]byte, g.size)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(random), nil
+}
+
+// alphanumericGenerator draws length letters and digits uniformly.
+type alphanumericGenerator struct {
+	length int
+}
+
+func (g alphanumericGenerator) NewNonce() (string, error) {
+	// the bytes from the largest multiple of the alphabet size are rejected,
+	// so that every character is as likely
+	const limit = 256 - 256%len(alphanumerics)
+	nonce := make([]byte, 0, g.length)
+	random := make([]byte, g.length)
+	for len(nonce) < g.length {
+		if _, err := rand.Read(random); err != nil {
+			return "", err
+		}
+		for _, b := range random {
+			if int(b) < limit && len(nonce) < g.length {
+				nonce = append(nonce, alphanumerics[int(b)%len(alphanumerics)])
+			}
+		}
+	}
+
+	return string(nonce), nil
+}
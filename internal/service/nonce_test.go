@@ -0,0 +1,73 @@
+package service_test
+
+import (
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/service"
+	"regexp"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNonceGenerators(t *testing.T) {
+	tests := []struct {
+		format string
+		check  func(t *testing.T, nonce string)
+	}{
+		{
+			format: domain.NonceFormatUUIDv4,
+			check:  uuidVersion(4),
+		},
+		{
+			format: domain.NonceFormatUUIDv7,
+			check:  uuidVersion(7),
+		},
+		{
+			format: domain.NonceFormatBase64URL128,
+			check:  matches("^[A-Za-z0-9_-]{22}$"),
+		},
+		{
+			format: domain.NonceFormatBase64URL256,
+			check:  matches("^[A-Za-z0-9_-]{43}$"),
+		},
+		{
+			format: domain.NonceFormatAlphanumeric,
+			check:  matches("^[A-Za-z0-9]{32}$"),
+		},
+	}
+
+	generators := service.NewNonceGenerators()
+	assert.Len(t, generators, len(domain.NonceFormats))
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			generator, ok := generators[test.format]
+			require.True(t, ok)
+
+			nonces := map[string]bool{}
+			for i := 0; i < 100; i++ {
+				nonce, err := generator.NewNonce()
+				require.NoError(t, err)
+				test.check(t, nonce)
+				assert.False(t, nonces[nonce], "nonce %s is repeated", nonce)
+				nonces[nonce] = true
+			}
+		})
+	}
+}
+
+func uuidVersion(version uuid.Version) func(t *testing.T, nonce string) {
+	return func(t *testing.T, nonce string) {
+		id, err := uuid.Parse(nonce)
+		require.NoError(t, err)
+		assert.Equal(t, version, id.Version())
+	}
+}
+
+func matches(pattern string) func(t *testing.T, nonce string) {
+	expr := regexp.MustCompile(pattern)
+	return func(t *testing.T, nonce string) {
+		assert.Regexp(t, expr, nonce)
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"text/template"
+	"time"
+)
+
+const (
+	ValidationErrorAlgorithmNotAllowed = "algorithm not allowed"
+	ValidationErrorAudienceNotAllowed  = "audience not allowed"
+)
+
+// tenants resolves the tenant of a request.
+type tenants struct {
+	byID          map[string]domain.Tenant
+	defaultTenant string
+}
+
+// newTenants builds the tenants of the configuration, whose challenges follow
+// the challenge settings unless they set their own. Without tenants there is a
+// single one, named after the default tenant, that allows everything the API
+// supports.
+func newTenants(tenancy config.TenancyConfig, challenge config.ChallengeConfig) *tenants {
+	configured := tenancy.Tenants
+	if len(configured) == 0 && tenancy.DefaultTenant != "" {
+		configured = []config.TenantConfig{{ID: tenancy.DefaultTenant}}
+	}
+
+	byID := map[string]domain.Tenant{}
+	for _, tenant := range configured {
+		t := domain.Tenant{
+			ID:              tenant.ID,
+			Audiences:       tenant.Audiences,
+			NonceTimeToLive: orDuration(tenant.NonceTimeToLive, challenge.NonceTimeToLive),
+			Algorithms:      orStrings(tenant.Algorithms, domain.Algorithms),
+			KeyFormats:      orStrings(tenant.KeyFormats, domain.KeyFormats),
+			NonceFormat:     orString(tenant.NonceFormat, challenge.NonceFormat),
+			Domain:          orString(tenant.Domain, challenge.Domain),
+			URI:             orString(tenant.URI, challenge.URI),
+			Statement:       orString(tenant.Statement, challenge.Statement),
+		}
+		if t.Domain != "" {
+			t.MessageTemplate = messageTemplate(orString(tenant.MessageTemplate, challenge.MessageTemplate))
+		}
+		byID[tenant.ID] = t
+	}
+
+	return &tenants{
+		byID:          byID,
+		defaultTenant: tenancy.DefaultTenant,
+	}
+}
+
+// messageTemplate parses a message template checked by the configuration, the
+// default one when empty.
+func messageTemplate(text string) *template.Template {
+	return template.Must(domain.ParseMessageTemplate(orString(text, domain.DefaultMessageTemplate)))
+}
+
+// get returns the tenant of an ID, the default tenant when the ID is empty.
+func (t *tenants) get(tenantID string) (domain.Tenant, error) {
+	if tenantID == "" {
+		if t.defaultTenant == "" {
+			return domain.Tenant{}, domain.ErrTenantRequired
+		}
+		tenantID = t.defaultTenant
+	}
+	tenant, found := t.byID[tenantID]
+	if !found {
+		return domain.Tenant{}, domain.ErrUnknownTenant
+	}
+
+	return tenant, nil
+}
+
+// allowsAudience reports whether a token for audience is accepted by the tenant.
+func allowsAudience(tenant domain.Tenant, audience string) bool {
+	return len(tenant.Audiences) == 0 || contains(tenant.Audiences, audience)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func orDuration(value, fallback time.Duration) time.Duration {
+	if value == 0 {
+		return fallback
+	}
+
+	return value
+}
+
+func orString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
+
+func orStrings(values, fallback []string) []string {
+	if len(values) == 0 {
+		return fallback
+	}
+
+	return values
+}
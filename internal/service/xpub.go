@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"crypto-project-1/internal/bip32"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"crypto-project-1/internal/tracing"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/yawning/secp256k1-voi/secec"
+	"go.opentelemetry.io/otel"
+)
+
+// derivationIndexHeader is the header of a token signed by a child of an
+// extended key, the index that replaces the wildcard of its path pattern.
+const derivationIndexHeader = "idx"
+
+// errExtendedKeyLookup marks the failure to read an enrollment, which fails
+// the call rather than the token.
+var errExtendedKeyLookup = errors.New("failed to get extended key")
+
+// enrollment is an enrolled extended public key, decoded.
+type enrollment struct {
+	key      *bip32.ExtendedKey
+	pattern  *bip32.PathPattern
+	maxIndex uint32
+}
+
+// derivedKey is the public key of a child of an extended key, at path
+// relative to it.
+type derivedKey struct {
+	*secec.PublicKey
+	path string
+}
+
+func (cs *challengeService) EnrollExtendedKey(ctx context.Context, tenantID, xpub, pathPattern string, maxIndex uint32) (_ *domain.ExtendedKey, err error) {
+	ctx, cancel := context.WithTimeout(ctx, cs.createTimeout)
+	defer cancel()
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "ChallengeService.EnrollExtendedKey")
+	defer func() { tracing.End(span, err) }()
+
+	tenant, err := cs.tenants.get(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	log := logging.FromContext(ctx, cs.log).WithFields(logrus.Fields{
+		logging.FieldTenant:         tenant.ID,
+		logging.FieldKeyFingerprint: logging.KeyFingerprint(xpub),
+	})
+
+	if err := validateEnrollment(tenant, xpub, pathPattern, maxIndex); err != nil {
+		log.WithField(logging.FieldOutcome, outcomeInvalidKey).WithError(err).Info("extended key not enrolled")
+		return nil, err
+	}
+
+	key := &domain.ExtendedKey{
+		TenantID:    tenant.ID,
+		Xpub:        xpub,
+		PathPattern: pathPattern,
+		MaxIndex:    maxIndex,
+		CreatedAt:   cs.now().Unix(),
+	}
+	if err := cs.repo.ExtendedKeyRepo.EnrollExtendedKey(ctx, key); err != nil {
+		log.WithFields(logrus.Fields{
+			logging.FieldOutcome:   outcomeError,
+			logging.FieldErrorCode: domain.UnexpectedError,
+		}).WithError(err).Error("failed to store extended key")
+		return nil, err
+	}
+	log.WithField(logging.FieldOutcome, outcomeEnrolled).Info("extended key enrolled")
+
+	return key, nil
+}
+
+// validateEnrollment checks that the tenant allows extended keys, that the key
+// decodes and that its children can be derived up to maxIndex.
+func validateEnrollment(tenant domain.Tenant, xpub, pathPattern string, maxIndex uint32) error {
+	if !contains(tenant.KeyFormats, domain.KeyFormatBIP32) {
+		return fmt.Errorf("%w: key format %s is not allowed", domain.ErrInvalidPublicKey, domain.KeyFormatBIP32)
+	}
+	if !bip32.IsExtendedKey(xpub) {
+		return fmt.Errorf("%w: not an xpub or a tpub", domain.ErrInvalidPublicKey)
+	}
+	if _, err := bip32.ParseExtendedKey(xpub); err != nil {
+		return fmt.Errorf("%w: %s", domain.ErrInvalidPublicKey, err)
+	}
+	if _, err := bip32.ParsePathPattern(pathPattern); err != nil {
+		return fmt.Errorf("%w: %s", domain.ErrInvalidDerivationPath, err)
+	}
+	if maxIndex >= bip32.HardenedOffset {
+		return fmt.Errorf("%w: max index %d is hardened", domain.ErrInvalidDerivationPath, maxIndex)
+	}
+
+	return nil
+}
+
+// enrolledKey returns the enrollment of an extended public key of the tenant,
+// a key that is not enrolled is an invalid public key.
+func (cs *challengeService) enrolledKey(ctx context.Context, tenant domain.Tenant, xpub string) (*enrollment, error) {
+	if !contains(tenant.KeyFormats, domain.KeyFormatBIP32) {
+		return nil, fmt.Errorf("%w: key format %s is not allowed", domain.ErrInvalidPublicKey, domain.KeyFormatBIP32)
+	}
+	enrolled, err := cs.repo.ExtendedKeyRepo.GetExtendedKey(ctx, tenant.ID, xpub)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errExtendedKeyLookup, err)
+	}
+	if enrolled == nil {
+		return nil, fmt.Errorf("%w: extended key is not enrolled", domain.ErrInvalidPublicKey)
+	}
+
+	key, err := bip32.ParseExtendedKey(enrolled.Xpub)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := bip32.ParsePathPattern(enrolled.PathPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return &enrollment{key: key, pattern: pattern, maxIndex: enrolled.MaxIndex}, nil
+}
+
+// tokenDerivedKey derives the public key of the child that signed a token,
+// from the extended key of its kid header and the index of its idx header.
+func (cs *challengeService) tokenDerivedKey(ctx context.Context, tenant domain.Tenant, token *jwt.Token, xpub string) (*derivedKey, error) {
+	header, found := token.Header[derivationIndexHeader]
+	if !found {
+		return nil, errors.New("derivation index header not found")
+	}
+	// JSON numbers decode as float64
+	index, ok := header.(float64)
+	if !ok || index < 0 || index != math.Trunc(index) || index >= bip32.HardenedOffset {
+		return nil, errors.New("derivation index header is not a non-hardened index")
+	}
+
+	enrolled, err := cs.enrolledKey(ctx, tenant, xpub)
+	if err != nil {
+		return nil, err
+	}
+	if uint32(index) > enrolled.maxIndex {
+		return nil, fmt.Errorf("derivation index %d is above the enrolled maximum %d", uint32(index), enrolled.maxIndex)
+	}
+	child, err := enrolled.pattern.Derive(enrolled.key, uint32(index))
+	if err != nil {
+		return nil, err
+	}
+
+	return &derivedKey{PublicKey: child.PublicKey, path: enrolled.pattern.Path(uint32(index))}, nil
+}
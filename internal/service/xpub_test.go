@@ -0,0 +1,128 @@
+package service_test
+
+import (
+	"context"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/service"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/yawning/secp256k1-voi/secec"
+)
+
+// extended key m/0'/1/2' of test vector 1 of BIP-32, and the private key of its
+// child at 2/1000000000
+const (
+	testXpub         = "xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5"
+	testChildIndex   = 1000000000
+	testChildPrivate = "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"
+	testMaxIndex     = testChildIndex + 10
+	testPathPattern  = "2/*"
+)
+
+func TestChallengeService_ExtendedKey(t *testing.T) {
+	// the tokens expire with their challenge, which jwt checks against the clock
+	timeNow := time.Now()
+	tenancy := config.TenancyConfig{
+		Tenants: []config.TenantConfig{
+			{ID: "wallet"},
+			{ID: "shop"},
+			{ID: "pem", KeyFormats: []string{domain.KeyFormatCompressedPEM}},
+		},
+	}
+	repo := repository.NewRepository(repository.NoTx, repository.NewChallengeMemoryRepository(), nil, repository.NewChallengeStatsMemoryRepository(), repository.NewMultisigMemoryRepository(), repository.NewExtendedKeyMemoryRepository())
+	challengeService := service.NewChallengeService(repo, func() time.Time { return timeNow }, config.Default().Challenge, tenancy, service.NewNonceGenerators(), logrus.NewEntry(logrus.New()))
+	ctx := context.Background()
+
+	privateKeyBytes, err := hex.DecodeString(testChildPrivate)
+	require.NoError(t, err)
+	childKey, err := secec.NewPrivateKey(privateKeyBytes)
+	require.NoError(t, err)
+	sign := func(t *testing.T, challenge *domain.Challenge, index interface{}) string {
+		token := jwt.NewWithClaims(jwt.GetSigningMethod(domain.AlgorithmES256K), jwt.StandardClaims{
+			Id:        challenge.Nonce,
+			Audience:  "wheltee",
+			IssuedAt:  timeNow.Unix(),
+			NotBefore: timeNow.Unix(),
+			ExpiresAt: challenge.ExpiresAt,
+		})
+		token.Header["kid"] = testXpub
+		if index != nil {
+			token.Header["idx"] = index
+		}
+		signedToken, err := token.SignedString(childKey)
+		require.NoError(t, err)
+		return signedToken
+	}
+
+	t.Run("enrollment is validated", func(t *testing.T) {
+		_, err := challengeService.EnrollExtendedKey(ctx, "wallet", "xpub0OIl", testPathPattern, testMaxIndex)
+		assert.ErrorIs(t, err, domain.ErrInvalidPublicKey)
+		_, err = challengeService.EnrollExtendedKey(ctx, "pem", testXpub, testPathPattern, testMaxIndex)
+		assert.ErrorIs(t, err, domain.ErrInvalidPublicKey)
+		_, err = challengeService.EnrollExtendedKey(ctx, "wallet", testXpub, "0'/*", testMaxIndex)
+		assert.ErrorIs(t, err, domain.ErrInvalidDerivationPath)
+		_, err = challengeService.EnrollExtendedKey(ctx, "wallet", testXpub, testPathPattern, 1<<31)
+		assert.ErrorIs(t, err, domain.ErrInvalidDerivationPath)
+	})
+
+	t.Run("challenge of a key that is not enrolled is not created", func(t *testing.T) {
+		_, err := challengeService.CreateChallenge(ctx, "wallet", testXpub)
+		assert.ErrorIs(t, err, domain.ErrInvalidPublicKey)
+	})
+
+	key, err := challengeService.EnrollExtendedKey(ctx, "wallet", testXpub, testPathPattern, testMaxIndex)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.ExtendedKey{
+		TenantID:    "wallet",
+		Xpub:        testXpub,
+		PathPattern: testPathPattern,
+		MaxIndex:    testMaxIndex,
+		CreatedAt:   timeNow.Unix(),
+	}, key)
+
+	t.Run("token signed by a derived child is verified", func(t *testing.T) {
+		challenge, err := challengeService.CreateChallenge(ctx, "wallet", testXpub)
+		require.NoError(t, err)
+
+		result, err := challengeService.VerifyChallenge(ctx, "wallet", sign(t, challenge, testChildIndex))
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.ValidationError)
+		assert.Equal(t, "2/1000000000", result.DerivationPath)
+		assert.Equal(t, "EC secp256k1", result.KeyType)
+		assert.Equal(t, domain.AlgorithmES256K, result.Algorithm)
+	})
+
+	t.Run("token is rejected", func(t *testing.T) {
+		tests := map[string]interface{}{
+			"without a derivation index": nil,
+			"signed by another child":    testChildIndex - 1,
+			"above the maximum index":    testMaxIndex + 1,
+			"with a hardened index":      1 << 31,
+			"with a fractional index":    1.5,
+		}
+		for name, index := range tests {
+			t.Run(name, func(t *testing.T) {
+				challenge, err := challengeService.CreateChallenge(ctx, "wallet", testXpub)
+				require.NoError(t, err)
+
+				result, err := challengeService.VerifyChallenge(ctx, "wallet", sign(t, challenge, index))
+				require.NoError(t, err)
+				assert.False(t, result.Valid)
+				assert.NotEmpty(t, result.ValidationError)
+			})
+		}
+	})
+
+	t.Run("key of another tenant is not enrolled", func(t *testing.T) {
+		_, err := challengeService.CreateChallenge(ctx, "shop", testXpub)
+		assert.ErrorIs(t, err, domain.ErrInvalidPublicKey)
+	})
+}
@@ -0,0 +1,59 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "crypto-project-1/internal/tracing"
+
+// Middleware starts a server span for every HTTP request, continuing the trace
+// of the W3C traceparent header when the caller sent one. The span is named
+// after the route pattern.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			request := ctx.Request()
+			parent := otel.GetTextMapPropagator().Extract(request.Context(), propagation.HeaderCarrier(request.Header))
+
+			spanCtx, span := otel.Tracer(tracerName).Start(parent, request.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", request.Method),
+					attribute.String("url.path", request.URL.Path),
+				))
+			defer span.End()
+			ctx.SetRequest(request.WithContext(spanCtx))
+
+			err := next(ctx)
+			if err != nil {
+				span.RecordError(err)
+				// let echo write the error response so that its status is recorded
+				ctx.Error(err)
+			}
+
+			// unmatched requests keep their raw path, which must not name the span
+			route := ctx.Path()
+			if route == "" || err == echo.ErrNotFound {
+				route = "unmatched"
+			}
+			span.SetName(fmt.Sprintf("%s %s", request.Method, route))
+			span.SetAttributes(attribute.String("http.route", route))
+
+			status := ctx.Response().Status
+			span.SetAttributes(attribute.Int("http.response.status_code", status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+
+			return nil
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package tracing_test
+
+import (
+	"context"
+	"crypto-project-1/internal/tracing"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+	parentSpanID = "00f067aa0ba902b7"
+	traceparent  = "00-" + traceID + "-" + parentSpanID + "-01"
+)
+
+func useInMemoryExporter(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return exporter
+}
+
+func TestMiddleware(t *testing.T) {
+	exporter := useInMemoryExporter(t)
+
+	e := echo.New()
+	e.Use(tracing.Middleware())
+	var handlerSpan trace.SpanContext
+	e.GET("/items/:id", func(ctx echo.Context) error {
+		handlerSpan = trace.SpanContextFromContext(ctx.Request().Context())
+		return ctx.NoContent(http.StatusInternalServerError)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	request.Header.Set("traceparent", traceparent)
+	e.ServeHTTP(httptest.NewRecorder(), request)
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	// the span continues the trace of the caller and is seen by the handler
+	assert.Equal(t, "GET /items/:id", spans[0].Name)
+	assert.Equal(t, traceID, spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, parentSpanID, spans[0].Parent.SpanID().String())
+	assert.Equal(t, spans[0].SpanContext.SpanID(), handlerSpan.SpanID())
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+
+	assert.Equal(t, "GET unmatched", spans[1].Name)
+	assert.False(t, spans[1].Parent.IsValid())
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	exporter := useInMemoryExporter(t)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", traceparent))
+	info := &grpc.UnaryServerInfo{FullMethod: "/crypto.challenge.v1.ChallengeService/CreateChallenge"}
+	var handlerSpan trace.SpanContext
+	_, err := tracing.UnaryServerInterceptor()(ctx, nil, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		handlerSpan = trace.SpanContextFromContext(ctx)
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, info.FullMethod, spans[0].Name)
+	assert.Equal(t, traceID, spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, parentSpanID, spans[0].Parent.SpanID().String())
+	assert.Equal(t, spans[0].SpanContext.SpanID(), handlerSpan.SpanID())
+}
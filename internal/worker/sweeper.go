@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Sweeper is a store that keeps what expired until it is swept.
+type Sweeper interface {
+	// SweepExpired removes what expired before the Unix time and returns how
+	// many entries it removed.
+	SweepExpired(ctx context.Context, before int64) (int, error)
+}
+
+// ExpirySweeper sweeps the stores that keep their expired challenges in
+// memory, which nothing else removes, every interval. The challenges expired
+// for less than retention are kept so that they are still reported expired.
+type ExpirySweeper struct {
+	interval  time.Duration
+	retention time.Duration
+	now       func() time.Time
+	log       *logrus.Entry
+	sweepers  []Sweeper
+}
+
+func NewExpirySweeper(interval, retention time.Duration, now func() time.Time, log *logrus.Entry, sweepers ...Sweeper) *ExpirySweeper {
+	return &ExpirySweeper{
+		interval:  interval,
+		retention: retention,
+		now:       now,
+		log:       log,
+		sweepers:  sweepers,
+	}
+}
+
+func (s *ExpirySweeper) Name() string {
+	return "expiry-sweeper"
+}
+
+// Run sweeps until ctx is done. A failed sweep is logged and tried again at
+// the next interval.
+func (s *ExpirySweeper) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.Sweep(ctx)
+		}
+	}
+}
+
+// Sweep sweeps every store once.
+func (s *ExpirySweeper) Sweep(ctx context.Context) {
+	before := s.now().Add(-s.retention).Unix()
+	log := s.log.WithField(logging.FieldWorker, s.Name())
+	for _, sweeper := range s.sweepers {
+		swept, err := sweeper.SweepExpired(ctx, before)
+		if err != nil {
+			log.WithField(logging.FieldErrorCode, domain.UnexpectedError).WithError(err).Error("failed to sweep expired challenges")
+			continue
+		}
+		if swept > 0 {
+			log.WithField("swept", swept).Debug("expired challenges swept")
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package worker_test
+
+import (
+	"context"
+	"crypto-project-1/internal/worker"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSweeper struct {
+	mu     sync.Mutex
+	before []int64
+	err    error
+}
+
+func (s *testSweeper) SweepExpired(_ context.Context, before int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.before = append(s.before, before)
+
+	return 1, s.err
+}
+
+func (s *testSweeper) sweeps() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]int64(nil), s.before...)
+}
+
+func TestExpirySweeper_Sweep(t *testing.T) {
+	timeNow := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	failing, sweeper := &testSweeper{err: errors.New("connection refused")}, &testSweeper{}
+	expirySweeper := worker.NewExpirySweeper(time.Minute, time.Hour, func() time.Time { return timeNow }, logrus.NewEntry(logrus.New()), failing, sweeper)
+
+	// a failing store does not stop the others
+	expirySweeper.Sweep(context.Background())
+	assert.Equal(t, []int64{timeNow.Add(-time.Hour).Unix()}, failing.sweeps())
+	assert.Equal(t, []int64{timeNow.Add(-time.Hour).Unix()}, sweeper.sweeps())
+}
+
+func TestExpirySweeper_Run(t *testing.T) {
+	sweeper := &testSweeper{}
+	group := worker.NewGroup(logrus.NewEntry(logrus.New()), worker.NewExpirySweeper(time.Millisecond*10, 0, time.Now, logrus.NewEntry(logrus.New()), sweeper))
+
+	group.Start(context.Background())
+	assert.Eventually(t, func() bool {
+		return len(sweeper.sweeps()) >= 2
+	}, time.Second, time.Millisecond*10)
+	assert.Equal(t, worker.StateRunning, group.States()["expiry-sweeper"])
+
+	assert.NoError(t, group.Stop(context.Background()))
+	assert.Equal(t, worker.StateStopped, group.States()["expiry-sweeper"])
+}
@@ -0,0 +1,115 @@
+// Package worker runs the background workers of the API and keeps track of their state.
+package worker
+
+import (
+	"context"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/logging"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	StateIdle    = "idle"
+	StateRunning = "running"
+	StateStopped = "stopped"
+	StateFailed  = "failed"
+)
+
+// Worker is a long running background task. Run must return once ctx is done.
+type Worker interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+// Group starts a set of workers and stops them on shutdown.
+type Group struct {
+	workers []Worker
+	log     *logrus.Entry
+
+	mu     sync.RWMutex
+	states map[string]string
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewGroup(log *logrus.Entry, workers ...Worker) *Group {
+	states := make(map[string]string, len(workers))
+	for _, w := range workers {
+		states[w.Name()] = StateIdle
+	}
+
+	return &Group{
+		workers: workers,
+		log:     log,
+		states:  states,
+	}
+}
+
+// Start runs every worker in its own goroutine until Stop is called or ctx is done.
+func (g *Group) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	g.mu.Lock()
+	g.cancel = cancel
+	g.mu.Unlock()
+
+	for _, w := range g.workers {
+		g.setState(w.Name(), StateRunning)
+		g.wg.Add(1)
+		go func(w Worker) {
+			defer g.wg.Done()
+			if err := w.Run(ctx); err != nil && ctx.Err() == nil {
+				g.log.WithFields(logrus.Fields{
+					logging.FieldWorker:    w.Name(),
+					logging.FieldErrorCode: domain.UnexpectedError,
+				}).WithError(err).Error("worker failed")
+				g.setState(w.Name(), StateFailed)
+				return
+			}
+			g.setState(w.Name(), StateStopped)
+		}(w)
+	}
+}
+
+// Stop asks every worker to return and waits for them until ctx is done.
+func (g *Group) Stop(ctx context.Context) error {
+	g.mu.RLock()
+	cancel := g.cancel
+	g.mu.RUnlock()
+	if cancel != nil {
+		cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// States returns the current state of every worker by name.
+func (g *Group) States() map[string]string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	states := make(map[string]string, len(g.states))
+	for name, state := range g.states {
+		states[name] = state
+	}
+
+	return states
+}
+
+func (g *Group) setState(name, state string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.states[name] = state
+}
@@ -0,0 +1,58 @@
+package worker_test
+
+import (
+	"context"
+	"crypto-project-1/internal/worker"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type testWorker struct {
+	name   string
+	runErr error
+	ignore bool
+}
+
+func (w *testWorker) Name() string {
+	return w.name
+}
+
+func (w *testWorker) Run(ctx context.Context) error {
+	if w.runErr != nil {
+		return w.runErr
+	}
+	if w.ignore {
+		time.Sleep(time.Second)
+		return nil
+	}
+	<-ctx.Done()
+
+	return nil
+}
+
+func TestGroup_StartStop(t *testing.T) {
+	group := worker.NewGroup(logrus.NewEntry(logrus.New()), &testWorker{name: "ok"}, &testWorker{name: "failing", runErr: errors.New("boom")})
+	assert.Equal(t, map[string]string{"ok": worker.StateIdle, "failing": worker.StateIdle}, group.States())
+
+	group.Start(context.Background())
+	assert.Eventually(t, func() bool {
+		return group.States()["failing"] == worker.StateFailed
+	}, time.Second, time.Millisecond*10)
+	assert.Equal(t, worker.StateRunning, group.States()["ok"])
+
+	assert.NoError(t, group.Stop(context.Background()))
+	assert.Equal(t, worker.StateStopped, group.States()["ok"])
+}
+
+func TestGroup_StopTimeout(t *testing.T) {
+	group := worker.NewGroup(logrus.NewEntry(logrus.New()), &testWorker{name: "slow", ignore: true})
+	group.Start(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	assert.ErrorIs(t, group.Stop(ctx), context.DeadlineExceeded)
+}
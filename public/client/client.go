@@ -0,0 +1,386 @@
+// Package client is the Go SDK for the crypto API.
+//
+// It wraps the HTTP endpoints exposed under /v1, decodes the public.ApiResponse
+// envelope into typed results and turns failed calls into *APIError values.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto-project-1/public"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeout      = time.Second * 10
+	defaultRetryBackoff = time.Millisecond * 200
+
+	createChallengePath = "/v1/challenge"
+	verifyChallengePath = "/v1/verify-challenge"
+	statusPath          = "/v1/challenge-status"
+	multisigPath        = "/v1/multisig-signatures"
+	verifyFrostPath     = "/v1/verify-frost-signature"
+	extendedKeysPath    = "/v1/extended-keys"
+
+	tenantHeader = "X-Tenant-ID"
+	bearerPrefix = "Bearer "
+)
+
+// Client calls the crypto API. It is safe for concurrent use.
+type Client struct {
+	baseURL      *url.URL
+	httpClient   *http.Client
+	timeout      time.Duration
+	maxRetries   int
+	retryBackoff time.Duration
+	retryVerify  bool
+	tenant       string
+	apiKey       string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the http.Client used to send requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout sets the timeout applied to every attempt of a call. A zero
+// timeout leaves the deadline to the caller's context.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetries sets how many times a call is retried after a transport error or
+// a 429, 502, 503 or 504 response, and the backoff before the first retry. The
+// backoff doubles after every attempt. The calls that consume a challenge,
+// VerifyChallenge, VerifyFrostSignature and SignMultisig, are sent once unless
+// WithVerifyRetries is set.
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryBackoff = backoff
+	}
+}
+
+// WithVerifyRetries retries the calls that consume a challenge as the others.
+// When the response of an attempt that reached the API is lost, the retry finds
+// the challenge consumed: the token is reported invalid, or its signer a
+// duplicate.
+func WithVerifyRetries() Option {
+	return func(c *Client) {
+		c.retryVerify = true
+	}
+}
+
+// WithTenant sets the tenant the calls are made for, the API uses its default
+// tenant when none is set.
+func WithTenant(tenant string) Option {
+	return func(c *Client) {
+		c.tenant = tenant
+	}
+}
+
+// WithAPIKey sets the API key sent with every call, required when the API
+// enables auth. A key bound to a tenant makes the calls for its tenant.
+func WithAPIKey(apiKey string) Option {
+	return func(c *Client) {
+		c.apiKey = apiKey
+	}
+}
+
+// New creates a Client for the API running at baseURL, e.g. http://localhost:7777.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid base url %q: scheme and host are required", baseURL)
+	}
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/")
+
+	c := &Client{
+		baseURL:      parsed,
+		httpClient:   http.DefaultClient,
+		timeout:      defaultTimeout,
+		retryBackoff: defaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// CreateChallenge asks the API for a new nonce bound to the compressed public key.
+func (c *Client) CreateChallenge(ctx context.Context, pubKey string) (*Challenge, error) {
+	challenge := &Challenge{}
+	body := &public.CreateChallengeRequestBody{
+		PubKey: pubKey,
+	}
+	if err := c.post(ctx, createChallengePath, body, challenge, public.ChallengeCreateSucceed); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// CreateMultisigChallenge asks the API for a nonce that threshold of the
+// compressed public keys must sign, each with a token of its own.
+func (c *Client) CreateMultisigChallenge(ctx context.Context, pubKeys []string, threshold int) (*Challenge, error) {
+	challenge := &Challenge{}
+	body := &public.CreateChallengeRequestBody{
+		PubKeys:   pubKeys,
+		Threshold: threshold,
+	}
+	if err := c.post(ctx, createChallengePath, body, challenge, public.ChallengeCreateSucceed); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// CreateFrostChallenge asks the API for a nonce bound to the hex encoded group
+// key of a FROST group, which signs the SignedMessage of the challenge.
+func (c *Client) CreateFrostChallenge(ctx context.Context, groupKey string) (*Challenge, error) {
+	challenge := &Challenge{}
+	body := &public.CreateChallengeRequestBody{
+		GroupKey: groupKey,
+	}
+	if err := c.post(ctx, createChallengePath, body, challenge, public.ChallengeCreateSucceed); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// VerifyFrostSignature sends the hex encoded aggregated signature of the
+// challenge of groupKey and nonce to the API. A signature rejected by the API
+// is not an error, as with VerifyChallenge.
+func (c *Client) VerifyFrostSignature(ctx context.Context, groupKey, nonce, signature string) (*ValidationResult, error) {
+	result := &ValidationResult{}
+	body := &public.VerifyFrostRequestBody{
+		GroupKey:  groupKey,
+		Nonce:     nonce,
+		Signature: signature,
+	}
+	err := c.consume(ctx, verifyFrostPath, body, result,
+		public.ChallengeValidationSucceeded, public.ChallengeValidationFailed)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// EnrollExtendedKey enrolls the BIP-32 extended public key xpub, whose
+// children at the paths of pathPattern, such as 0/*, sign the challenges
+// created for it. The index of the wildcard is at most maxIndex.
+func (c *Client) EnrollExtendedKey(ctx context.Context, xpub, pathPattern string, maxIndex uint32) (*ExtendedKey, error) {
+	key := &ExtendedKey{}
+	body := &public.EnrollExtendedKeyRequestBody{
+		Xpub:        xpub,
+		PathPattern: pathPattern,
+		MaxIndex:    maxIndex,
+	}
+	if err := c.post(ctx, extendedKeysPath, body, key, public.ExtendedKeyEnrollSucceeded); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// SignMultisig submits tokens signing the multi-signature challenge of nonce,
+// see SignChallenge. A token rejected by the API is not an error, its result
+// carries the validation error and its signer is not added.
+func (c *Client) SignMultisig(ctx context.Context, nonce string, tokens ...string) (*MultisigResult, error) {
+	result := &MultisigResult{}
+	body := &public.SignMultisigRequestBody{
+		Nonce:  nonce,
+		Tokens: tokens,
+	}
+	if err := c.consume(ctx, multisigPath, body, result, public.ChallengeSignSucceeded); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// VerifyChallenge sends a signed token to the API. A token rejected by the API
+// is not an error: the returned result has Valid set to false and carries the
+// validation error reported by the API.
+func (c *Client) VerifyChallenge(ctx context.Context, token string) (*ValidationResult, error) {
+	result := &ValidationResult{}
+	body := &public.VerifyChallengeRequestBody{
+		Token: token,
+	}
+	err := c.consume(ctx, verifyChallengePath, body, result,
+		public.ChallengeValidationSucceeded, public.ChallengeValidationFailed)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Status returns the state of the challenge created for pubKey with nonce.
+func (c *Client) Status(ctx context.Context, pubKey, nonce string) (*ChallengeStatus, error) {
+	status := &ChallengeStatus{}
+	body := &public.ChallengeStatusRequestBody{
+		PubKey: pubKey,
+		Nonce:  nonce,
+	}
+	if err := c.post(ctx, statusPath, body, status, public.ChallengeStatusSucceeded); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// post sends body to path, retrying when the failure is transient, and decodes
+// the response envelope into result. A response whose code is not one of
+// expectedCodes is returned as an *APIError.
+func (c *Client) post(ctx context.Context, path string, body, result interface{}, expectedCodes ...string) error {
+	return c.send(ctx, path, body, result, c.maxRetries, expectedCodes)
+}
+
+// consume is post for the calls that consume a challenge, which a retry cannot
+// repeat: they are sent once unless WithVerifyRetries is set.
+func (c *Client) consume(ctx context.Context, path string, body, result interface{}, expectedCodes ...string) error {
+	maxRetries := 0
+	if c.retryVerify {
+		maxRetries = c.maxRetries
+	}
+
+	return c.send(ctx, path, body, result, maxRetries, expectedCodes)
+}
+
+func (c *Client) send(ctx context.Context, path string, body, result interface{}, maxRetries int, expectedCodes []string) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("could not marshal request body: %w", err)
+	}
+
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := c.do(ctx, path, payload, result, expectedCodes)
+		if err == nil || attempt >= maxRetries || ctx.Err() != nil || !isRetryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) do(ctx context.Context, path string, payload []byte, result interface{}, expectedCodes []string) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	endpoint := *c.baseURL
+	endpoint.Path += path
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	if c.tenant != "" {
+		request.Header.Set(tenantHeader, c.tenant)
+	}
+	if c.apiKey != "" {
+		request.Header.Set("Authorization", bearerPrefix+c.apiKey)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return &transportError{err: err}
+	}
+	defer response.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return &transportError{err: err}
+	}
+
+	envelope := &apiResponse{}
+	if err := json.Unmarshal(responseBody, envelope); err != nil {
+		return &APIError{
+			StatusCode: response.StatusCode,
+			Message:    fmt.Sprintf("could not decode response body: %s", err),
+		}
+	}
+
+	if response.StatusCode >= http.StatusBadRequest || !containsCode(expectedCodes, envelope.Code) {
+		return &APIError{
+			StatusCode: response.StatusCode,
+			Code:       envelope.Code,
+			Message:    envelope.Message,
+		}
+	}
+
+	if len(envelope.Result) > 0 && string(envelope.Result) != "null" {
+		if err := json.Unmarshal(envelope.Result, result); err != nil {
+			return fmt.Errorf("could not decode response result: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// apiResponse mirrors public.ApiResponse, keeping the result raw so that it can
+// be decoded into the type expected by each call.
+type apiResponse struct {
+	Result  json.RawMessage `json:"result"`
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+}
+
+func containsCode(codes []string, code string) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isRetryable(err error) bool {
+	var transportErr *transportError
+	if errors.As(err, &transportErr) {
+		return true
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+
+	return false
+}
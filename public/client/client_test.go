@@ -0,0 +1,348 @@
+package client_test
+
+import (
+	"context"
+	"crypto-project-1/internal/app"
+	"crypto-project-1/internal/config"
+	"crypto-project-1/internal/domain"
+	"crypto-project-1/internal/metrics"
+	"crypto-project-1/internal/repository"
+	"crypto-project-1/internal/repository/mock_repository"
+	"crypto-project-1/internal/service"
+	"crypto-project-1/public"
+	"crypto-project-1/public/client"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, mockRepo *mock_repository.MockChallengeRepository) *client.Client {
+	return newServer(t, mockRepo, config.Default().Challenge)
+}
+
+func newServer(t *testing.T, challengeRepo repository.ChallengeRepository, cfg config.ChallengeConfig) *client.Client {
+	microservice := app.NewCryptoMicroservice(service.NewChallengeService(repository.NewRepository(repository.NoTx, challengeRepo, nil, repository.NewChallengeStatsMemoryRepository(), repository.NewMultisigMemoryRepository(), repository.NewExtendedKeyMemoryRepository()), time.Now, cfg, config.Default().Tenancy, service.NewNonceGenerators(), logrus.NewEntry(logrus.New())), nil, nil, nil, logrus.NewEntry(logrus.New()))
+	handler, err := app.NewServer(microservice, config.Default().HTTP, config.Default().Auth, metrics.New())
+	require.NoError(t, err)
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := client.New(server.URL)
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestClient_SignAndVerify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_repository.NewMockChallengeRepository(ctrl)
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	pubKey, err := client.CompressPublicKey(privateKey.Public())
+	require.NoError(t, err)
+
+	var created *domain.Challenge
+	mockRepo.EXPECT().CreateChallenge(gomock.Any(), "default", pubKey, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, tenantID, pubKey, nonce, _ string, createdAt, expiresAt int64) (*domain.Challenge, error) {
+			created = &domain.Challenge{TenantID: tenantID, PublicKey: pubKey, Nonce: nonce, ExpiresAt: expiresAt}
+			return created, nil
+		})
+	mockRepo.EXPECT().GetChallenges(gomock.Any(), "default", pubKey, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, _ string) ([]*domain.Challenge, error) {
+			return []*domain.Challenge{created}, nil
+		})
+
+	result, err := newTestServer(t, mockRepo).SignAndVerify(context.Background(), privateKey, "wheltee")
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, "EC P-256", result.KeyType)
+	assert.Equal(t, "ES256", result.Algorithm)
+	assert.Equal(t, created.Nonce, result.Claims["jti"])
+	assert.Equal(t, "wheltee", result.Claims["aud"])
+	assert.Equal(t, created.ExpiresAt, result.ExpiresAt)
+	assert.NotZero(t, result.VerifiedAt)
+}
+
+func TestClient_SignAndVerify_RSA(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	result, err := newServer(t, repository.NewChallengeMemoryRepository(), config.Default().Challenge).SignAndVerify(context.Background(), privateKey, "wheltee")
+	require.NoError(t, err)
+	assert.True(t, result.Valid, result.ValidationError)
+	assert.Equal(t, "RSA 2048", result.KeyType)
+	assert.Equal(t, "RS256", result.Algorithm)
+}
+
+func TestClient_SignAndVerify_Sealed(t *testing.T) {
+	cfg := config.Default().Challenge
+	cfg.SingleUse = true
+	cfg.SealKeys = []string{base64.StdEncoding.EncodeToString(make([]byte, 32))}
+	sealed, err := repository.NewChallengeSealedRepository(cfg, time.Now)
+	require.NoError(t, err)
+	c := newServer(t, sealed, cfg)
+	ctx := context.Background()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	pubKey, err := client.CompressPublicKey(privateKey.Public())
+	require.NoError(t, err)
+
+	challenge, err := c.CreateChallenge(ctx, pubKey)
+	require.NoError(t, err)
+	status, err := c.Status(ctx, pubKey, challenge.Nonce)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ChallengeStatusPending, status.Status)
+
+	token, err := client.SignChallenge(privateKey, challenge, "wheltee", time.Now())
+	require.NoError(t, err)
+	result, err := c.VerifyChallenge(ctx, token)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	// the replay cache keeps the token single use
+	result, err = c.VerifyChallenge(ctx, token)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, service.ValidationErrorInvalidNonce, result.ValidationError)
+
+	// a challenge is bound to its public key
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	challenge, err = c.CreateChallenge(ctx, pubKey)
+	require.NoError(t, err)
+	token, err = client.SignChallenge(otherKey, challenge, "wheltee", time.Now())
+	require.NoError(t, err)
+	result, err = c.VerifyChallenge(ctx, token)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+}
+
+func TestClient_SignAndVerify_Message(t *testing.T) {
+	cfg := config.Default().Challenge
+	cfg.Domain = "shop.example"
+	c := newServer(t, repository.NewChallengeMemoryRepository(), cfg)
+	ctx := context.Background()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	result, err := c.SignAndVerify(ctx, privateKey, "wheltee")
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	// the token must sign the message shown to the signer
+	pubKey, err := client.CompressPublicKey(privateKey.Public())
+	require.NoError(t, err)
+	challenge, err := c.CreateChallenge(ctx, pubKey)
+	require.NoError(t, err)
+	assert.Contains(t, challenge.Message, "shop.example asks you to sign in")
+	assert.Contains(t, challenge.Message, "Nonce: "+challenge.Nonce)
+	challenge.Message = strings.Replace(challenge.Message, "shop.example", "evil.example", 1)
+	token, err := client.SignChallenge(privateKey, challenge, "wheltee", time.Now())
+	require.NoError(t, err)
+	result, err = c.VerifyChallenge(ctx, token)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, service.ValidationErrorInvalidMessage, result.ValidationError)
+}
+
+func TestClient_SignMultisig(t *testing.T) {
+	c := newServer(t, repository.NewChallengeMemoryRepository(), config.Default().Challenge)
+	ctx := context.Background()
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	pubKeys := make([]string, 3)
+	for i := range keys {
+		var err error
+		keys[i], err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		pubKeys[i], err = client.CompressPublicKey(keys[i].Public())
+		require.NoError(t, err)
+	}
+
+	challenge, err := c.CreateMultisigChallenge(ctx, pubKeys, 2)
+	require.NoError(t, err)
+	assert.Equal(t, pubKeys, challenge.PublicKeys)
+	assert.Equal(t, 2, challenge.Threshold)
+
+	first, err := client.SignChallenge(keys[0], challenge, "wheltee", time.Now())
+	require.NoError(t, err)
+	result, err := c.SignMultisig(ctx, challenge.Nonce, first)
+	require.NoError(t, err)
+	assert.False(t, result.Verified)
+	status, err := c.Status(ctx, pubKeys[2], challenge.Nonce)
+	require.NoError(t, err)
+	assert.Equal(t, client.StatusPending, status.Status)
+	assert.Equal(t, []string{pubKeys[0]}, status.Signers)
+
+	second, err := client.SignChallenge(keys[2], challenge, "wheltee", time.Now())
+	require.NoError(t, err)
+	result, err = c.SignMultisig(ctx, challenge.Nonce, first, second)
+	require.NoError(t, err)
+	assert.True(t, result.Verified)
+	assert.Equal(t, []string{pubKeys[0], pubKeys[2]}, result.Signers)
+	require.Len(t, result.Signatures, 2)
+	assert.Equal(t, service.ValidationErrorDuplicateSigner, result.Signatures[0].ValidationError)
+	assert.True(t, result.Signatures[1].Valid)
+	status, err = c.Status(ctx, pubKeys[1], challenge.Nonce)
+	require.NoError(t, err)
+	assert.Equal(t, client.StatusVerified, status.Status)
+
+	_, err = c.CreateMultisigChallenge(ctx, pubKeys, 4)
+	assert.True(t, client.IsCode(err, public.ChallengeCreateFailed))
+}
+
+func TestClient_VerifyFrostSignature(t *testing.T) {
+	c := newServer(t, repository.NewChallengeMemoryRepository(), config.Default().Challenge)
+	ctx := context.Background()
+
+	// an aggregated FROST(Ed25519, SHA-512) signature is one of RFC 8032
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	groupKey := hex.EncodeToString(pub)
+
+	challenge, err := c.CreateFrostChallenge(ctx, groupKey)
+	require.NoError(t, err)
+	assert.Equal(t, groupKey, challenge.PublicKey)
+	assert.Equal(t, "FROST-ED25519-SHA512-v1", challenge.Ciphersuite)
+
+	result, err := c.VerifyFrostSignature(ctx, groupKey, challenge.Nonce, hex.EncodeToString(ed25519.Sign(priv, challenge.SignedMessage())))
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, "FROST", result.KeyType)
+
+	result, err = c.VerifyFrostSignature(ctx, groupKey, challenge.Nonce, hex.EncodeToString(ed25519.Sign(priv, []byte("other message"))))
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.NotEmpty(t, result.ValidationError)
+
+	_, err = c.CreateFrostChallenge(ctx, "00")
+	assert.True(t, client.IsCode(err, public.RequestValidationFailed))
+}
+
+func TestClient_VerifyChallenge_InvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	result, err := newTestServer(t, mock_repository.NewMockChallengeRepository(ctrl)).
+		VerifyChallenge(context.Background(), "not-a-token")
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.NotEmpty(t, result.ValidationError)
+}
+
+func TestClient_Status(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_repository.NewMockChallengeRepository(ctrl)
+	mockRepo.EXPECT().GetChallenges(gomock.Any(), "default", "pub-key", "nonce").Return(nil, nil)
+
+	status, err := newTestServer(t, mockRepo).Status(context.Background(), "pub-key", "nonce")
+	require.NoError(t, err)
+	assert.Equal(t, client.StatusNotFound, status.Status)
+}
+
+func TestClient_CreateChallenge_Errors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"code":"","message":"unavailable"}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"result":null,"code":"CryptoAPI-ChallengeCreateFailed","message":"error while trying to create challenge"}`))
+	}))
+	defer server.Close()
+
+	c, err := client.New(server.URL, client.WithRetries(3, time.Millisecond))
+	require.NoError(t, err)
+
+	_, err = c.CreateChallenge(context.Background(), "pub-key")
+	var apiErr *client.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+	assert.True(t, client.IsCode(err, public.ChallengeCreateFailed))
+	// the 503 is retried, the 500 is not
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestClient_VerifyChallenge_Retries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(`{"code":"","message":"bad gateway"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":{"valid":true},"code":"CryptoAPI-ChallengeValidationSucceeded","message":""}`))
+	}))
+	defer server.Close()
+
+	// the verification may have consumed the challenge before the 502
+	c, err := client.New(server.URL, client.WithRetries(3, time.Millisecond))
+	require.NoError(t, err)
+	_, err = c.VerifyChallenge(context.Background(), "token")
+	var apiErr *client.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	atomic.StoreInt32(&calls, 0)
+	c, err = client.New(server.URL, client.WithRetries(3, time.Millisecond), client.WithVerifyRetries())
+	require.NoError(t, err)
+	result, err := c.VerifyChallenge(context.Background(), "token")
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestClient_WithTenant(t *testing.T) {
+	var tenant string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant = r.Header.Get("X-Tenant-ID")
+		_, _ = w.Write([]byte(`{"result":{"tenantId":"shop","publicKey":"pub-key","nonce":"nonce","expiresAt":1},"code":"CryptoAPI-ChallengeCreateSucceed","message":""}`))
+	}))
+	defer server.Close()
+
+	c, err := client.New(server.URL, client.WithTenant("shop"))
+	require.NoError(t, err)
+
+	challenge, err := c.CreateChallenge(context.Background(), "pub-key")
+	require.NoError(t, err)
+	assert.Equal(t, "shop", tenant)
+	assert.Equal(t, "shop", challenge.TenantID)
+}
+
+func TestClient_WithAPIKey(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"result":{"valid":true},"code":"CryptoAPI-ChallengeValidationSucceeded","message":""}`))
+	}))
+	defer server.Close()
+
+	c, err := client.New(server.URL, client.WithAPIKey("ck_id.secret"))
+	require.NoError(t, err)
+
+	_, err = c.VerifyChallenge(context.Background(), "token")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer ck_id.secret", authorization)
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+// APIError is returned when the API answers with an error status or with a
+// response code the call did not expect.
+type APIError struct {
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+	// Code is one of the codes declared in the public package, empty when the
+	// response could not be decoded.
+	Code    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("crypto api: http status %d: %s", e.StatusCode, e.Message)
+	}
+
+	return fmt.Sprintf("crypto api: http status %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// VerificationError is returned by SignAndVerify when the API rejects the
+// signed token.
+type VerificationError struct {
+	Reason string
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("crypto api: challenge validation failed: %s", e.Reason)
+}
+
+// IsCode reports whether err is an *APIError carrying the given response code.
+func IsCode(err error, code string) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// transportError wraps failures that happened before a response was decoded,
+// so that they can be retried.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	return fmt.Sprintf("crypto api: %s", e.err)
+}
+
+func (e *transportError) Unwrap() error {
+	return e.err
+}
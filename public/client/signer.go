@@ -0,0 +1,175 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	publicKeyHeader = "kid"
+	// messageClaim carries the message of the challenge
+	messageClaim = "msg"
+)
+
+// SignAndVerify runs the whole challenge flow for signer: it creates a
+// challenge for the signer's public key, signs a token carrying the nonce for
+// the given audience and verifies it. A token rejected by the API is returned
+// as a *VerificationError.
+func (c *Client) SignAndVerify(ctx context.Context, signer crypto.Signer, audience string) (*ValidationResult, error) {
+	pubKey, err := CompressPublicKey(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := c.CreateChallenge(ctx, pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := SignChallenge(signer, challenge, audience, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.VerifyChallenge(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Valid {
+		return result, &VerificationError{Reason: result.ValidationError}
+	}
+
+	return result, nil
+}
+
+// CompressPublicKey encodes a public key the way the API expects it: PKIX PEM,
+// hex encoded, gzipped and base64 encoded.
+func CompressPublicKey(publicKey crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal public key: %w", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: der,
+	})
+
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write([]byte(hex.EncodeToString(keyPEM))); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+}
+
+// SignChallenge creates a JWT for the challenge nonce signed by signer, with
+// the message of the challenge when it has one. The token of a
+// multi-signature challenge names the public key of signer. ECDSA keys on
+// P-256, P-384 and P-521 and RSA keys are supported.
+func SignChallenge(signer crypto.Signer, challenge *Challenge, audience string, now time.Time) (string, error) {
+	algorithm, hash, err := signingAlgorithm(signer.Public())
+	if err != nil {
+		return "", err
+	}
+	pubKey := challenge.PublicKey
+	if pubKey == "" {
+		if pubKey, err = CompressPublicKey(signer.Public()); err != nil {
+			return "", err
+		}
+	}
+
+	header, err := json.Marshal(map[string]string{
+		"alg":           algorithm,
+		"typ":           "JWT",
+		publicKeyHeader: pubKey,
+	})
+	if err != nil {
+		return "", err
+	}
+	claims := map[string]interface{}{
+		"jti": challenge.Nonce,
+		"aud": audience,
+		"iat": now.Unix(),
+		"nbf": now.Unix(),
+		"exp": challenge.ExpiresAt,
+	}
+	if challenge.Message != "" {
+		claims[messageClaim] = challenge.Message
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := hash.New()
+	digest.Write([]byte(signingInput))
+	signature, err := signer.Sign(rand.Reader, digest.Sum(nil), hash)
+	if err != nil {
+		return "", fmt.Errorf("could not sign token: %w", err)
+	}
+
+	if ecKey, ok := signer.Public().(*ecdsa.PublicKey); ok {
+		if signature, err = joseECDSASignature(signature, ecKey.Curve); err != nil {
+			return "", err
+		}
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func signingAlgorithm(publicKey crypto.PublicKey) (string, crypto.Hash, error) {
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		switch key.Curve {
+		case elliptic.P256():
+			return "ES256", crypto.SHA256, nil
+		case elliptic.P384():
+			return "ES384", crypto.SHA384, nil
+		case elliptic.P521():
+			return "ES512", crypto.SHA512, nil
+		}
+	case *rsa.PublicKey:
+		return "RS256", crypto.SHA256, nil
+	}
+
+	return "", 0, errors.New("unsupported signer key type")
+}
+
+// joseECDSASignature converts an ASN.1 ECDSA signature, as returned by
+// crypto.Signer, into the fixed size r||s form used by JWS.
+func joseECDSASignature(der []byte, curve elliptic.Curve) ([]byte, error) {
+	var signature struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(der, &signature); err != nil {
+		return nil, fmt.Errorf("could not decode ecdsa signature: %w", err)
+	}
+
+	size := (curve.Params().BitSize + 7) / 8
+	jose := make([]byte, 2*size)
+	signature.R.FillBytes(jose[:size])
+	signature.S.FillBytes(jose[size:])
+
+	return jose, nil
+}
@@ -0,0 +1,90 @@
+package client
+
+// Challenge statuses reported by Status.
+const (
+	StatusPending  = "pending"
+	StatusExpired  = "expired"
+	StatusNotFound = "notFound"
+	// StatusVerified is the final status of a multi-signature challenge once
+	// its threshold of signers signed it.
+	StatusVerified = "verified"
+)
+
+// Challenge is a nonce issued by the API for a public key.
+type Challenge struct {
+	TenantID  string `json:"tenantId"`
+	PublicKey string `json:"publicKey"`
+	Nonce     string `json:"nonce"`
+	ExpiresAt int64  `json:"expiresAt"`
+	// Message is the text the signer is asked to sign when the tenant has a
+	// domain, show it to the signer before signing.
+	Message string `json:"message,omitempty"`
+	// PublicKeys are the keys of a multi-signature challenge, whose PublicKey
+	// is empty, Threshold the number of them that must sign it.
+	PublicKeys []string `json:"publicKeys,omitempty"`
+	Threshold  int      `json:"threshold,omitempty"`
+	// Ciphersuite is the FROST ciphersuite of the group key of a threshold
+	// challenge, its PublicKey.
+	Ciphersuite string `json:"ciphersuite,omitempty"`
+}
+
+// SignedMessage is what a FROST group signs for the challenge: the message,
+// or the nonce when there is none.
+func (c *Challenge) SignedMessage() []byte {
+	if c.Message == "" {
+		return []byte(c.Nonce)
+	}
+
+	return []byte(c.Message)
+}
+
+// ValidationResult is the outcome of a challenge verification. A valid result
+// also tells what the token proved, the other fields are empty otherwise.
+type ValidationResult struct {
+	Valid           bool   `json:"valid"`
+	ValidationError string `json:"validationError"`
+	KeyFingerprint  string `json:"keyFingerprint,omitempty"`
+	// KeyType is the type of the public key, e.g. EC P-256
+	KeyType   string                 `json:"keyType,omitempty"`
+	Algorithm string                 `json:"algorithm,omitempty"`
+	Claims    map[string]interface{} `json:"claims,omitempty"`
+	// DerivationPath is the path of the child key that signed the token
+	// relative to its extended public key, e.g. 0/5
+	DerivationPath string `json:"derivationPath,omitempty"`
+	// CreatedAt, ExpiresAt and VerifiedAt are Unix times in seconds
+	CreatedAt  int64 `json:"createdAt,omitempty"`
+	ExpiresAt  int64 `json:"expiresAt,omitempty"`
+	VerifiedAt int64 `json:"verifiedAt,omitempty"`
+}
+
+// ChallengeStatus is the state of a previously created challenge.
+type ChallengeStatus struct {
+	PublicKey string `json:"publicKey"`
+	Nonce     string `json:"nonce"`
+	Status    string `json:"status"`
+	ExpiresAt int64  `json:"expiresAt,omitempty"`
+	// Threshold and Signers are the progress of a multi-signature challenge
+	Threshold int      `json:"threshold,omitempty"`
+	Signers   []string `json:"signers,omitempty"`
+}
+
+// ExtendedKey is an enrolled BIP-32 extended public key, whose children at
+// the paths of PathPattern sign its challenges.
+type ExtendedKey struct {
+	TenantID    string `json:"tenantId"`
+	Xpub        string `json:"xpub"`
+	PathPattern string `json:"pathPattern"`
+	MaxIndex    uint32 `json:"maxIndex"`
+	CreatedAt   int64  `json:"createdAt"`
+}
+
+// MultisigResult is the progress of a multi-signature challenge after tokens
+// were submitted, with the result of each token in order.
+type MultisigResult struct {
+	Nonce      string              `json:"nonce"`
+	Verified   bool                `json:"verified"`
+	Threshold  int                 `json:"threshold"`
+	Signers    []string            `json:"signers"`
+	ExpiresAt  int64               `json:"expiresAt,omitempty"`
+	Signatures []*ValidationResult `json:"signatures"`
+}
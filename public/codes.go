@@ -7,4 +7,29 @@ const (
 	ChallengeValidationFailed    = ServicePrefix + "ChallengeValidationFailed"
 	ChallengeCreateFailed        = ServicePrefix + "ChallengeCreateFailed"
 	ChallengeCreateSucceed       = ServicePrefix + "ChallengeCreateSucceed"
+	ChallengeStatusSucceeded     = ServicePrefix + "ChallengeStatusSucceeded"
+	ChallengeStatusFailed        = ServicePrefix + "ChallengeStatusFailed"
+	RequestValidationFailed      = ServicePrefix + "RequestValidationFailed"
+	AuthenticationFailed         = ServicePrefix + "AuthenticationFailed"
+	PermissionDenied             = ServicePrefix + "PermissionDenied"
+	ApiKeyCreateSucceeded        = ServicePrefix + "ApiKeyCreateSucceeded"
+	ApiKeyCreateFailed           = ServicePrefix + "ApiKeyCreateFailed"
+	ApiKeyListSucceeded          = ServicePrefix + "ApiKeyListSucceeded"
+	ApiKeyListFailed             = ServicePrefix + "ApiKeyListFailed"
+	ApiKeyRotateSucceeded        = ServicePrefix + "ApiKeyRotateSucceeded"
+	ApiKeyRotateFailed           = ServicePrefix + "ApiKeyRotateFailed"
+	ApiKeyRevokeSucceeded        = ServicePrefix + "ApiKeyRevokeSucceeded"
+	ApiKeyRevokeFailed           = ServicePrefix + "ApiKeyRevokeFailed"
+	ApiKeyGetSucceeded           = ServicePrefix + "ApiKeyGetSucceeded"
+	ApiKeyGetFailed              = ServicePrefix + "ApiKeyGetFailed"
+	ChallengeSearchSucceeded     = ServicePrefix + "ChallengeSearchSucceeded"
+	ChallengeSearchFailed        = ServicePrefix + "ChallengeSearchFailed"
+	ChallengeExpireSucceeded     = ServicePrefix + "ChallengeExpireSucceeded"
+	ChallengeExpireFailed        = ServicePrefix + "ChallengeExpireFailed"
+	ChallengeStatsSucceeded      = ServicePrefix + "ChallengeStatsSucceeded"
+	ChallengeStatsFailed         = ServicePrefix + "ChallengeStatsFailed"
+	ChallengeSignSucceeded       = ServicePrefix + "ChallengeSignSucceeded"
+	ChallengeSignFailed          = ServicePrefix + "ChallengeSignFailed"
+	ExtendedKeyEnrollSucceeded   = ServicePrefix + "ExtendedKeyEnrollSucceeded"
+	ExtendedKeyEnrollFailed      = ServicePrefix + "ExtendedKeyEnrollFailed"
 )
@@ -0,0 +1,12 @@
+package public
+
+import (
+	// embed the OpenAPI document of the API
+	_ "embed"
+)
+
+// OpenAPISpec is the OpenAPI 3 document describing the HTTP API. It is served
+// at /openapi.json and used to validate incoming requests.
+//
+//go:embed openapi.json
+var OpenAPISpec []byte
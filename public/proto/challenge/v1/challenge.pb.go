@@ -0,0 +1,1083 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v5.29.3
+// source: challenge/v1/challenge.proto
+
+package challengev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ChallengeState int32
+
+const (
+	ChallengeState_CHALLENGE_STATE_UNSPECIFIED ChallengeState = 0
+	ChallengeState_CHALLENGE_STATE_PENDING     ChallengeState = 1
+	ChallengeState_CHALLENGE_STATE_EXPIRED     ChallengeState = 2
+	ChallengeState_CHALLENGE_STATE_NOT_FOUND   ChallengeState = 3
+	// Final state of a multi-signature challenge once its threshold of signers signed it.
+	ChallengeState_CHALLENGE_STATE_VERIFIED ChallengeState = 4
+)
+
+// Enum value maps for ChallengeState.
+var (
+	ChallengeState_name = map[int32]string{
+		0: "CHALLENGE_STATE_UNSPECIFIED",
+		1: "CHALLENGE_STATE_PENDING",
+		2: "CHALLENGE_STATE_EXPIRED",
+		3: "CHALLENGE_STATE_NOT_FOUND",
+		4: "CHALLENGE_STATE_VERIFIED",
+	}
+	ChallengeState_value = map[string]int32{
+		"CHALLENGE_STATE_UNSPECIFIED": 0,
+		"CHALLENGE_STATE_PENDING":     1,
+		"CHALLENGE_STATE_EXPIRED":     2,
+		"CHALLENGE_STATE_NOT_FOUND":   3,
+		"CHALLENGE_STATE_VERIFIED":    4,
+	}
+)
+
+func (x ChallengeState) Enum() *ChallengeState {
+	p := new(ChallengeState)
+	*p = x
+	return p
+}
+
+func (x ChallengeState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChallengeState) Descriptor() protoreflect.EnumDescriptor {
+	return file_challenge_v1_challenge_proto_enumTypes[0].Descriptor()
+}
+
+func (ChallengeState) Type() protoreflect.EnumType {
+	return &file_challenge_v1_challenge_proto_enumTypes[0]
+}
+
+func (x ChallengeState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChallengeState.Descriptor instead.
+func (ChallengeState) EnumDescriptor() ([]byte, []int) {
+	return file_challenge_v1_challenge_proto_rawDescGZIP(), []int{0}
+}
+
+type Challenge struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Base64 encoded gzip of the hex encoded PEM public key, or an enrolled
+	// extended public key.
+	PublicKey string `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Nonce     string `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// Unix time in seconds.
+	ExpiresAt int64 `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Text to sign in the msg claim of the token, empty unless the tenant has a domain.
+	Message string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	// Public keys of a multi-signature challenge, whose public_key is empty,
+	// and the number of them that must sign it.
+	PublicKeys []string `protobuf:"bytes,5,rep,name=public_keys,json=publicKeys,proto3" json:"public_keys,omitempty"`
+	Threshold  int32    `protobuf:"varint,6,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	// FROST ciphersuite of the group key of a threshold challenge, its public_key.
+	Ciphersuite   string `protobuf:"bytes,7,opt,name=ciphersuite,proto3" json:"ciphersuite,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Challenge) Reset() {
+	*x = Challenge{}
+	mi := &file_challenge_v1_challenge_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Challenge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Challenge) ProtoMessage() {}
+
+func (x *Challenge) ProtoReflect() protoreflect.Message {
+	mi := &file_challenge_v1_challenge_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Challenge.ProtoReflect.Descriptor instead.
+func (*Challenge) Descriptor() ([]byte, []int) {
+	return file_challenge_v1_challenge_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Challenge) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *Challenge) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *Challenge) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *Challenge) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Challenge) GetPublicKeys() []string {
+	if x != nil {
+		return x.PublicKeys
+	}
+	return nil
+}
+
+func (x *Challenge) GetThreshold() int32 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *Challenge) GetCiphersuite() string {
+	if x != nil {
+		return x.Ciphersuite
+	}
+	return ""
+}
+
+// CreateChallengeRequest asks for a challenge of public_key, for a
+// multi-signature challenge that threshold of public_keys must sign, or for a
+// threshold challenge of the group_key of a FROST group.
+type CreateChallengeRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	PublicKey  string                 `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	PublicKeys []string               `protobuf:"bytes,2,rep,name=public_keys,json=publicKeys,proto3" json:"public_keys,omitempty"`
+	Threshold  int32                  `protobuf:"varint,3,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	// Hex encoded group key: 32 bytes for FROST(Ed25519, SHA-512), a compressed
+	// point of 33 bytes for FROST(secp256k1, SHA-256).
+	GroupKey      string `protobuf:"bytes,4,opt,name=group_key,json=groupKey,proto3" json:"group_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateChallengeRequest) Reset() {
+	*x = CreateChallengeRequest{}
+	mi := &file_challenge_v1_challenge_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateChallengeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateChallengeRequest) ProtoMessage() {}
+
+func (x *CreateChallengeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_challenge_v1_challenge_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateChallengeRequest.ProtoReflect.Descriptor instead.
+func (*CreateChallengeRequest) Descriptor() ([]byte, []int) {
+	return file_challenge_v1_challenge_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateChallengeRequest) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *CreateChallengeRequest) GetPublicKeys() []string {
+	if x != nil {
+		return x.PublicKeys
+	}
+	return nil
+}
+
+func (x *CreateChallengeRequest) GetThreshold() int32 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *CreateChallengeRequest) GetGroupKey() string {
+	if x != nil {
+		return x.GroupKey
+	}
+	return ""
+}
+
+type CreateChallengeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Challenge     *Challenge             `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateChallengeResponse) Reset() {
+	*x = CreateChallengeResponse{}
+	mi := &file_challenge_v1_challenge_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateChallengeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateChallengeResponse) ProtoMessage() {}
+
+func (x *CreateChallengeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_challenge_v1_challenge_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateChallengeResponse.ProtoReflect.Descriptor instead.
+func (*CreateChallengeResponse) Descriptor() ([]byte, []int) {
+	return file_challenge_v1_challenge_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateChallengeResponse) GetChallenge() *Challenge {
+	if x != nil {
+		return x.Challenge
+	}
+	return nil
+}
+
+type VerifyChallengeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyChallengeRequest) Reset() {
+	*x = VerifyChallengeRequest{}
+	mi := &file_challenge_v1_challenge_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyChallengeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyChallengeRequest) ProtoMessage() {}
+
+func (x *VerifyChallengeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_challenge_v1_challenge_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyChallengeRequest.ProtoReflect.Descriptor instead.
+func (*VerifyChallengeRequest) Descriptor() ([]byte, []int) {
+	return file_challenge_v1_challenge_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *VerifyChallengeRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// VerifyChallengeResponse tells what a valid token proved, the fields after
+// validation_error are empty when the token is not valid.
+type VerifyChallengeResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Valid           bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	ValidationError string                 `protobuf:"bytes,2,opt,name=validation_error,json=validationError,proto3" json:"validation_error,omitempty"`
+	// Fingerprint of the public key that signed the token.
+	KeyFingerprint string `protobuf:"bytes,3,opt,name=key_fingerprint,json=keyFingerprint,proto3" json:"key_fingerprint,omitempty"`
+	// Type of the public key, e.g. EC P-256.
+	KeyType   string `protobuf:"bytes,4,opt,name=key_type,json=keyType,proto3" json:"key_type,omitempty"`
+	Algorithm string `protobuf:"bytes,5,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	// Claims of the token, registered and custom ones.
+	Claims *structpb.Struct `protobuf:"bytes,6,opt,name=claims,proto3" json:"claims,omitempty"`
+	// Unix times in seconds of the challenge and of the verification.
+	CreatedAt  int64 `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt  int64 `protobuf:"varint,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	VerifiedAt int64 `protobuf:"varint,9,opt,name=verified_at,json=verifiedAt,proto3" json:"verified_at,omitempty"`
+	// Path of the child key that signed the token relative to its extended
+	// public key, e.g. 0/5.
+	DerivationPath string `protobuf:"bytes,10,opt,name=derivation_path,json=derivationPath,proto3" json:"derivation_path,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *VerifyChallengeResponse) Reset() {
+	*x = VerifyChallengeResponse{}
+	mi := &file_challenge_v1_challenge_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyChallengeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyChallengeResponse) ProtoMessage() {}
+
+func (x *VerifyChallengeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_challenge_v1_challenge_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyChallengeResponse.ProtoReflect.Descriptor instead.
+func (*VerifyChallengeResponse) Descriptor() ([]byte, []int) {
+	return file_challenge_v1_challenge_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *VerifyChallengeResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *VerifyChallengeResponse) GetValidationError() string {
+	if x != nil {
+		return x.ValidationError
+	}
+	return ""
+}
+
+func (x *VerifyChallengeResponse) GetKeyFingerprint() string {
+	if x != nil {
+		return x.KeyFingerprint
+	}
+	return ""
+}
+
+func (x *VerifyChallengeResponse) GetKeyType() string {
+	if x != nil {
+		return x.KeyType
+	}
+	return ""
+}
+
+func (x *VerifyChallengeResponse) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *VerifyChallengeResponse) GetClaims() *structpb.Struct {
+	if x != nil {
+		return x.Claims
+	}
+	return nil
+}
+
+func (x *VerifyChallengeResponse) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *VerifyChallengeResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *VerifyChallengeResponse) GetVerifiedAt() int64 {
+	if x != nil {
+		return x.VerifiedAt
+	}
+	return 0
+}
+
+func (x *VerifyChallengeResponse) GetDerivationPath() string {
+	if x != nil {
+		return x.DerivationPath
+	}
+	return ""
+}
+
+type VerifyFrostSignatureRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	GroupKey string                 `protobuf:"bytes,1,opt,name=group_key,json=groupKey,proto3" json:"group_key,omitempty"`
+	Nonce    string                 `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// Hex encoded aggregated signature, R then z.
+	Signature     string `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyFrostSignatureRequest) Reset() {
+	*x = VerifyFrostSignatureRequest{}
+	mi := &file_challenge_v1_challenge_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyFrostSignatureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyFrostSignatureRequest) ProtoMessage() {}
+
+func (x *VerifyFrostSignatureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_challenge_v1_challenge_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyFrostSignatureRequest.ProtoReflect.Descriptor instead.
+func (*VerifyFrostSignatureRequest) Descriptor() ([]byte, []int) {
+	return file_challenge_v1_challenge_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VerifyFrostSignatureRequest) GetGroupKey() string {
+	if x != nil {
+		return x.GroupKey
+	}
+	return ""
+}
+
+func (x *VerifyFrostSignatureRequest) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *VerifyFrostSignatureRequest) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+// EnrollExtendedKeyRequest enrolls an xpub or a tpub, whose children at the
+// paths of path_pattern, such as 0/*, sign its challenges. The index of the
+// wildcard is at most max_index.
+type EnrollExtendedKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Xpub          string                 `protobuf:"bytes,1,opt,name=xpub,proto3" json:"xpub,omitempty"`
+	PathPattern   string                 `protobuf:"bytes,2,opt,name=path_pattern,json=pathPattern,proto3" json:"path_pattern,omitempty"`
+	MaxIndex      uint32                 `protobuf:"varint,3,opt,name=max_index,json=maxIndex,proto3" json:"max_index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollExtendedKeyRequest) Reset() {
+	*x = EnrollExtendedKeyRequest{}
+	mi := &file_challenge_v1_challenge_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollExtendedKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollExtendedKeyRequest) ProtoMessage() {}
+
+func (x *EnrollExtendedKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_challenge_v1_challenge_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollExtendedKeyRequest.ProtoReflect.Descriptor instead.
+func (*EnrollExtendedKeyRequest) Descriptor() ([]byte, []int) {
+	return file_challenge_v1_challenge_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *EnrollExtendedKeyRequest) GetXpub() string {
+	if x != nil {
+		return x.Xpub
+	}
+	return ""
+}
+
+func (x *EnrollExtendedKeyRequest) GetPathPattern() string {
+	if x != nil {
+		return x.PathPattern
+	}
+	return ""
+}
+
+func (x *EnrollExtendedKeyRequest) GetMaxIndex() uint32 {
+	if x != nil {
+		return x.MaxIndex
+	}
+	return 0
+}
+
+type EnrollExtendedKeyResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Xpub        string                 `protobuf:"bytes,1,opt,name=xpub,proto3" json:"xpub,omitempty"`
+	PathPattern string                 `protobuf:"bytes,2,opt,name=path_pattern,json=pathPattern,proto3" json:"path_pattern,omitempty"`
+	MaxIndex    uint32                 `protobuf:"varint,3,opt,name=max_index,json=maxIndex,proto3" json:"max_index,omitempty"`
+	// Unix time in seconds.
+	CreatedAt     int64 `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollExtendedKeyResponse) Reset() {
+	*x = EnrollExtendedKeyResponse{}
+	mi := &file_challenge_v1_challenge_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollExtendedKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollExtendedKeyResponse) ProtoMessage() {}
+
+func (x *EnrollExtendedKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_challenge_v1_challenge_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollExtendedKeyResponse.ProtoReflect.Descriptor instead.
+func (*EnrollExtendedKeyResponse) Descriptor() ([]byte, []int) {
+	return file_challenge_v1_challenge_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *EnrollExtendedKeyResponse) GetXpub() string {
+	if x != nil {
+		return x.Xpub
+	}
+	return ""
+}
+
+func (x *EnrollExtendedKeyResponse) GetPathPattern() string {
+	if x != nil {
+		return x.PathPattern
+	}
+	return ""
+}
+
+func (x *EnrollExtendedKeyResponse) GetMaxIndex() uint32 {
+	if x != nil {
+		return x.MaxIndex
+	}
+	return 0
+}
+
+func (x *EnrollExtendedKeyResponse) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type SignMultisigChallengeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Nonce string                 `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// Tokens signed with the private keys of the challenge, one per key.
+	Tokens        []string `protobuf:"bytes,2,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignMultisigChallengeRequest) Reset() {
+	*x = SignMultisigChallengeRequest{}
+	mi := &file_challenge_v1_challenge_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignMultisigChallengeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignMultisigChallengeRequest) ProtoMessage() {}
+
+func (x *SignMultisigChallengeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_challenge_v1_challenge_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignMultisigChallengeRequest.ProtoReflect.Descriptor instead.
+func (*SignMultisigChallengeRequest) Descriptor() ([]byte, []int) {
+	return file_challenge_v1_challenge_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SignMultisigChallengeRequest) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *SignMultisigChallengeRequest) GetTokens() []string {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+type SignMultisigChallengeResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Verified bool                   `protobuf:"varint,1,opt,name=verified,proto3" json:"verified,omitempty"`
+	// Zero when the nonce is unknown.
+	Threshold int32 `protobuf:"varint,2,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	// Public keys that signed the challenge, in the order they did.
+	Signers []string `protobuf:"bytes,3,rep,name=signers,proto3" json:"signers,omitempty"`
+	// Unix time in seconds.
+	ExpiresAt int64 `protobuf:"varint,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Outcome of each token in order, a valid token added its signer.
+	Signatures    []*VerifyChallengeResponse `protobuf:"bytes,5,rep,name=signatures,proto3" json:"signatures,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignMultisigChallengeResponse) Reset() {
+	*x = SignMultisigChallengeResponse{}
+	mi := &file_challenge_v1_challenge_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignMultisigChallengeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignMultisigChallengeResponse) ProtoMessage() {}
+
+func (x *SignMultisigChallengeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_challenge_v1_challenge_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignMultisigChallengeResponse.ProtoReflect.Descriptor instead.
+func (*SignMultisigChallengeResponse) Descriptor() ([]byte, []int) {
+	return file_challenge_v1_challenge_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SignMultisigChallengeResponse) GetVerified() bool {
+	if x != nil {
+		return x.Verified
+	}
+	return false
+}
+
+func (x *SignMultisigChallengeResponse) GetThreshold() int32 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *SignMultisigChallengeResponse) GetSigners() []string {
+	if x != nil {
+		return x.Signers
+	}
+	return nil
+}
+
+func (x *SignMultisigChallengeResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *SignMultisigChallengeResponse) GetSignatures() []*VerifyChallengeResponse {
+	if x != nil {
+		return x.Signatures
+	}
+	return nil
+}
+
+type WatchChallengeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PublicKey     string                 `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Nonce         string                 `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchChallengeRequest) Reset() {
+	*x = WatchChallengeRequest{}
+	mi := &file_challenge_v1_challenge_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchChallengeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchChallengeRequest) ProtoMessage() {}
+
+func (x *WatchChallengeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_challenge_v1_challenge_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchChallengeRequest.ProtoReflect.Descriptor instead.
+func (*WatchChallengeRequest) Descriptor() ([]byte, []int) {
+	return file_challenge_v1_challenge_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WatchChallengeRequest) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *WatchChallengeRequest) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+type WatchChallengeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	State ChallengeState         `protobuf:"varint,1,opt,name=state,proto3,enum=crypto.challenge.v1.ChallengeState" json:"state,omitempty"`
+	// Unix time in seconds, zero when the challenge is not found.
+	ExpiresAt int64 `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Progress of a multi-signature challenge, a response is sent every time a
+	// signer is added.
+	Threshold     int32    `protobuf:"varint,3,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	Signers       []string `protobuf:"bytes,4,rep,name=signers,proto3" json:"signers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchChallengeResponse) Reset() {
+	*x = WatchChallengeResponse{}
+	mi := &file_challenge_v1_challenge_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchChallengeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchChallengeResponse) ProtoMessage() {}
+
+func (x *WatchChallengeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_challenge_v1_challenge_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchChallengeResponse.ProtoReflect.Descriptor instead.
+func (*WatchChallengeResponse) Descriptor() ([]byte, []int) {
+	return file_challenge_v1_challenge_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *WatchChallengeResponse) GetState() ChallengeState {
+	if x != nil {
+		return x.State
+	}
+	return ChallengeState_CHALLENGE_STATE_UNSPECIFIED
+}
+
+func (x *WatchChallengeResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *WatchChallengeResponse) GetThreshold() int32 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *WatchChallengeResponse) GetSigners() []string {
+	if x != nil {
+		return x.Signers
+	}
+	return nil
+}
+
+var File_challenge_v1_challenge_proto protoreflect.FileDescriptor
+
+const file_challenge_v1_challenge_proto_rawDesc = "" +
+	"\n" +
+	"\x1cchallenge/v1/challenge.proto\x12\x13crypto.challenge.v1\x1a\x1cgoogle/protobuf/struct.proto\"\xda\x01\n" +
+	"\tChallenge\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x01 \x01(\tR\tpublicKey\x12\x14\n" +
+	"\x05nonce\x18\x02 \x01(\tR\x05nonce\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\x03R\texpiresAt\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12\x1f\n" +
+	"\vpublic_keys\x18\x05 \x03(\tR\n" +
+	"publicKeys\x12\x1c\n" +
+	"\tthreshold\x18\x06 \x01(\x05R\tthreshold\x12 \n" +
+	"\vciphersuite\x18\a \x01(\tR\vciphersuite\"\x93\x01\n" +
+	"\x16CreateChallengeRequest\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x01 \x01(\tR\tpublicKey\x12\x1f\n" +
+	"\vpublic_keys\x18\x02 \x03(\tR\n" +
+	"publicKeys\x12\x1c\n" +
+	"\tthreshold\x18\x03 \x01(\x05R\tthreshold\x12\x1b\n" +
+	"\tgroup_key\x18\x04 \x01(\tR\bgroupKey\"W\n" +
+	"\x17CreateChallengeResponse\x12<\n" +
+	"\tchallenge\x18\x01 \x01(\v2\x1e.crypto.challenge.v1.ChallengeR\tchallenge\".\n" +
+	"\x16VerifyChallengeRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"\xf5\x02\n" +
+	"\x17VerifyChallengeResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12)\n" +
+	"\x10validation_error\x18\x02 \x01(\tR\x0fvalidationError\x12'\n" +
+	"\x0fkey_fingerprint\x18\x03 \x01(\tR\x0ekeyFingerprint\x12\x19\n" +
+	"\bkey_type\x18\x04 \x01(\tR\akeyType\x12\x1c\n" +
+	"\talgorithm\x18\x05 \x01(\tR\talgorithm\x12/\n" +
+	"\x06claims\x18\x06 \x01(\v2\x17.google.protobuf.StructR\x06claims\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\b \x01(\x03R\texpiresAt\x12\x1f\n" +
+	"\vverified_at\x18\t \x01(\x03R\n" +
+	"verifiedAt\x12'\n" +
+	"\x0fderivation_path\x18\n" +
+	" \x01(\tR\x0ederivationPath\"n\n" +
+	"\x1bVerifyFrostSignatureRequest\x12\x1b\n" +
+	"\tgroup_key\x18\x01 \x01(\tR\bgroupKey\x12\x14\n" +
+	"\x05nonce\x18\x02 \x01(\tR\x05nonce\x12\x1c\n" +
+	"\tsignature\x18\x03 \x01(\tR\tsignature\"n\n" +
+	"\x18EnrollExtendedKeyRequest\x12\x12\n" +
+	"\x04xpub\x18\x01 \x01(\tR\x04xpub\x12!\n" +
+	"\fpath_pattern\x18\x02 \x01(\tR\vpathPattern\x12\x1b\n" +
+	"\tmax_index\x18\x03 \x01(\rR\bmaxIndex\"\x8e\x01\n" +
+	"\x19EnrollExtendedKeyResponse\x12\x12\n" +
+	"\x04xpub\x18\x01 \x01(\tR\x04xpub\x12!\n" +
+	"\fpath_pattern\x18\x02 \x01(\tR\vpathPattern\x12\x1b\n" +
+	"\tmax_index\x18\x03 \x01(\rR\bmaxIndex\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\x03R\tcreatedAt\"L\n" +
+	"\x1cSignMultisigChallengeRequest\x12\x14\n" +
+	"\x05nonce\x18\x01 \x01(\tR\x05nonce\x12\x16\n" +
+	"\x06tokens\x18\x02 \x03(\tR\x06tokens\"\xe0\x01\n" +
+	"\x1dSignMultisigChallengeResponse\x12\x1a\n" +
+	"\bverified\x18\x01 \x01(\bR\bverified\x12\x1c\n" +
+	"\tthreshold\x18\x02 \x01(\x05R\tthreshold\x12\x18\n" +
+	"\asigners\x18\x03 \x03(\tR\asigners\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\x03R\texpiresAt\x12L\n" +
+	"\n" +
+	"signatures\x18\x05 \x03(\v2,.crypto.challenge.v1.VerifyChallengeResponseR\n" +
+	"signatures\"L\n" +
+	"\x15WatchChallengeRequest\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x01 \x01(\tR\tpublicKey\x12\x14\n" +
+	"\x05nonce\x18\x02 \x01(\tR\x05nonce\"\xaa\x01\n" +
+	"\x16WatchChallengeResponse\x129\n" +
+	"\x05state\x18\x01 \x01(\x0e2#.crypto.challenge.v1.ChallengeStateR\x05state\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\x12\x1c\n" +
+	"\tthreshold\x18\x03 \x01(\x05R\tthreshold\x12\x18\n" +
+	"\asigners\x18\x04 \x03(\tR\asigners*\xa8\x01\n" +
+	"\x0eChallengeState\x12\x1f\n" +
+	"\x1bCHALLENGE_STATE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17CHALLENGE_STATE_PENDING\x10\x01\x12\x1b\n" +
+	"\x17CHALLENGE_STATE_EXPIRED\x10\x02\x12\x1d\n" +
+	"\x19CHALLENGE_STATE_NOT_FOUND\x10\x03\x12\x1c\n" +
+	"\x18CHALLENGE_STATE_VERIFIED\x10\x042\xc7\x05\n" +
+	"\x10ChallengeService\x12l\n" +
+	"\x0fCreateChallenge\x12+.crypto.challenge.v1.CreateChallengeRequest\x1a,.crypto.challenge.v1.CreateChallengeResponse\x12l\n" +
+	"\x0fVerifyChallenge\x12+.crypto.challenge.v1.VerifyChallengeRequest\x1a,.crypto.challenge.v1.VerifyChallengeResponse\x12~\n" +
+	"\x15SignMultisigChallenge\x121.crypto.challenge.v1.SignMultisigChallengeRequest\x1a2.crypto.challenge.v1.SignMultisigChallengeResponse\x12v\n" +
+	"\x14VerifyFrostSignature\x120.crypto.challenge.v1.VerifyFrostSignatureRequest\x1a,.crypto.challenge.v1.VerifyChallengeResponse\x12r\n" +
+	"\x11EnrollExtendedKey\x12-.crypto.challenge.v1.EnrollExtendedKeyRequest\x1a..crypto.challenge.v1.EnrollExtendedKeyResponse\x12k\n" +
+	"\x0eWatchChallenge\x12*.crypto.challenge.v1.WatchChallengeRequest\x1a+.crypto.challenge.v1.WatchChallengeResponse0\x01B8Z6crypto-project-1/public/proto/challenge/v1;challengev1b\x06proto3"
+
+var (
+	file_challenge_v1_challenge_proto_rawDescOnce sync.Once
+	file_challenge_v1_challenge_proto_rawDescData []byte
+)
+
+func file_challenge_v1_challenge_proto_rawDescGZIP() []byte {
+	file_challenge_v1_challenge_proto_rawDescOnce.Do(func() {
+		file_challenge_v1_challenge_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_challenge_v1_challenge_proto_rawDesc), len(file_challenge_v1_challenge_proto_rawDesc)))
+	})
+	return file_challenge_v1_challenge_proto_rawDescData
+}
+
+var file_challenge_v1_challenge_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_challenge_v1_challenge_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_challenge_v1_challenge_proto_goTypes = []any{
+	(ChallengeState)(0),                   // 0: crypto.challenge.v1.ChallengeState
+	(*Challenge)(nil),                     // 1: crypto.challenge.v1.Challenge
+	(*CreateChallengeRequest)(nil),        // 2: crypto.challenge.v1.CreateChallengeRequest
+	(*CreateChallengeResponse)(nil),       // 3: crypto.challenge.v1.CreateChallengeResponse
+	(*VerifyChallengeRequest)(nil),        // 4: crypto.challenge.v1.VerifyChallengeRequest
+	(*VerifyChallengeResponse)(nil),       // 5: crypto.challenge.v1.VerifyChallengeResponse
+	(*VerifyFrostSignatureRequest)(nil),   // 6: crypto.challenge.v1.VerifyFrostSignatureRequest
+	(*EnrollExtendedKeyRequest)(nil),      // 7: crypto.challenge.v1.EnrollExtendedKeyRequest
+	(*EnrollExtendedKeyResponse)(nil),     // 8: crypto.challenge.v1.EnrollExtendedKeyResponse
+	(*SignMultisigChallengeRequest)(nil),  // 9: crypto.challenge.v1.SignMultisigChallengeRequest
+	(*SignMultisigChallengeResponse)(nil), // 10: crypto.challenge.v1.SignMultisigChallengeResponse
+	(*WatchChallengeRequest)(nil),         // 11: crypto.challenge.v1.WatchChallengeRequest
+	(*WatchChallengeResponse)(nil),        // 12: crypto.challenge.v1.WatchChallengeResponse
+	(*structpb.Struct)(nil),               // 13: google.protobuf.Struct
+}
+var file_challenge_v1_challenge_proto_depIdxs = []int32{
+	1,  // 0: crypto.challenge.v1.CreateChallengeResponse.challenge:type_name -> crypto.challenge.v1.Challenge
+	13, // 1: crypto.challenge.v1.VerifyChallengeResponse.claims:type_name -> google.protobuf.Struct
+	5,  // 2: crypto.challenge.v1.SignMultisigChallengeResponse.signatures:type_name -> crypto.challenge.v1.VerifyChallengeResponse
+	0,  // 3: crypto.challenge.v1.WatchChallengeResponse.state:type_name -> crypto.challenge.v1.ChallengeState
+	2,  // 4: crypto.challenge.v1.ChallengeService.CreateChallenge:input_type -> crypto.challenge.v1.CreateChallengeRequest
+	4,  // 5: crypto.challenge.v1.ChallengeService.VerifyChallenge:input_type -> crypto.challenge.v1.VerifyChallengeRequest
+	9,  // 6: crypto.challenge.v1.ChallengeService.SignMultisigChallenge:input_type -> crypto.challenge.v1.SignMultisigChallengeRequest
+	6,  // 7: crypto.challenge.v1.ChallengeService.VerifyFrostSignature:input_type -> crypto.challenge.v1.VerifyFrostSignatureRequest
+	7,  // 8: crypto.challenge.v1.ChallengeService.EnrollExtendedKey:input_type -> crypto.challenge.v1.EnrollExtendedKeyRequest
+	11, // 9: crypto.challenge.v1.ChallengeService.WatchChallenge:input_type -> crypto.challenge.v1.WatchChallengeRequest
+	3,  // 10: crypto.challenge.v1.ChallengeService.CreateChallenge:output_type -> crypto.challenge.v1.CreateChallengeResponse
+	5,  // 11: crypto.challenge.v1.ChallengeService.VerifyChallenge:output_type -> crypto.challenge.v1.VerifyChallengeResponse
+	10, // 12: crypto.challenge.v1.ChallengeService.SignMultisigChallenge:output_type -> crypto.challenge.v1.SignMultisigChallengeResponse
+	5,  // 13: crypto.challenge.v1.ChallengeService.VerifyFrostSignature:output_type -> crypto.challenge.v1.VerifyChallengeResponse
+	8,  // 14: crypto.challenge.v1.ChallengeService.EnrollExtendedKey:output_type -> crypto.challenge.v1.EnrollExtendedKeyResponse
+	12, // 15: crypto.challenge.v1.ChallengeService.WatchChallenge:output_type -> crypto.challenge.v1.WatchChallengeResponse
+	10, // [10:16] is the sub-list for method output_type
+	4,  // [4:10] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_challenge_v1_challenge_proto_init() }
+func file_challenge_v1_challenge_proto_init() {
+	if File_challenge_v1_challenge_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_challenge_v1_challenge_proto_rawDesc), len(file_challenge_v1_challenge_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_challenge_v1_challenge_proto_goTypes,
+		DependencyIndexes: file_challenge_v1_challenge_proto_depIdxs,
+		EnumInfos:         file_challenge_v1_challenge_proto_enumTypes,
+		MessageInfos:      file_challenge_v1_challenge_proto_msgTypes,
+	}.Build()
+	File_challenge_v1_challenge_proto = out.File
+	file_challenge_v1_challenge_proto_goTypes = nil
+	file_challenge_v1_challenge_proto_depIdxs = nil
+}
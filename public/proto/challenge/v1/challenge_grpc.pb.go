@@ -0,0 +1,343 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: challenge/v1/challenge.proto
+
+package challengev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ChallengeService_CreateChallenge_FullMethodName       = "/crypto.challenge.v1.ChallengeService/CreateChallenge"
+	ChallengeService_VerifyChallenge_FullMethodName       = "/crypto.challenge.v1.ChallengeService/VerifyChallenge"
+	ChallengeService_SignMultisigChallenge_FullMethodName = "/crypto.challenge.v1.ChallengeService/SignMultisigChallenge"
+	ChallengeService_VerifyFrostSignature_FullMethodName  = "/crypto.challenge.v1.ChallengeService/VerifyFrostSignature"
+	ChallengeService_EnrollExtendedKey_FullMethodName     = "/crypto.challenge.v1.ChallengeService/EnrollExtendedKey"
+	ChallengeService_WatchChallenge_FullMethodName        = "/crypto.challenge.v1.ChallengeService/WatchChallenge"
+)
+
+// ChallengeServiceClient is the client API for ChallengeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ChallengeService creates challenges (nonces) bound to a public key and
+// verifies tokens signed with the matching private key.
+type ChallengeServiceClient interface {
+	// CreateChallenge creates a nonce for the compressed public key.
+	CreateChallenge(ctx context.Context, in *CreateChallengeRequest, opts ...grpc.CallOption) (*CreateChallengeResponse, error)
+	// VerifyChallenge verifies a JWT signed with the private key whose jti claim is the nonce.
+	VerifyChallenge(ctx context.Context, in *VerifyChallengeRequest, opts ...grpc.CallOption) (*VerifyChallengeResponse, error)
+	// SignMultisigChallenge adds the signers of the tokens to a multi-signature
+	// challenge, which is verified once it has its threshold of signers. A
+	// public key signs a challenge once.
+	SignMultisigChallenge(ctx context.Context, in *SignMultisigChallengeRequest, opts ...grpc.CallOption) (*SignMultisigChallengeResponse, error)
+	// VerifyFrostSignature verifies the aggregated signature of a FROST group
+	// of the message of a threshold challenge, or of its nonce when it has none.
+	VerifyFrostSignature(ctx context.Context, in *VerifyFrostSignatureRequest, opts ...grpc.CallOption) (*VerifyChallengeResponse, error)
+	// EnrollExtendedKey enrolls a BIP-32 extended public key, whose
+	// non-hardened children sign the challenges of the key.
+	EnrollExtendedKey(ctx context.Context, in *EnrollExtendedKeyRequest, opts ...grpc.CallOption) (*EnrollExtendedKeyResponse, error)
+	// WatchChallenge streams the state of a challenge every time it changes. The
+	// stream ends once the challenge reaches a final state.
+	WatchChallenge(ctx context.Context, in *WatchChallengeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchChallengeResponse], error)
+}
+
+type challengeServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewChallengeServiceClient(cc grpc.ClientConnInterface) ChallengeServiceClient {
+	return &challengeServiceClient{cc}
+}
+
+func (c *challengeServiceClient) CreateChallenge(ctx context.Context, in *CreateChallengeRequest, opts ...grpc.CallOption) (*CreateChallengeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateChallengeResponse)
+	err := c.cc.Invoke(ctx, ChallengeService_CreateChallenge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *challengeServiceClient) VerifyChallenge(ctx context.Context, in *VerifyChallengeRequest, opts ...grpc.CallOption) (*VerifyChallengeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyChallengeResponse)
+	err := c.cc.Invoke(ctx, ChallengeService_VerifyChallenge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *challengeServiceClient) SignMultisigChallenge(ctx context.Context, in *SignMultisigChallengeRequest, opts ...grpc.CallOption) (*SignMultisigChallengeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SignMultisigChallengeResponse)
+	err := c.cc.Invoke(ctx, ChallengeService_SignMultisigChallenge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *challengeServiceClient) VerifyFrostSignature(ctx context.Context, in *VerifyFrostSignatureRequest, opts ...grpc.CallOption) (*VerifyChallengeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyChallengeResponse)
+	err := c.cc.Invoke(ctx, ChallengeService_VerifyFrostSignature_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *challengeServiceClient) EnrollExtendedKey(ctx context.Context, in *EnrollExtendedKeyRequest, opts ...grpc.CallOption) (*EnrollExtendedKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnrollExtendedKeyResponse)
+	err := c.cc.Invoke(ctx, ChallengeService_EnrollExtendedKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *challengeServiceClient) WatchChallenge(ctx context.Context, in *WatchChallengeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchChallengeResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ChallengeService_ServiceDesc.Streams[0], ChallengeService_WatchChallenge_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchChallengeRequest, WatchChallengeResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChallengeService_WatchChallengeClient = grpc.ServerStreamingClient[WatchChallengeResponse]
+
+// ChallengeServiceServer is the server API for ChallengeService service.
+// All implementations must embed UnimplementedChallengeServiceServer
+// for forward compatibility.
+//
+// ChallengeService creates challenges (nonces) bound to a public key and
+// verifies tokens signed with the matching private key.
+type ChallengeServiceServer interface {
+	// CreateChallenge creates a nonce for the compressed public key.
+	CreateChallenge(context.Context, *CreateChallengeRequest) (*CreateChallengeResponse, error)
+	// VerifyChallenge verifies a JWT signed with the private key whose jti claim is the nonce.
+	VerifyChallenge(context.Context, *VerifyChallengeRequest) (*VerifyChallengeResponse, error)
+	// SignMultisigChallenge adds the signers of the tokens to a multi-signature
+	// challenge, which is verified once it has its threshold of signers. A
+	// public key signs a challenge once.
+	SignMultisigChallenge(context.Context, *SignMultisigChallengeRequest) (*SignMultisigChallengeResponse, error)
+	// VerifyFrostSignature verifies the aggregated signature of a FROST group
+	// of the message of a threshold challenge, or of its nonce when it has none.
+	VerifyFrostSignature(context.Context, *VerifyFrostSignatureRequest) (*VerifyChallengeResponse, error)
+	// EnrollExtendedKey enrolls a BIP-32 extended public key, whose
+	// non-hardened children sign the challenges of the key.
+	EnrollExtendedKey(context.Context, *EnrollExtendedKeyRequest) (*EnrollExtendedKeyResponse, error)
+	// WatchChallenge streams the state of a challenge every time it changes. The
+	// stream ends once the challenge reaches a final state.
+	WatchChallenge(*WatchChallengeRequest, grpc.ServerStreamingServer[WatchChallengeResponse]) error
+	mustEmbedUnimplementedChallengeServiceServer()
+}
+
+// UnimplementedChallengeServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedChallengeServiceServer struct{}
+
+func (UnimplementedChallengeServiceServer) CreateChallenge(context.Context, *CreateChallengeRequest) (*CreateChallengeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateChallenge not implemented")
+}
+func (UnimplementedChallengeServiceServer) VerifyChallenge(context.Context, *VerifyChallengeRequest) (*VerifyChallengeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyChallenge not implemented")
+}
+func (UnimplementedChallengeServiceServer) SignMultisigChallenge(context.Context, *SignMultisigChallengeRequest) (*SignMultisigChallengeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignMultisigChallenge not implemented")
+}
+func (UnimplementedChallengeServiceServer) VerifyFrostSignature(context.Context, *VerifyFrostSignatureRequest) (*VerifyChallengeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyFrostSignature not implemented")
+}
+func (UnimplementedChallengeServiceServer) EnrollExtendedKey(context.Context, *EnrollExtendedKeyRequest) (*EnrollExtendedKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollExtendedKey not implemented")
+}
+func (UnimplementedChallengeServiceServer) WatchChallenge(*WatchChallengeRequest, grpc.ServerStreamingServer[WatchChallengeResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchChallenge not implemented")
+}
+func (UnimplementedChallengeServiceServer) mustEmbedUnimplementedChallengeServiceServer() {}
+func (UnimplementedChallengeServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeChallengeServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ChallengeServiceServer will
+// result in compilation errors.
+type UnsafeChallengeServiceServer interface {
+	mustEmbedUnimplementedChallengeServiceServer()
+}
+
+func RegisterChallengeServiceServer(s grpc.ServiceRegistrar, srv ChallengeServiceServer) {
+	// If the following call pancis, it indicates UnimplementedChallengeServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ChallengeService_ServiceDesc, srv)
+}
+
+func _ChallengeService_CreateChallenge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateChallengeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChallengeServiceServer).CreateChallenge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChallengeService_CreateChallenge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChallengeServiceServer).CreateChallenge(ctx, req.(*CreateChallengeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChallengeService_VerifyChallenge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyChallengeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChallengeServiceServer).VerifyChallenge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChallengeService_VerifyChallenge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChallengeServiceServer).VerifyChallenge(ctx, req.(*VerifyChallengeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChallengeService_SignMultisigChallenge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignMultisigChallengeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChallengeServiceServer).SignMultisigChallenge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChallengeService_SignMultisigChallenge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChallengeServiceServer).SignMultisigChallenge(ctx, req.(*SignMultisigChallengeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChallengeService_VerifyFrostSignature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyFrostSignatureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChallengeServiceServer).VerifyFrostSignature(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChallengeService_VerifyFrostSignature_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChallengeServiceServer).VerifyFrostSignature(ctx, req.(*VerifyFrostSignatureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChallengeService_EnrollExtendedKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollExtendedKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChallengeServiceServer).EnrollExtendedKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChallengeService_EnrollExtendedKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChallengeServiceServer).EnrollExtendedKey(ctx, req.(*EnrollExtendedKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChallengeService_WatchChallenge_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchChallengeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChallengeServiceServer).WatchChallenge(m, &grpc.GenericServerStream[WatchChallengeRequest, WatchChallengeResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChallengeService_WatchChallengeServer = grpc.ServerStreamingServer[WatchChallengeResponse]
+
+// ChallengeService_ServiceDesc is the grpc.ServiceDesc for ChallengeService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ChallengeService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "crypto.challenge.v1.ChallengeService",
+	HandlerType: (*ChallengeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateChallenge",
+			Handler:    _ChallengeService_CreateChallenge_Handler,
+		},
+		{
+			MethodName: "VerifyChallenge",
+			Handler:    _ChallengeService_VerifyChallenge_Handler,
+		},
+		{
+			MethodName: "SignMultisigChallenge",
+			Handler:    _ChallengeService_SignMultisigChallenge_Handler,
+		},
+		{
+			MethodName: "VerifyFrostSignature",
+			Handler:    _ChallengeService_VerifyFrostSignature_Handler,
+		},
+		{
+			MethodName: "EnrollExtendedKey",
+			Handler:    _ChallengeService_EnrollExtendedKey_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchChallenge",
+			Handler:       _ChallengeService_WatchChallenge_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "challenge/v1/challenge.proto",
+}
@@ -1,9 +1,49 @@
 package public
 
+// CreateChallengeRequestBody asks for a challenge of PubKey, for a
+// multi-signature challenge that Threshold of PubKeys must sign, or for a
+// threshold challenge of the GroupKey of a FROST group.
 type CreateChallengeRequestBody struct {
-	PubKey string `json:"pubKey"`
+	PubKey    string   `json:"pubKey,omitempty"`
+	PubKeys   []string `json:"pubKeys,omitempty"`
+	Threshold int      `json:"threshold,omitempty"`
+	GroupKey  string   `json:"groupKey,omitempty"`
 }
 
 type VerifyChallengeRequestBody struct {
 	Token string `json:"token"`
 }
+
+// VerifyFrostRequestBody submits the hex of the aggregated signature of the
+// challenge of GroupKey and Nonce.
+type VerifyFrostRequestBody struct {
+	GroupKey  string `json:"groupKey"`
+	Nonce     string `json:"nonce"`
+	Signature string `json:"signature"`
+}
+
+// SignMultisigRequestBody submits tokens signing the multi-signature
+// challenge of Nonce, one per public key.
+type SignMultisigRequestBody struct {
+	Nonce  string   `json:"nonce"`
+	Tokens []string `json:"tokens"`
+}
+
+// EnrollExtendedKeyRequestBody enrolls the BIP-32 extended public key Xpub,
+// whose children at the paths of PathPattern, such as 0/*, sign its
+// challenges. The index of the wildcard is at most MaxIndex.
+type EnrollExtendedKeyRequestBody struct {
+	Xpub        string `json:"xpub"`
+	PathPattern string `json:"pathPattern"`
+	MaxIndex    uint32 `json:"maxIndex"`
+}
+
+type ChallengeStatusRequestBody struct {
+	PubKey string `json:"pubKey"`
+	Nonce  string `json:"nonce"`
+}
+
+type CreateApiKeyRequestBody struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}